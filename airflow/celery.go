@@ -0,0 +1,69 @@
+package airflow
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/astronomer/astro-cli/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ExecutorCelery runs the tasks on Celery workers, one for each queue set in dev.celery_queues as
+	// <queue>[:<concurrency>]
+	ExecutorCelery = "celery"
+
+	celeryExecutorClass = "CeleryExecutor"
+	celeryWorkerPrefix  = "worker-"
+	redisImage          = "docker.io/redis:7.2-bookworm"
+)
+
+var (
+	errNoCeleryQueues = errors.New("no queues found in dev.celery_queues, set a comma separated list of queues with 'astro config set dev.celery_queues'")
+
+	celeryQueueRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+)
+
+// celeryWorker is a Celery worker container consuming the tasks of a single queue, with the default concurrency of
+// Airflow when Concurrency is 0
+type celeryWorker struct {
+	ServiceName string
+	Queue       string
+	Concurrency int
+}
+
+// celeryExecutorEnabled checks whether the project runs its tasks on Celery workers
+func celeryExecutorEnabled() bool {
+	return config.CFG.DevExecutor.GetString() == ExecutorCelery
+}
+
+// celeryWorkers returns a worker for each queue set in dev.celery_queues, the first queue is the default queue of
+// the tasks without a queue
+func celeryWorkers() ([]celeryWorker, error) {
+	var workers []celeryWorker
+	seen := map[string]bool{}
+	for _, spec := range strings.Split(config.CFG.DevCeleryQueues.GetString(), ",") {
+		queue, concurrency, hasConcurrency := strings.Cut(strings.TrimSpace(spec), ":")
+		if queue == "" || seen[queue] {
+			continue
+		}
+		if !celeryQueueRegex.MatchString(queue) {
+			return nil, errors.Errorf("invalid queue %q in dev.celery_queues, a queue name can only contain lowercase alphanumeric characters, '-', '_' and '.'", queue)
+		}
+		worker := celeryWorker{ServiceName: celeryWorkerPrefix + queue, Queue: queue}
+		if hasConcurrency {
+			n, err := strconv.Atoi(concurrency)
+			if err != nil || n < 1 {
+				return nil, errors.Errorf("invalid concurrency %q of queue %q in dev.celery_queues, the concurrency must be a positive number", concurrency, queue)
+			}
+			worker.Concurrency = n
+		}
+		seen[queue] = true
+		workers = append(workers, worker)
+	}
+	if len(workers) == 0 {
+		return nil, errNoCeleryQueues
+	}
+	return workers, nil
+}
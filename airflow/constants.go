@@ -20,6 +20,8 @@ const (
 	APIServerDockerContainerName    = "api-server"
 	PostgresDockerContainerName     = "postgres"
 	DAGProcessorDockerContainerName = "dag-processor"
+	VaultDockerContainerName        = "vault"
+	RedisDockerContainerName        = "redis"
 )
 
 var (
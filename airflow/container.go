@@ -18,6 +18,7 @@ import (
 	"github.com/astronomer/astro-cli/pkg/fileutil"
 	"github.com/astronomer/astro-cli/pkg/logger"
 	"github.com/astronomer/astro-cli/pkg/util"
+	"github.com/astronomer/astro-cli/settings"
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/docker/client"
 	"github.com/pkg/errors"
@@ -26,15 +27,20 @@ import (
 type ContainerHandler interface {
 	Start(imageName, settingsFile, composeFile, buildSecretString string, noCache, noBrowser bool, waitTime time.Duration, envConns map[string]astrocore.EnvironmentObjectConnection) error
 	Stop(waitForExit bool) error
-	PS() error
+	Watch(settingsFile, composeFile, buildSecretString string) error
+	PS(outputFormat string) error
+	List() error
 	Kill() error
 	Logs(follow bool, containerNames ...string) error
 	Run(args []string, user string) error
 	Bash(container string) error
 	RunDAG(dagID, settingsFile, dagFile, executionDate string, noCache, taskLogs bool) error
-	ImportSettings(settingsFile, envFile string, connections, variables, pools bool) error
+	ImportSettings(settingsFile, envFile string, connections, variables, pools, prune bool) error
 	ExportSettings(settingsFile, envFile string, connections, variables, pools, envExport bool) error
 	ComposeExport(settingsFile, composeFile string) error
+	SaveSnapshot(name string) error
+	RestoreSnapshot(name string) error
+	ListSnapshots() error
 	Pytest(pytestFile, customImageName, deployImageName, pytestArgsString, buildSecretString string) (string, error)
 	Parse(customImageName, deployImageName, buildSecretString string) error
 	UpgradeTest(runtimeVersion, deploymentID, customImageName, buildSecretString string, versionTest, dagTest, lintTest, includeLintDeprecations bool, lintConfigFile string, astroPlatformCore astroplatformcore.ClientWithResponsesInterface) error
@@ -163,6 +169,11 @@ func generateConfig(projectName, airflowHome, envFile, buildImage, settingsFile
 		logger.Debug(err)
 	}
 
+	secretsBackend, err := localSecretsBackend(settingsFile)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read secrets backend")
+	}
+
 	cfg := ComposeConfig{
 		PostgresUser:          config.CFG.PostgresUser.GetString(),
 		PostgresPassword:      config.CFG.PostgresPassword.GetString(),
@@ -170,6 +181,7 @@ func generateConfig(projectName, airflowHome, envFile, buildImage, settingsFile
 		PostgresPort:          config.CFG.PostgresPort.GetString(),
 		PostgresRepository:    config.CFG.PostgresRepository.GetString(),
 		PostgresTag:           config.CFG.PostgresTag.GetString(),
+		FernetKey:             localFernetKey,
 		AirflowImage:          airflowImage,
 		AirflowHome:           airflowHome,
 		AirflowUser:           "astro",
@@ -183,6 +195,44 @@ func generateConfig(projectName, airflowHome, envFile, buildImage, settingsFile
 		TriggererEnabled:      triggererEnabled,
 		DuplicateImageVolumes: config.CFG.DuplicateImageVolumes.GetBool(),
 		ProjectName:           projectName,
+		Executor:              localExecutorClass,
+		SchedulerCount:        config.CFG.DevSchedulerCount.GetInt(),
+	}
+
+	if secretsBackend != nil {
+		backendCfg, err := getSecretsBackendConfig(airflowHome, secretsBackend)
+		if err != nil {
+			return "", err
+		}
+		cfg.SecretsBackend = backendCfg.Backend
+		// the kwargs are JSON, which must not be HTML escaped by the template
+		cfg.SecretsBackendKwargs = template.HTML(backendCfg.BackendKwargs) //nolint:gosec
+		cfg.ExtraVolumes = backendCfg.Volumes
+		cfg.VaultEnabled = secretsBackend.Type == settings.SecretsBackendVault
+		cfg.VaultImage = backendCfg.VaultImage
+		cfg.VaultToken = backendCfg.VaultToken
+		cfg.VaultPort = config.CFG.VaultPort.GetString()
+	}
+
+	if kubernetesExecutorEnabled() {
+		cluster, err := newLocalCluster(projectName)
+		if err != nil {
+			return "", err
+		}
+		cfg.Executor = kubernetesExecutorClass
+		cfg.KubernetesNetwork = cluster.Network()
+		cfg.KubernetesPostgresAlias = kubernetesHostAlias(projectName, PostgresDockerContainerName)
+		cfg.KubernetesAPIServerAlias = kubernetesHostAlias(projectName, APIServerDockerContainerName)
+		cfg.ExtraVolumes = append(cfg.ExtraVolumes, fmt.Sprintf("%s/%s:%s/%s", airflowHome, kubernetesDirectory, containerAirflowHome, kubernetesDirectory))
+	}
+
+	if celeryExecutorEnabled() {
+		cfg.CeleryWorkers, err = celeryWorkers()
+		if err != nil {
+			return "", err
+		}
+		cfg.Executor = celeryExecutorClass
+		cfg.RedisImage = redisImage
 	}
 
 	buff := new(bytes.Buffer)
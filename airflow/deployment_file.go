@@ -0,0 +1,163 @@
+package airflow
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	airflowversions "github.com/astronomer/astro-cli/airflow_versions"
+	"github.com/astronomer/astro-cli/cloud/deployment"
+	"github.com/astronomer/astro-cli/cloud/deployment/inspect"
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/docker"
+	"github.com/astronomer/astro-cli/pkg/fileutil"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// deploymentEnvFile is the env file generated from a deployment file, relative to the project
+const deploymentEnvFile = ".astro/deployment.env"
+
+var (
+	errEmptyDeploymentFile = errors.New("the deployment file has no content")
+
+	// runtimeTagSuffixRegex matches the image variant suffixes of a Runtime image tag
+	runtimeTagSuffixRegex = regexp.MustCompile(`(-python-[0-9.]+)?(-base)?$`)
+)
+
+// ConfigureFromDeploymentFile configures the local environment of a project like the Deployment of a deployment file
+// would be created by 'astro deployment create --deployment-file'. It checks the Runtime version of the Dockerfile,
+// saves the executor, the worker queues and the scheduler count to the project config and returns an env file
+// holding the environment variables of the Deployment followed by the ones of envFile, so local values win.
+func ConfigureFromDeploymentFile(deploymentFile, airflowHome, envFile, dockerfile string, out io.Writer) (string, error) {
+	dataBytes, err := os.ReadFile(deploymentFile)
+	if err != nil {
+		return "", err
+	}
+	if len(dataBytes) == 0 {
+		return "", errEmptyDeploymentFile
+	}
+	var formattedDeployment inspect.FormattedDeployment
+	err = yaml.Unmarshal(dataBytes, &formattedDeployment)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse deployment file %s", deploymentFile)
+	}
+	deploymentConfig := formattedDeployment.Deployment.Configuration
+
+	err = checkDeploymentRuntimeVersion(deploymentConfig.RunTimeVersion, filepath.Join(airflowHome, dockerfile))
+	if err != nil {
+		return "", err
+	}
+
+	var queues []string
+	for _, queue := range formattedDeployment.Deployment.WorkerQs {
+		spec := queue.Name
+		if queue.WorkerConcurrency > 0 {
+			spec += ":" + strconv.Itoa(queue.WorkerConcurrency)
+		}
+		queues = append(queues, spec)
+	}
+	switch {
+	case strings.EqualFold(deploymentConfig.Executor, deployment.CeleryExecutor) || strings.EqualFold(deploymentConfig.Executor, deployment.CELERY):
+		err = config.CFG.DevExecutor.SetProjectString(ExecutorCelery)
+		if err != nil {
+			return "", err
+		}
+		if len(queues) > 0 {
+			err = config.CFG.DevCeleryQueues.SetProjectString(strings.Join(queues, ","))
+			if err != nil {
+				return "", err
+			}
+		}
+		fmt.Fprintf(out, "Using the Celery executor with the worker queues: %s\n", config.CFG.DevCeleryQueues.GetString())
+	case strings.EqualFold(deploymentConfig.Executor, deployment.KubeExecutor) || strings.EqualFold(deploymentConfig.Executor, deployment.KUBERNETES):
+		err = config.CFG.DevExecutor.SetProjectString(ExecutorKubernetes)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintln(out, "Using the Kubernetes executor, the worker queues of the deployment file are not supported locally")
+	default:
+		// the Local and Astro executors run the tasks in the scheduler locally, replacing the executor of a previous run
+		err = config.CFG.DevExecutor.SetProjectString(ExecutorLocal)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintln(out, "Using the local executor")
+	}
+
+	if deploymentConfig.SchedulerCount > 0 {
+		err = config.CFG.DevSchedulerCount.SetProjectString(strconv.Itoa(deploymentConfig.SchedulerCount))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return writeDeploymentEnvFile(formattedDeployment.Deployment.EnvVars, airflowHome, envFile, out)
+}
+
+// checkDeploymentRuntimeVersion checks the Dockerfile uses the Runtime version of the deployment file
+func checkDeploymentRuntimeVersion(runtimeVersion, dockerfilePath string) error {
+	if runtimeVersion == "" {
+		return nil
+	}
+	cmds, err := docker.ParseFile(dockerfilePath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse dockerfile: %s", dockerfilePath)
+	}
+	_, tag := docker.GetImageTagFromParsedFile(cmds)
+	tag = RuntimeVersionFromImageTag(tag)
+	if airflowversions.CompareRuntimeVersions(tag, runtimeVersion) != 0 {
+		return errors.Errorf("the Dockerfile uses the image tag %s but the deployment file uses Runtime %s, update the FROM line of %s to match", tag, runtimeVersion, dockerfilePath)
+	}
+	return nil
+}
+
+// RuntimeVersionFromImageTag returns the Runtime version of a Runtime image tag, without its image variant suffixes
+func RuntimeVersionFromImageTag(tag string) string {
+	return runtimeTagSuffixRegex.ReplaceAllString(tag, "")
+}
+
+// writeDeploymentEnvFile writes the environment variables of a deployment file followed by the ones of envFile,
+// skipping the secret variables a deployment file has no value for
+func writeDeploymentEnvFile(envVars []inspect.EnvironmentVariable, airflowHome, envFile string, out io.Writer) (string, error) {
+	buff := new(bytes.Buffer)
+	for _, envVar := range envVars {
+		if envVar.Value == nil {
+			fmt.Fprintf(out, "Skipping the secret environment variable %s, set its value in %s to use it locally\n", envVar.Key, envFile)
+			continue
+		}
+		fmt.Fprintf(buff, "%s=%s\n", envVar.Key, *envVar.Value)
+	}
+
+	if envFile != "" {
+		envBytes, err := os.ReadFile(envFile)
+		if err != nil && !os.IsNotExist(err) {
+			return "", errors.Wrapf(err, envPathMsg, envFile)
+		}
+		buff.Write(envBytes)
+	}
+
+	path := filepath.Join(airflowHome, deploymentEnvFile)
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(path, buff.Bytes(), 0o600) //nolint:mnd
+	if err != nil {
+		return "", err
+	}
+
+	// the env file may hold the local values of secrets, keep it out of git and out of the image
+	for _, ignoreFile := range []string{".gitignore", ".dockerignore"} {
+		err = fileutil.AddLineToFile(filepath.Join(airflowHome, ignoreFile), deploymentEnvFile, "")
+		if err != nil {
+			fmt.Fprintf(out, "failed to add '%s' to %s: %s\n", deploymentEnvFile, ignoreFile, err.Error())
+		}
+	}
+	return path, nil
+}
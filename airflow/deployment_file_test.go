@@ -0,0 +1,104 @@
+package airflow
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/astronomer/astro-cli/config"
+)
+
+const testDeploymentFile = `deployment:
+  environment_variables:
+    - is_secret: false
+      key: FOO
+      value: bar
+    - is_secret: false
+      key: OVERRIDDEN
+      value: deployment
+    - is_secret: true
+      key: TOKEN
+      value: null
+  configuration:
+    name: test-deployment
+    runtime_version: 12.1.0
+    executor: CeleryExecutor
+    scheduler_count: 2
+  worker_queues:
+    - name: default
+      worker_type: A5
+    - name: high-cpu
+      worker_concurrency: 4
+      worker_type: A10
+`
+
+func (s *Suite) TestConfigureFromDeploymentFile() {
+	writeProject := func(dockerfile string) string {
+		airflowHome := s.T().TempDir()
+		s.NoError(os.WriteFile(filepath.Join(airflowHome, "Dockerfile"), []byte(dockerfile), 0o600))
+		s.NoError(os.WriteFile(filepath.Join(airflowHome, "deployment.yaml"), []byte(testDeploymentFile), 0o600))
+		s.NoError(os.WriteFile(filepath.Join(airflowHome, ".env"), []byte("OVERRIDDEN=local\n"), 0o600))
+		config.CreateProjectConfig(airflowHome)
+		return airflowHome
+	}
+
+	s.Run("success", func() {
+		airflowHome := writeProject("FROM quay.io/astronomer/astro-runtime:12.1.0-base\n")
+		out := new(bytes.Buffer)
+
+		envFile, err := ConfigureFromDeploymentFile(filepath.Join(airflowHome, "deployment.yaml"), airflowHome, filepath.Join(airflowHome, ".env"), "Dockerfile", out)
+		s.NoError(err)
+		s.Equal(filepath.Join(airflowHome, deploymentEnvFile), envFile)
+
+		env, err := os.ReadFile(envFile)
+		s.NoError(err)
+		s.Equal("FOO=bar\nOVERRIDDEN=deployment\nOVERRIDDEN=local\n", string(env))
+		s.Contains(out.String(), "Skipping the secret environment variable TOKEN")
+		s.Contains(out.String(), "Using the Celery executor with the worker queues: default,high-cpu:4")
+
+		s.Equal(ExecutorCelery, config.CFG.DevExecutor.GetString())
+		s.Equal("default,high-cpu:4", config.CFG.DevCeleryQueues.GetString())
+		s.Equal(2, config.CFG.DevSchedulerCount.GetInt())
+
+		gitignore, err := os.ReadFile(filepath.Join(airflowHome, ".gitignore"))
+		s.NoError(err)
+		s.Contains(string(gitignore), deploymentEnvFile)
+	})
+
+	s.Run("runtime version mismatch", func() {
+		airflowHome := writeProject("FROM quay.io/astronomer/astro-runtime:11.0.0\n")
+
+		_, err := ConfigureFromDeploymentFile(filepath.Join(airflowHome, "deployment.yaml"), airflowHome, ".env", "Dockerfile", new(bytes.Buffer))
+		s.ErrorContains(err, "the Dockerfile uses the image tag 11.0.0 but the deployment file uses Runtime 12.1.0")
+	})
+
+	s.Run("local executor replaces the executor of a previous run", func() {
+		airflowHome := writeProject("FROM quay.io/astronomer/astro-runtime:12.1.0\n")
+		s.NoError(config.CFG.DevExecutor.SetProjectString(ExecutorCelery))
+		deploymentFile := strings.Replace(testDeploymentFile, "executor: CeleryExecutor", "executor: LocalExecutor", 1)
+		s.NoError(os.WriteFile(filepath.Join(airflowHome, "deployment.yaml"), []byte(deploymentFile), 0o600))
+		out := new(bytes.Buffer)
+
+		_, err := ConfigureFromDeploymentFile(filepath.Join(airflowHome, "deployment.yaml"), airflowHome, filepath.Join(airflowHome, ".env"), "Dockerfile", out)
+		s.NoError(err)
+		s.Equal(ExecutorLocal, config.CFG.DevExecutor.GetString())
+		s.Contains(out.String(), "Using the local executor")
+	})
+
+	s.Run("empty deployment file", func() {
+		airflowHome := s.T().TempDir()
+		s.NoError(os.WriteFile(filepath.Join(airflowHome, "deployment.yaml"), []byte{}, 0o600))
+
+		_, err := ConfigureFromDeploymentFile(filepath.Join(airflowHome, "deployment.yaml"), airflowHome, ".env", "Dockerfile", new(bytes.Buffer))
+		s.ErrorIs(err, errEmptyDeploymentFile)
+	})
+}
+
+func (s *Suite) TestGenerateConfigWithSchedulerCount() {
+	s.NoError(config.CFG.DevSchedulerCount.SetHomeString("2"))
+
+	cfg, err := generateConfig("test-project-name", "airflow_home", ".env", "", "", map[string]string{runtimeVersionLabelName: "3.0-1"})
+	s.NoError(err)
+	s.Contains(cfg, "    deploy:\n      replicas: 2\n")
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"html/template"
 	"io"
 	"io/fs"
 	"os"
@@ -39,6 +40,7 @@ import (
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/compose"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/pelletier/go-toml/v2"
 	"github.com/pkg/browser"
@@ -52,6 +54,7 @@ const (
 	componentName                         = "airflow"
 	dockerStateUp                         = "running"
 	dockerExitState                       = "exited"
+	astroCLIDockerLabel                   = "io.astronomer.docker.cli"
 	defaultAirflowVersion                 = uint64(0x2) //nolint:mnd
 	triggererAllowedRuntimeVersion        = "4.0.0"
 	triggererAllowedAirflowVersion        = "2.2.0"
@@ -66,6 +69,10 @@ const (
 	airflowMajorVersion2           uint64 = 2
 	airflowMajorVersion3           uint64 = 3
 
+	// localFernetKey encrypts the connections and variables of the local metadata database, for the containers of the
+	// project and the task pods of the KubernetesExecutor alike
+	localFernetKey = "d6Vefz3G9U_ynXB3cr7y_Ak35tAHkEGAVxuz_B-jzWw="
+
 	composeCreateErrMsg      = "error creating docker-compose project"
 	composeStatusCheckErrMsg = "error checking docker-compose status"
 	composeRecreateErrMsg    = "error building, (re)creating or starting project containers"
@@ -90,6 +97,7 @@ var (
 	initSettings      = settings.ConfigSettings
 	exportSettings    = settings.Export
 	envExportSettings = settings.EnvExport
+	validateSettings  = settings.ValidateSettingsFile
 
 	openURL = browser.OpenURL
 
@@ -136,6 +144,7 @@ type ComposeConfig struct {
 	PostgresPort             string
 	PostgresRepository       string
 	PostgresTag              string
+	FernetKey                string
 	AirflowEnvFile           string
 	AirflowImage             string
 	AirflowHome              string
@@ -150,6 +159,20 @@ type ComposeConfig struct {
 	TriggererEnabled         bool
 	ProjectName              string
 	AuthCredentialsDirectory string
+	SecretsBackend           string
+	SecretsBackendKwargs     template.HTML
+	ExtraVolumes             []string
+	VaultEnabled             bool
+	VaultImage               string
+	VaultToken               string
+	VaultPort                string
+	Executor                 string
+	KubernetesNetwork        string
+	KubernetesPostgresAlias  string
+	KubernetesAPIServerAlias string
+	CeleryWorkers            []celeryWorker
+	RedisImage               string
+	SchedulerCount           int
 }
 
 type DockerCompose struct {
@@ -215,25 +238,23 @@ func DockerComposeInit(airflowHome, envFile, dockerfile, imageName string) (*Doc
 //
 //nolint:gocognit
 func (d *DockerCompose) Start(imageName, settingsFile, composeFile, buildSecretString string, noCache, noBrowser bool, waitTime time.Duration, envConns map[string]astrocore.EnvironmentObjectConnection) error {
+	// Catch mistakes in the settings file before spending time on building the image
+	settingsFileExists, err := fileutil.Exists(settingsFile, nil)
+	if err != nil {
+		return errors.Wrap(err, errSettingsPath)
+	}
+	if settingsFileExists {
+		err = validateSettings(settingsFile)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Build this project image
 	if imageName == "" {
-		if !config.CFG.DisableAstroRun.GetBool() {
-			// add astro-run-dag package
-			err := fileutil.AddLineToFile("./requirements.txt", "astro-run-dag", "# This package is needed for the astro run command. It will be removed before a deploy")
-			if err != nil {
-				fmt.Printf("Adding 'astro-run-dag' package to requirements.txt unsuccessful: %s\nManually add package to requirements.txt", err.Error())
-			}
-		}
-		imageBuildErr := d.imageHandler.Build(d.dockerfile, buildSecretString, airflowTypes.ImageBuildConfig{Path: d.airflowHome, NoCache: noCache})
-		if !config.CFG.DisableAstroRun.GetBool() {
-			// remove astro-run-dag from requirments.txt
-			err := fileutil.RemoveLineFromFile("./requirements.txt", "astro-run-dag", " # This package is needed for the astro run command. It will be removed before a deploy")
-			if err != nil {
-				fmt.Printf("Removing line 'astro-run-dag' package from requirements.txt unsuccessful: %s\n", err.Error())
-			}
-		}
-		if imageBuildErr != nil {
-			return imageBuildErr
+		err := d.buildProjectImage(buildSecretString, noCache)
+		if err != nil {
+			return err
 		}
 	} else {
 		// skip build if an imageName is passed
@@ -248,6 +269,27 @@ func (d *DockerCompose) Start(imageName, settingsFile, composeFile, buildSecretS
 		return err
 	}
 
+	// Make sure the ports of this project don't collide with other running projects
+	err = d.allocatePorts(imageLabels, settingsFile)
+	if err != nil {
+		return err
+	}
+
+	// The KubernetesExecutor runs the tasks on a local cluster, which needs the project image. The cluster left
+	// behind by a previous run with the KubernetesExecutor is deleted once the project uses another executor.
+	if kubernetesExecutorEnabled() {
+		err = d.startKubernetesCluster(imageLabels)
+		if err != nil {
+			return err
+		}
+	} else if d.kubernetesClusterTracked() {
+		fmt.Println("Deleting the local Kubernetes cluster, which the executor of the project no longer uses")
+		err = d.deleteKubernetesCluster()
+		if err != nil {
+			return err
+		}
+	}
+
 	s := spinner.NewSpinner("Project is starting up…")
 	if !logger.IsLevelEnabled(logrus.DebugLevel) {
 		s.Start()
@@ -286,11 +328,11 @@ func (d *DockerCompose) Start(imageName, settingsFile, composeFile, buildSecretS
 	var healthURL, healthComponent string
 	switch airflowMajorVersion {
 	case "3":
-		healthURL = fmt.Sprintf("http://localhost:%s/api/v2/monitor/health", config.CFG.APIServerPort.GetString())
-		healthComponent = "api-server"
+		healthComponent = APIServerDockerContainerName
+		healthURL = healthCheckURL(healthComponent, config.CFG.APIServerPort.GetString())
 	case "2":
-		healthURL = fmt.Sprintf("http://localhost:%s/health", config.CFG.WebserverPort.GetString())
-		healthComponent = "webserver"
+		healthComponent = WebserverDockerContainerName
+		healthURL = healthCheckURL(healthComponent, config.CFG.WebserverPort.GetString())
 	}
 
 	// Check the health of the webserver, up to the timeout.
@@ -311,6 +353,26 @@ func (d *DockerCompose) Start(imageName, settingsFile, composeFile, buildSecretS
 	return nil
 }
 
+// buildProjectImage builds the image of this project, including the packages needed by astro run
+func (d *DockerCompose) buildProjectImage(buildSecretString string, noCache bool) error {
+	if !config.CFG.DisableAstroRun.GetBool() {
+		// add astro-run-dag package
+		err := fileutil.AddLineToFile("./requirements.txt", "astro-run-dag", "# This package is needed for the astro run command. It will be removed before a deploy")
+		if err != nil {
+			fmt.Printf("Adding 'astro-run-dag' package to requirements.txt unsuccessful: %s\nManually add package to requirements.txt", err.Error())
+		}
+	}
+	imageBuildErr := d.imageHandler.Build(d.dockerfile, buildSecretString, airflowTypes.ImageBuildConfig{Path: d.airflowHome, NoCache: noCache})
+	if !config.CFG.DisableAstroRun.GetBool() {
+		// remove astro-run-dag from requirments.txt
+		err := fileutil.RemoveLineFromFile("./requirements.txt", "astro-run-dag", " # This package is needed for the astro run command. It will be removed before a deploy")
+		if err != nil {
+			fmt.Printf("Removing line 'astro-run-dag' package from requirements.txt unsuccessful: %s\n", err.Error())
+		}
+	}
+	return imageBuildErr
+}
+
 func (d *DockerCompose) ComposeExport(settingsFile, composeFile string) error {
 	// Get project containers
 	_, err := d.composeService.Ps(context.Background(), d.projectName, api.PsOptions{
@@ -367,6 +429,13 @@ func (d *DockerCompose) Stop(waitForExit bool) error {
 		return errors.Wrap(err, composePauseErrMsg)
 	}
 
+	if kubernetesExecutorEnabled() || d.kubernetesClusterTracked() {
+		err = d.stopKubernetesCluster()
+		if err != nil {
+			return err
+		}
+	}
+
 	if !waitForExit {
 		spinner.StopWithCheckmark(s, "Project stopped")
 		return nil
@@ -402,7 +471,7 @@ func (d *DockerCompose) Stop(waitForExit bool) error {
 	}
 }
 
-func (d *DockerCompose) PS() error {
+func (d *DockerCompose) PS(outputFormat string) error {
 	// List project containers
 	psInfo, err := d.composeService.Ps(context.Background(), d.projectName, api.PsOptions{
 		All: true,
@@ -411,6 +480,10 @@ func (d *DockerCompose) PS() error {
 		return errors.Wrap(err, composeStatusCheckErrMsg)
 	}
 
+	if outputFormat != "" && outputFormat != tableOutputFormat {
+		return printProjectStatus(d.projectName, psInfo, outputFormat)
+	}
+
 	// Columns for table
 	infoColumns := []string{"Name", "State", "Ports"}
 
@@ -432,6 +505,89 @@ func (d *DockerCompose) PS() error {
 	return tw.Flush()
 }
 
+// List prints all local Astro projects along with their state and URLs
+func (d *DockerCompose) List() error {
+	containers, err := d.cliClient.ContainerList(context.Background(), container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", astroCLIDockerLabel+"=true")),
+	})
+	if err != nil {
+		return errors.Wrap(err, composeStatusCheckErrMsg)
+	}
+
+	type localProject struct {
+		name, state, path, uiURL, postgresURL string
+	}
+	projects := map[string]*localProject{}
+	for i := range containers {
+		labels := containers[i].Labels
+		name := labels[api.ProjectLabel]
+		if name == "" {
+			continue
+		}
+		p, ok := projects[name]
+		if !ok {
+			p = &localProject{name: name, state: containers[i].State, path: labels[api.WorkingDirLabel]}
+			projects[name] = p
+		}
+		// a project is running as soon as any of its containers is running
+		if containers[i].State == dockerStateUp {
+			p.state = dockerStateUp
+		}
+		for _, port := range containers[i].Ports {
+			if port.PublicPort == 0 {
+				continue
+			}
+			switch labels[api.ServiceLabel] {
+			case WebserverDockerContainerName, APIServerDockerContainerName:
+				p.uiURL = fmt.Sprintf("http://localhost:%d", port.PublicPort)
+			case PostgresDockerContainerName:
+				p.postgresURL = fmt.Sprintf("postgresql://localhost:%d/postgres", port.PublicPort)
+			}
+		}
+	}
+
+	if len(projects) == 0 {
+		fmt.Println("No local Astro projects found")
+		return nil
+	}
+
+	names := make([]string, 0, len(projects))
+	for name := range projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Columns for table
+	infoColumns := []string{"Name", "State", "Airflow UI", "Postgres", "Path"}
+
+	// Create a new tabwriter
+	tw := new(tabwriter.Writer)
+	tw.Init(os.Stdout, 0, 8, 2, '\t', tabwriter.AlignRight) //nolint:mnd
+
+	// Append data to table, marking the project of the current directory
+	currentProject := normalizeName(d.projectName)
+	fmt.Fprintln(tw, strings.Join(infoColumns, "\t"))
+	for _, name := range names {
+		p := projects[name]
+		if name == currentProject {
+			name += " (current)"
+		}
+		data := []string{name, p.state, valueOrDash(p.uiURL), valueOrDash(p.postgresURL), p.path}
+		fmt.Fprintln(tw, strings.Join(data, "\t"))
+	}
+
+	// Flush to stdout
+	return tw.Flush()
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
 // Kill stops a local airflow development cluster
 func (d *DockerCompose) Kill() error {
 	s := spinner.NewSpinner("Killing project…")
@@ -453,6 +609,13 @@ func (d *DockerCompose) Kill() error {
 	}
 	logrus.SetLevel(originalLevel)
 
+	if kubernetesExecutorEnabled() || d.kubernetesClusterTracked() {
+		err = d.deleteKubernetesCluster()
+		if err != nil {
+			return err
+		}
+	}
+
 	spinner.StopWithCheckmark(s, "Project killed")
 
 	return nil
@@ -1278,7 +1441,7 @@ func (d *DockerCompose) ExportSettings(settingsFile, envFile string, connections
 	return nil
 }
 
-func (d *DockerCompose) ImportSettings(settingsFile, envFile string, connections, variables, pools bool) error {
+func (d *DockerCompose) ImportSettings(settingsFile, envFile string, connections, variables, pools, prune bool) error {
 	// setup bools
 	if !connections && !variables && !pools {
 		connections = true
@@ -1306,7 +1469,7 @@ func (d *DockerCompose) ImportSettings(settingsFile, envFile string, connections
 		return errNoFile
 	}
 
-	err = initSettings(containerID, settingsFile, nil, airflowDockerVersion, connections, variables, pools)
+	err = initSettings(containerID, settingsFile, nil, airflowDockerVersion, connections, variables, pools, prune)
 	if err != nil {
 		return err
 	}
@@ -1315,6 +1478,11 @@ func (d *DockerCompose) ImportSettings(settingsFile, envFile string, connections
 }
 
 func (d *DockerCompose) getWebServerContainerID() (string, error) {
+	return d.getContainerID(WebserverDockerContainerName, APIServerDockerContainerName)
+}
+
+// getContainerID returns the ID of the first container of this project matching one of the given components
+func (d *DockerCompose) getContainerID(components ...string) (string, error) {
 	psInfo, err := d.composeService.Ps(context.Background(), d.projectName, api.PsOptions{
 		All: true,
 	})
@@ -1328,10 +1496,13 @@ func (d *DockerCompose) getWebServerContainerID() (string, error) {
 	replacer := strings.NewReplacer("_", "", "-", "")
 	strippedProjectName := replacer.Replace(d.projectName)
 	for i := range psInfo {
-		if strings.Contains(replacer.Replace(psInfo[i].Name), strippedProjectName) &&
-			(strings.Contains(psInfo[i].Name, WebserverDockerContainerName) ||
-				strings.Contains(psInfo[i].Name, APIServerDockerContainerName)) {
-			return psInfo[i].ID, nil
+		if !strings.Contains(replacer.Replace(psInfo[i].Name), strippedProjectName) {
+			continue
+		}
+		for _, component := range components {
+			if strings.Contains(psInfo[i].Name, component) {
+				return psInfo[i].ID, nil
+			}
 		}
 	}
 	return "", err
@@ -1512,7 +1683,7 @@ func printStatus(settingsFile string, envConns map[string]astrocore.EnvironmentO
 			if strings.Contains(container.Name, project.Name) &&
 				(strings.Contains(container.Name, WebserverDockerContainerName) ||
 					strings.Contains(container.Name, APIServerDockerContainerName)) {
-				err = initSettings(container.ID, settingsFile, envConns, airflowMajorVersion, true, true, true)
+				err = initSettings(container.ID, settingsFile, envConns, airflowMajorVersion, true, true, true, false)
 				if err != nil {
 					return err
 				}
@@ -20,6 +20,7 @@ import (
 	"github.com/astronomer/astro-cli/pkg/fileutil"
 	"github.com/astronomer/astro-cli/pkg/logger"
 	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/astronomer/astro-cli/settings"
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/compose/v2/pkg/api"
 	docker_types "github.com/docker/docker/api/types"
@@ -588,6 +589,24 @@ func (s *Suite) TestDockerComposeStart() {
 		composeMock.AssertExpectations(s.T())
 	})
 
+	s.Run("invalid settings file", func() {
+		validateSettings = func(settingsFile string) error {
+			s.Equal("./testfiles/airflow_settings.yaml", settingsFile)
+			return errMockSettings
+		}
+		defer func() { validateSettings = settings.ValidateSettingsFile }()
+
+		imageHandler := new(mocks.ImageHandler)
+		composeMock := new(mocks.DockerComposeAPI)
+		mockDockerCompose.composeService = composeMock
+		mockDockerCompose.imageHandler = imageHandler
+
+		err := mockDockerCompose.Start("", "./testfiles/airflow_settings.yaml", "", "", false, true, waitTime, nil)
+		s.ErrorIs(err, errMockSettings)
+
+		imageHandler.AssertNotCalled(s.T(), "Build", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	s.Run("success with shorter default startup time", func() {
 		defaultTimeOut := 1 * time.Minute
 		noCache := false
@@ -989,7 +1008,7 @@ func (s *Suite) TestDockerComposePS() {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 
-		err := mockDockerCompose.PS()
+		err := mockDockerCompose.PS("")
 		s.NoError(err)
 
 		w.Close()
@@ -1006,12 +1025,84 @@ func (s *Suite) TestDockerComposePS() {
 
 		mockDockerCompose.composeService = composeMock
 
-		err := mockDockerCompose.PS()
+		err := mockDockerCompose.PS("")
 		s.ErrorIs(err, errMockDocker)
 		composeMock.AssertExpectations(s.T())
 	})
 }
 
+func (s *Suite) TestDockerComposeList() {
+	mockDockerCompose := DockerCompose{projectName: "test"}
+	s.Run("success", func() {
+		cliClient := new(mocks.DockerCLIClient)
+		cliClient.On("ContainerList", mock.Anything, mock.Anything).Return([]docker_types.Container{
+			{
+				State:  "running",
+				Labels: map[string]string{api.ProjectLabel: "test", api.ServiceLabel: APIServerDockerContainerName, api.WorkingDirLabel: "/projects/test"},
+				Ports:  []docker_types.Port{{PrivatePort: 8080, PublicPort: 8081}},
+			},
+			{
+				State:  "running",
+				Labels: map[string]string{api.ProjectLabel: "test", api.ServiceLabel: PostgresDockerContainerName, api.WorkingDirLabel: "/projects/test"},
+				Ports:  []docker_types.Port{{PrivatePort: 5432, PublicPort: 5433}},
+			},
+			{
+				State:  "exited",
+				Labels: map[string]string{api.ProjectLabel: "other", api.ServiceLabel: WebserverDockerContainerName, api.WorkingDirLabel: "/projects/other"},
+			},
+		}, nil).Once()
+
+		mockDockerCompose.cliClient = cliClient
+
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := mockDockerCompose.List()
+		s.NoError(err)
+
+		w.Close()
+		out, _ := io.ReadAll(r)
+
+		s.Contains(string(out), "test (current)")
+		s.Contains(string(out), "http://localhost:8081")
+		s.Contains(string(out), "postgresql://localhost:5433/postgres")
+		s.Contains(string(out), "/projects/test")
+		s.Contains(string(out), "other")
+		s.Contains(string(out), "exited")
+		cliClient.AssertExpectations(s.T())
+	})
+
+	s.Run("no projects", func() {
+		cliClient := new(mocks.DockerCLIClient)
+		cliClient.On("ContainerList", mock.Anything, mock.Anything).Return([]docker_types.Container{}, nil).Once()
+
+		mockDockerCompose.cliClient = cliClient
+
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := mockDockerCompose.List()
+		s.NoError(err)
+
+		w.Close()
+		out, _ := io.ReadAll(r)
+
+		s.Contains(string(out), "No local Astro projects found")
+		cliClient.AssertExpectations(s.T())
+	})
+
+	s.Run("container list failure", func() {
+		cliClient := new(mocks.DockerCLIClient)
+		cliClient.On("ContainerList", mock.Anything, mock.Anything).Return(nil, errMockDocker).Once()
+
+		mockDockerCompose.cliClient = cliClient
+
+		err := mockDockerCompose.List()
+		s.ErrorIs(err, errMockDocker)
+		cliClient.AssertExpectations(s.T())
+	})
+}
+
 func (s *Suite) TestDockerComposeKill() {
 	mockDockerCompose := DockerCompose{projectName: "test"}
 	s.Run("success", func() {
@@ -1025,6 +1116,27 @@ func (s *Suite) TestDockerComposeKill() {
 		composeMock.AssertExpectations(s.T())
 	})
 
+	s.Run("deletes the local Kubernetes cluster after switching executor", func() {
+		origNewLocalCluster := newLocalCluster
+		defer func() { newLocalCluster = origNewLocalCluster }()
+		airflowHome := s.T().TempDir()
+		s.NoError(os.MkdirAll(filepath.Join(airflowHome, kubernetesDirectory), 0o700))
+		cluster := new(fakeLocalCluster)
+		cluster.On("Exists").Return(true, nil).Once()
+		cluster.On("Delete").Return(nil).Once()
+		newLocalCluster = func(projectName string) (localCluster, error) { return cluster, nil }
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Down", mock.Anything, mockDockerCompose.projectName, api.DownOptions{Volumes: true, RemoveOrphans: true}).Return(nil).Once()
+
+		d := DockerCompose{projectName: "test", airflowHome: airflowHome, composeService: composeMock}
+		s.Equal(ExecutorLocal, config.CFG.DevExecutor.GetString())
+		err := d.Kill()
+		s.NoError(err)
+		composeMock.AssertExpectations(s.T())
+		cluster.AssertExpectations(s.T())
+		s.NoDirExists(filepath.Join(airflowHome, kubernetesDirectory))
+	})
+
 	s.Run("compose down failure", func() {
 		composeMock := new(mocks.DockerComposeAPI)
 		composeMock.On("Down", mock.Anything, mockDockerCompose.projectName, api.DownOptions{Volumes: true, RemoveOrphans: true}).Return(errMockDocker).Once()
@@ -1650,14 +1762,15 @@ func (s *Suite) TestDockerComposeSettings() {
 		imageHandler := new(mocks.ImageHandler)
 		imageHandler.On("ListLabels").Return(map[string]string{airflowVersionLabelName: airflowVersionLabel}, nil).Once()
 
-		initSettings = func(id, settingsFile string, envConns map[string]astrocore.EnvironmentObjectConnection, version uint64, connections, variables, pools bool) error {
+		initSettings = func(id, settingsFile string, envConns map[string]astrocore.EnvironmentObjectConnection, version uint64, connections, variables, pools, prune bool) error {
+			s.True(prune)
 			return nil
 		}
 
 		mockDockerCompose.composeService = composeMock
 		mockDockerCompose.imageHandler = imageHandler
 
-		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings.yaml", ".env", true, true, true)
+		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings.yaml", ".env", true, true, true, true)
 		s.NoError(err)
 		composeMock.AssertExpectations(s.T())
 	})
@@ -1667,14 +1780,14 @@ func (s *Suite) TestDockerComposeSettings() {
 		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{{ID: "test-webserver-id", State: "running", Name: "test-webserver"}}, nil).Once()
 		imageHandler := new(mocks.ImageHandler)
 		imageHandler.On("ListLabels").Return(map[string]string{airflowVersionLabelName: airflowVersionLabel}, nil).Once()
-		initSettings = func(id, settingsFile string, envConns map[string]astrocore.EnvironmentObjectConnection, version uint64, connections, variables, pools bool) error {
+		initSettings = func(id, settingsFile string, envConns map[string]astrocore.EnvironmentObjectConnection, version uint64, connections, variables, pools, prune bool) error {
 			return errMockSettings
 		}
 
 		mockDockerCompose.composeService = composeMock
 		mockDockerCompose.imageHandler = imageHandler
 
-		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings.yaml", ".env", false, false, false)
+		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings.yaml", ".env", false, false, false, false)
 		s.ErrorIs(err, errMockSettings)
 		composeMock.AssertExpectations(s.T())
 	})
@@ -1756,7 +1869,7 @@ func (s *Suite) TestDockerComposeSettings() {
 		mockDockerCompose.composeService = composeMock
 		mockDockerCompose.imageHandler = imageHandler
 
-		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings.yaml", ".env", true, true, true)
+		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings.yaml", ".env", true, true, true, false)
 		s.Contains(err.Error(), errMock.Error())
 		composeMock.AssertExpectations(s.T())
 	})
@@ -1780,7 +1893,7 @@ func (s *Suite) TestDockerComposeSettings() {
 		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{}, errMockDocker).Once()
 
 		mockDockerCompose.composeService = composeMock
-		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings.yaml", ".env", true, true, true)
+		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings.yaml", ".env", true, true, true, false)
 		s.ErrorIs(err, errMockDocker)
 		composeMock.AssertExpectations(s.T())
 	})
@@ -1801,7 +1914,7 @@ func (s *Suite) TestDockerComposeSettings() {
 
 		mockDockerCompose.composeService = composeMock
 
-		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings.yaml", ".env", true, true, true)
+		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings.yaml", ".env", true, true, true, false)
 		s.Contains(err.Error(), "project not running, run astro dev start to start project")
 		composeMock.AssertExpectations(s.T())
 	})
@@ -1826,7 +1939,7 @@ func (s *Suite) TestDockerComposeSettings() {
 		mockDockerCompose.composeService = composeMock
 		mockDockerCompose.imageHandler = imageHandler
 
-		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings_invalid.yaml", ".env", true, true, true)
+		err := mockDockerCompose.ImportSettings("./testfiles/airflow_settings_invalid.yaml", ".env", true, true, true, false)
 		s.Contains(err.Error(), "file specified does not exist")
 		composeMock.AssertExpectations(s.T())
 	})
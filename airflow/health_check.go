@@ -7,6 +7,16 @@ import (
 	"time"
 )
 
+const healthCheckRequestTimeout = 5 * time.Second
+
+// healthCheckURL returns the URL of the health endpoint of the webserver or API server
+func healthCheckURL(component, port string) string {
+	if component == APIServerDockerContainerName {
+		return fmt.Sprintf("http://localhost:%s/api/v2/monitor/health", port)
+	}
+	return fmt.Sprintf("http://localhost:%s/health", port)
+}
+
 // checkWebserverHealth is a container runtime agnostic way to check if
 // the webserver or API server is healthy.
 var checkWebserverHealth = func(url string, timeout time.Duration, component string) error {
@@ -17,7 +27,7 @@ var checkWebserverHealth = func(url string, timeout time.Duration, component str
 
 	// Create an HTTP client for healthcheck requests.
 	client := &http.Client{
-		Timeout: 5 * time.Second,
+		Timeout: healthCheckRequestTimeout,
 	}
 
 	// This ticker represents the interval of our healthcheck.
@@ -46,6 +56,18 @@ var checkWebserverHealth = func(url string, timeout time.Duration, component str
 	}
 }
 
+// isWebserverHealthy runs a single health check against the webserver or API server
+var isWebserverHealthy = func(url string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckRequestTimeout)
+	defer cancel()
+
+	client := &http.Client{
+		Timeout: healthCheckRequestTimeout,
+	}
+	statusCode, err := healthCheck(ctx, client, url)
+	return err == nil && statusCode == http.StatusOK
+}
+
 // healthCheck is a helper function to execute an HTTP request
 // and return the status code or an error.
 func healthCheck(ctx context.Context, client *http.Client, url string) (int, error) {
@@ -0,0 +1,538 @@
+package airflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/astronomer/astro-cli/airflow/runtimes"
+	airflowversions "github.com/astronomer/astro-cli/airflow_versions"
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/pkg/fileutil"
+	"github.com/compose-spec/compose-go/v2/dotenv"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ExecutorLocal runs the tasks in the scheduler container with the LocalExecutor
+	ExecutorLocal = "local"
+	// ExecutorKubernetes runs each task in a pod of a local kind or k3d cluster with the KubernetesExecutor
+	ExecutorKubernetes = "kubernetes"
+
+	kubernetesProviderKind = "kind"
+	kubernetesProviderK3d  = "k3d"
+
+	// kubernetesDirectory holds the kubeconfig and the pod template of the local cluster, relative to the project
+	kubernetesDirectory   = ".astro/kubernetes"
+	kubeconfigFileName    = "kubeconfig.yaml"
+	podTemplateFileName   = "pod_template.yaml"
+	kindConfigFileName    = "kind_config.yaml"
+	kubernetesFilePerms   = 0o600
+	kubernetesDirPerms    = 0o700
+	clusterNameMaxLength  = 32
+	kubernetesClusterRole = "astro-dev"
+	// kubernetesProjectMount is where the project is mounted on the nodes of the cluster, for the task pods to mount
+	// its dags, plugins and include directories like the containers of the project do
+	kubernetesProjectMount = "/astro-project"
+
+	kubernetesExecutorClass = "KubernetesExecutor"
+	localExecutorClass      = "LocalExecutor"
+)
+
+var (
+	errInvalidExecutor           = errors.New("invalid executor, the executor can be one of: local, celery or kubernetes")
+	errInvalidKubernetesProvider = errors.New("invalid dev.kubernetes_provider, the provider can be one of: kind or k3d")
+
+	k3dServerRegex = regexp.MustCompile(`(?m)^(\s*server:\s*).*$`)
+
+	// podTemplate is the pod the KubernetesExecutor runs each task in, the tasks reach the metadata database and
+	// the API server of the project through their aliases on the network of the cluster. Like the containers of the
+	// project, the tasks get the variables of the env file and the dags, plugins and include directories of the project.
+	podTemplate = template.Must(template.New("pod").Parse(`apiVersion: v1
+kind: Pod
+metadata:
+  name: placeholder
+spec:
+  restartPolicy: Never
+  containers:
+    - name: base
+      image: {{ .Image }}
+      imagePullPolicy: Never
+      env:
+        {{- range .EnvVars }}
+        - name: {{ .Name }}
+          value: {{ .Value }}
+        {{- end }}
+        - name: AIRFLOW__CORE__EXECUTOR
+          value: ` + localExecutorClass + `
+        - name: AIRFLOW__CORE__LOAD_EXAMPLES
+          value: "False"
+        - name: AIRFLOW__CORE__FERNET_KEY
+          value: "{{ .FernetKey }}"
+        - name: AIRFLOW__CORE__SQL_ALCHEMY_CONN
+          value: "{{ .SQLAlchemyConn }}"
+        - name: AIRFLOW__DATABASE__SQL_ALCHEMY_CONN
+          value: "{{ .SQLAlchemyConn }}"
+        - name: AIRFLOW__WEBSERVER__SECRET_KEY
+          value: "{{ .ProjectName }}"
+        - name: ASTRONOMER_ENVIRONMENT
+          value: local
+        {{- if .ExecutionAPIServerURL }}
+        - name: AIRFLOW__API_AUTH__JWT_SECRET
+          value: "{{ .ProjectName }}"
+        - name: AIRFLOW__CORE__EXECUTION_API_SERVER_URL
+          value: "{{ .ExecutionAPIServerURL }}"
+        {{- end }}
+      volumeMounts:
+        {{- range .ProjectDirs }}
+        - name: {{ . }}
+          mountPath: /usr/local/airflow/{{ . }}
+        {{- end }}
+  volumes:
+    {{- range .ProjectDirs }}
+    - name: {{ . }}
+      hostPath:
+        path: ` + kubernetesProjectMount + `/{{ . }}
+    {{- end }}
+`))
+)
+
+// ValidateExecutor checks the executor of the local environment is supported
+func ValidateExecutor(executor string) error {
+	switch executor {
+	case ExecutorLocal, ExecutorCelery, ExecutorKubernetes:
+		return nil
+	}
+	return errInvalidExecutor
+}
+
+// kubernetesExecutorEnabled checks whether the project runs its tasks on a local Kubernetes cluster
+func kubernetesExecutorEnabled() bool {
+	return config.CFG.DevExecutor.GetString() == ExecutorKubernetes
+}
+
+// localCluster is a local Kubernetes cluster running the task pods of the KubernetesExecutor
+type localCluster interface {
+	Name() string
+	// Network is the docker network of the cluster nodes, which the Airflow containers join
+	Network() string
+	Exists() (bool, error)
+	// Create creates the cluster, mounting the project directory on its nodes
+	Create(projectDir string) error
+	Start() error
+	Stop() error
+	Delete() error
+	LoadImage(image string) error
+	// Kubeconfig returns a kubeconfig reaching the cluster from containers on its network
+	Kubeconfig() (string, error)
+	Kubectl(args ...string) error
+}
+
+// newLocalCluster returns the local cluster of a project, using the provider set in dev.kubernetes_provider
+var newLocalCluster = func(projectName string) (localCluster, error) {
+	name := kubernetesClusterName(projectName)
+	switch config.CFG.KubernetesProvider.GetString() {
+	case kubernetesProviderKind:
+		return &kindCluster{name: name}, nil
+	case kubernetesProviderK3d:
+		return &k3dCluster{name: name}, nil
+	}
+	return nil, errInvalidKubernetesProvider
+}
+
+// kubernetesClusterName derives a valid cluster name from the project name, keeping its unique suffix
+func kubernetesClusterName(projectName string) string {
+	name := strings.ReplaceAll(projectName, "_", "-")
+	maxLength := clusterNameMaxLength - len("astro-")
+	if len(name) > maxLength {
+		name = name[len(name)-maxLength:]
+	}
+	return "astro-" + strings.TrimLeft(name, "-")
+}
+
+// runClusterCommand runs a cluster command, returning its output and its error output on failure
+func runClusterCommand(command string, args ...string) (string, error) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	if err := cmdExec(command, stdout, stderr, args...); err != nil {
+		return "", errors.Wrapf(err, "%s %s: %s", command, strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+type kindCluster struct {
+	name string
+}
+
+func (c *kindCluster) Name() string {
+	return c.name
+}
+
+func (c *kindCluster) Network() string {
+	return "kind"
+}
+
+func (c *kindCluster) controlPlane() string {
+	return c.name + "-control-plane"
+}
+
+func (c *kindCluster) Exists() (bool, error) {
+	out, err := runClusterCommand(kubernetesProviderKind, "get", "clusters")
+	if err != nil {
+		return false, err
+	}
+	for _, name := range strings.Fields(out) {
+		if name == c.name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Create mounts the project directory on the node with a kind config, written to the directory of the cluster
+func (c *kindCluster) Create(projectDir string) error {
+	kindConfig := fmt.Sprintf(`kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+  - role: control-plane
+    extraMounts:
+      - hostPath: %q
+        containerPath: %s
+`, projectDir, kubernetesProjectMount)
+	configFile := filepath.Join(projectDir, kubernetesDirectory, kindConfigFileName)
+	if err := os.WriteFile(configFile, []byte(kindConfig), kubernetesFilePerms); err != nil {
+		return errors.Wrap(err, "error writing the kind config of the local Kubernetes cluster")
+	}
+	_, err := runClusterCommand(kubernetesProviderKind, "create", "cluster", "--name", c.name, "--config", configFile)
+	return err
+}
+
+// Start starts the node of a stopped cluster, kind has no command for it
+func (c *kindCluster) Start() error {
+	containerRuntime, err := runtimes.GetContainerRuntimeBinary()
+	if err != nil {
+		return err
+	}
+	_, err = runClusterCommand(containerRuntime, "start", c.controlPlane())
+	return err
+}
+
+func (c *kindCluster) Stop() error {
+	containerRuntime, err := runtimes.GetContainerRuntimeBinary()
+	if err != nil {
+		return err
+	}
+	_, err = runClusterCommand(containerRuntime, "stop", c.controlPlane())
+	return err
+}
+
+func (c *kindCluster) Delete() error {
+	_, err := runClusterCommand(kubernetesProviderKind, "delete", "cluster", "--name", c.name)
+	return err
+}
+
+func (c *kindCluster) LoadImage(image string) error {
+	_, err := runClusterCommand(kubernetesProviderKind, "load", "docker-image", image, "--name", c.name)
+	return err
+}
+
+func (c *kindCluster) Kubeconfig() (string, error) {
+	return runClusterCommand(kubernetesProviderKind, "get", "kubeconfig", "--internal", "--name", c.name)
+}
+
+func (c *kindCluster) Kubectl(args ...string) error {
+	containerRuntime, err := runtimes.GetContainerRuntimeBinary()
+	if err != nil {
+		return err
+	}
+	_, err = runClusterCommand(containerRuntime, append([]string{"exec", c.controlPlane(), "kubectl", "--kubeconfig", "/etc/kubernetes/admin.conf"}, args...)...)
+	return err
+}
+
+type k3dCluster struct {
+	name string
+}
+
+func (c *k3dCluster) Name() string {
+	return c.name
+}
+
+func (c *k3dCluster) Network() string {
+	return "k3d-" + c.name
+}
+
+func (c *k3dCluster) server() string {
+	return "k3d-" + c.name + "-server-0"
+}
+
+func (c *k3dCluster) Exists() (bool, error) {
+	out, err := runClusterCommand(kubernetesProviderK3d, "cluster", "list", "-o", "json")
+	if err != nil {
+		return false, err
+	}
+	var clusters []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(out), &clusters); err != nil {
+		return false, errors.Wrap(err, "error decoding the k3d clusters")
+	}
+	for _, cluster := range clusters {
+		if cluster.Name == c.name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *k3dCluster) Create(projectDir string) error {
+	_, err := runClusterCommand(kubernetesProviderK3d, "cluster", "create", c.name, "--volume", projectDir+":"+kubernetesProjectMount+"@all", "--wait")
+	return err
+}
+
+func (c *k3dCluster) Start() error {
+	_, err := runClusterCommand(kubernetesProviderK3d, "cluster", "start", c.name, "--wait")
+	return err
+}
+
+func (c *k3dCluster) Stop() error {
+	_, err := runClusterCommand(kubernetesProviderK3d, "cluster", "stop", c.name)
+	return err
+}
+
+func (c *k3dCluster) Delete() error {
+	_, err := runClusterCommand(kubernetesProviderK3d, "cluster", "delete", c.name)
+	return err
+}
+
+func (c *k3dCluster) LoadImage(image string) error {
+	_, err := runClusterCommand(kubernetesProviderK3d, "image", "import", image, "--cluster", c.name)
+	return err
+}
+
+// Kubeconfig points the kubeconfig of the cluster at the server node, as the kubeconfig of k3d uses a host port
+func (c *k3dCluster) Kubeconfig() (string, error) {
+	kubeconfig, err := runClusterCommand(kubernetesProviderK3d, "kubeconfig", "get", c.name)
+	if err != nil {
+		return "", err
+	}
+	return k3dServerRegex.ReplaceAllString(kubeconfig, "${1}https://"+c.server()+":6443"), nil
+}
+
+func (c *k3dCluster) Kubectl(args ...string) error {
+	containerRuntime, err := runtimes.GetContainerRuntimeBinary()
+	if err != nil {
+		return err
+	}
+	_, err = runClusterCommand(containerRuntime, append([]string{"exec", c.server(), "kubectl"}, args...)...)
+	return err
+}
+
+// kubernetesHostAlias is the name a container of the project is reached at from the task pods
+func kubernetesHostAlias(projectName, service string) string {
+	return kubernetesClusterName(projectName) + "-" + service
+}
+
+// startKubernetesCluster creates or starts the local cluster of the project, loads the project image into it and
+// writes the kubeconfig and the pod template the KubernetesExecutor uses
+func (d *DockerCompose) startKubernetesCluster(imageLabels map[string]string) error {
+	cluster, err := newLocalCluster(d.projectName)
+	if err != nil {
+		return err
+	}
+
+	// the directory tracks the cluster, so it is created before the cluster
+	directory := filepath.Join(d.airflowHome, kubernetesDirectory)
+	if err := os.MkdirAll(directory, kubernetesDirPerms); err != nil {
+		return errors.Wrap(err, "error creating the local Kubernetes cluster directory")
+	}
+
+	exists, err := cluster.Exists()
+	if err != nil {
+		return err
+	}
+	if exists {
+		fmt.Printf("Starting local Kubernetes cluster %s\n", cluster.Name())
+		if err := cluster.Start(); err != nil {
+			return errors.Wrap(err, "error starting the local Kubernetes cluster")
+		}
+	} else {
+		fmt.Printf("Creating local Kubernetes cluster %s\n", cluster.Name())
+		if err := cluster.Create(d.airflowHome); err != nil {
+			return errors.Wrap(err, "error creating the local Kubernetes cluster")
+		}
+		// let the tasks manage pods, e.g. with the KubernetesPodOperator
+		err = cluster.Kubectl("create", "clusterrolebinding", kubernetesClusterRole, "--clusterrole=cluster-admin", "--serviceaccount=default:default")
+		if err != nil {
+			return errors.Wrap(err, "error granting the task pods access to the local Kubernetes cluster")
+		}
+	}
+
+	image := ImageName(d.projectName, "latest")
+	if err := loadKubernetesImage(cluster, image); err != nil {
+		return err
+	}
+
+	kubeconfig, err := cluster.Kubeconfig()
+	if err != nil {
+		return errors.Wrap(err, "error getting the kubeconfig of the local Kubernetes cluster")
+	}
+	pod, err := generatePodTemplate(d.projectName, image, d.envFile, imageLabels)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(directory, kubeconfigFileName), []byte(kubeconfig), kubernetesFilePerms); err != nil {
+		return errors.Wrap(err, "error writing the kubeconfig of the local Kubernetes cluster")
+	}
+	if err := os.WriteFile(filepath.Join(directory, podTemplateFileName), []byte(pod), kubernetesFilePerms); err != nil {
+		return errors.Wrap(err, "error writing the pod template of the local Kubernetes cluster")
+	}
+
+	// the kubeconfig holds the credentials of the cluster, keep it out of git and out of the image
+	for _, ignoreFile := range []string{".gitignore", ".dockerignore"} {
+		err = fileutil.AddLineToFile(filepath.Join(d.airflowHome, ignoreFile), kubernetesDirectory+"/", "")
+		if err != nil {
+			fmt.Printf("failed to add '%s/' to %s: %s\n", kubernetesDirectory, ignoreFile, err.Error())
+		}
+	}
+	return nil
+}
+
+// reloadKubernetesImage loads a rebuilt project image into the local cluster of the project
+func (d *DockerCompose) reloadKubernetesImage() error {
+	cluster, err := newLocalCluster(d.projectName)
+	if err != nil {
+		return err
+	}
+	return loadKubernetesImage(cluster, ImageName(d.projectName, "latest"))
+}
+
+func loadKubernetesImage(cluster localCluster, image string) error {
+	fmt.Printf("Loading image %s into the local Kubernetes cluster\n", image)
+	if err := cluster.LoadImage(image); err != nil {
+		return errors.Wrap(err, "error loading the project image into the local Kubernetes cluster")
+	}
+	return nil
+}
+
+// stopKubernetesCluster stops the local cluster of the project, if it exists
+func (d *DockerCompose) stopKubernetesCluster() error {
+	cluster, err := newLocalCluster(d.projectName)
+	if err != nil {
+		return err
+	}
+	exists, err := cluster.Exists()
+	if err != nil || !exists {
+		return err
+	}
+	return errors.Wrap(cluster.Stop(), "error stopping the local Kubernetes cluster")
+}
+
+// deleteKubernetesCluster deletes the local cluster of the project, if it exists, and its kubeconfig
+func (d *DockerCompose) deleteKubernetesCluster() error {
+	cluster, err := newLocalCluster(d.projectName)
+	if err != nil {
+		return err
+	}
+	exists, err := cluster.Exists()
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := cluster.Delete(); err != nil {
+			return errors.Wrap(err, "error deleting the local Kubernetes cluster")
+		}
+	}
+	return os.RemoveAll(filepath.Join(d.airflowHome, kubernetesDirectory))
+}
+
+// kubernetesClusterTracked checks whether the project has a local cluster. The cluster is tracked by its directory
+// rather than by the executor, so that it is still stopped and deleted once the project uses another executor.
+func (d *DockerCompose) kubernetesClusterTracked() bool {
+	_, err := os.Stat(filepath.Join(d.airflowHome, kubernetesDirectory))
+	return err == nil
+}
+
+// podEnvVar is a variable of the env file of the project, with its name and value quoted for the pod template
+type podEnvVar struct {
+	Name  string
+	Value string
+}
+
+// generatePodTemplate generates the pod template of the task pods
+func generatePodTemplate(projectName, image, envFile string, imageLabels map[string]string) (string, error) {
+	envVars, err := podEnvVars(envFile)
+	if err != nil {
+		return "", err
+	}
+	data := struct {
+		Image                 string
+		EnvVars               []podEnvVar
+		FernetKey             string
+		SQLAlchemyConn        string
+		ProjectName           string
+		ExecutionAPIServerURL string
+		ProjectDirs           []string
+	}{
+		Image:     image,
+		EnvVars:   envVars,
+		FernetKey: localFernetKey,
+		SQLAlchemyConn: fmt.Sprintf("postgresql://%s:%s@%s:5432",
+			config.CFG.PostgresUser.GetString(),
+			config.CFG.PostgresPassword.GetString(),
+			kubernetesHostAlias(projectName, PostgresDockerContainerName)),
+		ProjectName: projectName,
+		ProjectDirs: []string{"dags", "plugins", "include"},
+	}
+	if airflowversions.AirflowMajorVersionForRuntimeVersion(imageLabels[runtimeVersionLabelName]) == "3" {
+		data.ExecutionAPIServerURL = fmt.Sprintf("http://%s:8080/execution/", kubernetesHostAlias(projectName, APIServerDockerContainerName))
+	}
+
+	buff := new(bytes.Buffer)
+	if err := podTemplate.Execute(buff, data); err != nil {
+		return "", errors.Wrap(err, "failed to generate the pod template")
+	}
+	return buff.String(), nil
+}
+
+// podEnvVars reads the variables of the env file of the project, sorted by name, if the file exists
+func podEnvVars(envFile string) ([]podEnvVar, error) {
+	if envFile == "" {
+		return nil, nil
+	}
+	exists, err := fileutil.Exists(envFile, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, envPathMsg, envFile)
+	}
+	if !exists {
+		return nil, nil
+	}
+	vars, err := dotenv.Read(envFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading the env file %s", envFile)
+	}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	envVars := make([]podEnvVar, 0, len(names))
+	for _, name := range names {
+		// JSON strings are valid YAML strings, which keeps the values of any content in the template
+		quotedName, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		quotedValue, err := json.Marshal(vars[name])
+		if err != nil {
+			return nil, err
+		}
+		envVars = append(envVars, podEnvVar{Name: string(quotedName), Value: string(quotedValue)})
+	}
+	return envVars, nil
+}
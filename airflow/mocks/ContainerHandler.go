@@ -70,17 +70,17 @@ func (_m *ContainerHandler) ExportSettings(settingsFile string, envFile string,
 	return r0
 }
 
-// ImportSettings provides a mock function with given fields: settingsFile, envFile, connections, variables, pools
-func (_m *ContainerHandler) ImportSettings(settingsFile string, envFile string, connections bool, variables bool, pools bool) error {
-	ret := _m.Called(settingsFile, envFile, connections, variables, pools)
+// ImportSettings provides a mock function with given fields: settingsFile, envFile, connections, variables, pools, prune
+func (_m *ContainerHandler) ImportSettings(settingsFile string, envFile string, connections bool, variables bool, pools bool, prune bool) error {
+	ret := _m.Called(settingsFile, envFile, connections, variables, pools, prune)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ImportSettings")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, bool, bool, bool) error); ok {
-		r0 = rf(settingsFile, envFile, connections, variables, pools)
+	if rf, ok := ret.Get(0).(func(string, string, bool, bool, bool, bool) error); ok {
+		r0 = rf(settingsFile, envFile, connections, variables, pools, prune)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -106,6 +106,42 @@ func (_m *ContainerHandler) Kill() error {
 	return r0
 }
 
+// List provides a mock function with no fields
+func (_m *ContainerHandler) List() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListSnapshots provides a mock function with no fields
+func (_m *ContainerHandler) ListSnapshots() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSnapshots")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Logs provides a mock function with given fields: follow, containerNames
 func (_m *ContainerHandler) Logs(follow bool, containerNames ...string) error {
 	_va := make([]interface{}, len(containerNames))
@@ -131,17 +167,17 @@ func (_m *ContainerHandler) Logs(follow bool, containerNames ...string) error {
 	return r0
 }
 
-// PS provides a mock function with no fields
-func (_m *ContainerHandler) PS() error {
-	ret := _m.Called()
+// PS provides a mock function with given fields: outputFormat
+func (_m *ContainerHandler) PS(outputFormat string) error {
+	ret := _m.Called(outputFormat)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PS")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(outputFormat)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -195,6 +231,24 @@ func (_m *ContainerHandler) Pytest(pytestFile string, customImageName string, de
 	return r0, r1
 }
 
+// RestoreSnapshot provides a mock function with given fields: name
+func (_m *ContainerHandler) RestoreSnapshot(name string) error {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreSnapshot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Run provides a mock function with given fields: args, user
 func (_m *ContainerHandler) Run(args []string, user string) error {
 	ret := _m.Called(args, user)
@@ -231,6 +285,24 @@ func (_m *ContainerHandler) RunDAG(dagID string, settingsFile string, dagFile st
 	return r0
 }
 
+// SaveSnapshot provides a mock function with given fields: name
+func (_m *ContainerHandler) SaveSnapshot(name string) error {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSnapshot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields: imageName, settingsFile, composeFile, buildSecretString, noCache, noBrowser, waitTime, envConns
 func (_m *ContainerHandler) Start(imageName string, settingsFile string, composeFile string, buildSecretString string, noCache bool, noBrowser bool, waitTime time.Duration, envConns map[string]astrocore.EnvironmentObjectConnection) error {
 	ret := _m.Called(imageName, settingsFile, composeFile, buildSecretString, noCache, noBrowser, waitTime, envConns)
@@ -285,6 +357,24 @@ func (_m *ContainerHandler) UpgradeTest(runtimeVersion string, deploymentID stri
 	return r0
 }
 
+// Watch provides a mock function with given fields: settingsFile, composeFile, buildSecretString
+func (_m *ContainerHandler) Watch(settingsFile string, composeFile string, buildSecretString string) error {
+	ret := _m.Called(settingsFile, composeFile, buildSecretString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Watch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(settingsFile, composeFile, buildSecretString)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewContainerHandler creates a new instance of ContainerHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewContainerHandler(t interface {
@@ -0,0 +1,134 @@
+package airflow
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	airflowversions "github.com/astronomer/astro-cli/airflow_versions"
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/pkg/logger"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/pkg/errors"
+)
+
+const (
+	portInUseMsg = "Port %s is already in use, the %s of this project will use port %s instead\n"
+
+	uiPortComponent       = "Airflow UI"
+	postgresPortComponent = "Postgres database"
+)
+
+var errPortInUse = errors.New("port is already in use")
+
+// isPortAvailable checks whether a host port can be bound to by the local environment
+var isPortAvailable = func(port string) bool {
+	host := "127.0.0.1"
+	if config.CFG.AirflowExposePort.GetBool() {
+		host = ""
+	}
+	l, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
+
+// getFreePort asks the OS for a host port that is currently free
+var getFreePort = func() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+	return strconv.Itoa(l.Addr().(*net.TCPAddr).Port), nil
+}
+
+// portConfig is a config setting holding a host port
+type portConfig interface {
+	GetString() string
+	SetProjectString(value string) error
+}
+
+// uiPortConfig returns the config setting holding the host port of the Airflow UI for the given image
+func uiPortConfig(imageLabels map[string]string) portConfig {
+	if airflowversions.AirflowMajorVersionForRuntimeVersion(imageLabels[runtimeVersionLabelName]) == "3" {
+		return config.CFG.APIServerPort
+	}
+	return config.CFG.WebserverPort
+}
+
+// allocatePorts makes sure the host ports used by this project are free, so multiple projects
+// can run side by side. Ports taken by another process are swapped for a free port, which is
+// persisted in the project config so the project keeps the same URLs across restarts.
+func (d *DockerCompose) allocatePorts(imageLabels map[string]string, settingsFile string) error {
+	type portSetting struct {
+		cfg       portConfig
+		component string
+	}
+	portSettings := []portSetting{
+		{uiPortConfig(imageLabels), uiPortComponent},
+		{config.CFG.PostgresPort, postgresPortComponent},
+	}
+	if vaultPort := vaultPortConfig(settingsFile); vaultPort != nil {
+		portSettings = append(portSettings, portSetting{vaultPort, vaultPortComponent})
+	}
+
+	var projectPorts map[string]bool
+	for _, setting := range portSettings {
+		port := setting.cfg.GetString()
+		// leave anything other than a plain port number (e.g. host:port) untouched
+		if _, err := strconv.Atoi(port); err != nil {
+			continue
+		}
+		if isPortAvailable(port) {
+			continue
+		}
+		// the port may be held by the containers of this very project, in which case it is fine
+		if projectPorts == nil {
+			var err error
+			projectPorts, err = d.publishedPorts()
+			if err != nil {
+				return err
+			}
+		}
+		if projectPorts[port] {
+			continue
+		}
+		// without a project config the free port could not be saved, so the project would not keep it across restarts
+		if !config.ProjectConfigExists() {
+			return fmt.Errorf("%w: port %s of the %s, stop the process using it or set another port with 'astro config set'", errPortInUse, port, setting.component)
+		}
+		freePort, err := getFreePort()
+		if err != nil {
+			return errors.Wrapf(err, "error finding a free port for the %s", setting.component)
+		}
+		if err := setting.cfg.SetProjectString(freePort); err != nil {
+			return errors.Wrapf(err, "error saving port for the %s to the project config", setting.component)
+		}
+		fmt.Printf(portInUseMsg, port, setting.component, freePort)
+	}
+	return nil
+}
+
+// publishedPorts returns the host ports published by the containers of this project
+func (d *DockerCompose) publishedPorts() (map[string]bool, error) {
+	psInfo, err := d.composeService.Ps(context.Background(), d.projectName, api.PsOptions{
+		All: true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, composeStatusCheckErrMsg)
+	}
+	ports := map[string]bool{}
+	for i := range psInfo {
+		for _, publisher := range psInfo[i].Publishers {
+			if publisher.PublishedPort != 0 {
+				ports[strconv.Itoa(publisher.PublishedPort)] = true
+			}
+		}
+	}
+	logger.Debugf("ports published by project %s: %v", d.projectName, ports)
+	return ports, nil
+}
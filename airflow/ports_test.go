@@ -0,0 +1,112 @@
+package airflow
+
+import (
+	"github.com/astronomer/astro-cli/airflow/mocks"
+	"github.com/astronomer/astro-cli/config"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/stretchr/testify/mock"
+)
+
+func (s *Suite) TestAllocatePorts() {
+	origIsPortAvailable := isPortAvailable
+	origGetFreePort := getFreePort
+	defer func() {
+		isPortAvailable = origIsPortAvailable
+		getFreePort = origGetFreePort
+		testUtil.InitTestConfig(testUtil.LocalPlatform)
+	}()
+	mockDockerCompose := DockerCompose{projectName: "test"}
+
+	s.Run("keeps free ports", func() {
+		isPortAvailable = func(port string) bool { return true }
+		getFreePort = func() (string, error) {
+			s.Fail("no port should be allocated")
+			return "", nil
+		}
+		composeMock := new(mocks.DockerComposeAPI)
+		mockDockerCompose.composeService = composeMock
+
+		err := mockDockerCompose.allocatePorts(labels, "")
+		s.NoError(err)
+		composeMock.AssertExpectations(s.T())
+	})
+
+	s.Run("keeps ports published by the project", func() {
+		isPortAvailable = func(port string) bool { return false }
+		getFreePort = func() (string, error) {
+			s.Fail("no port should be allocated")
+			return "", nil
+		}
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{
+			{Name: "test-webserver", Publishers: api.PortPublishers{{PublishedPort: 8080}}},
+			{Name: "test-postgres", Publishers: api.PortPublishers{{PublishedPort: 5432}}},
+		}, nil).Once()
+		mockDockerCompose.composeService = composeMock
+
+		err := mockDockerCompose.allocatePorts(labels, "")
+		s.NoError(err)
+		composeMock.AssertExpectations(s.T())
+	})
+
+	s.Run("allocates ports taken by another project", func() {
+		config.CreateProjectConfig(s.T().TempDir())
+		isPortAvailable = func(port string) bool { return port != config.CFG.WebserverPort.GetString() }
+		getFreePort = func() (string, error) { return "18080", nil }
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{}, nil).Once()
+		mockDockerCompose.composeService = composeMock
+
+		err := mockDockerCompose.allocatePorts(labels, "")
+		s.NoError(err)
+		s.Equal("18080", config.CFG.WebserverPort.GetString())
+		composeMock.AssertExpectations(s.T())
+	})
+
+	s.Run("fails without a project config to save the allocated port", func() {
+		testUtil.InitTestConfig(testUtil.LocalPlatform)
+		isPortAvailable = func(port string) bool { return port != config.CFG.WebserverPort.GetString() }
+		getFreePort = func() (string, error) {
+			s.Fail("no port should be allocated")
+			return "", nil
+		}
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{}, nil).Once()
+		mockDockerCompose.composeService = composeMock
+
+		err := mockDockerCompose.allocatePorts(labels, "")
+		s.ErrorIs(err, errPortInUse)
+		s.ErrorContains(err, "port 8080 of the Airflow UI")
+		composeMock.AssertExpectations(s.T())
+	})
+
+	s.Run("compose ps failure", func() {
+		isPortAvailable = func(port string) bool { return false }
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return(nil, errMockDocker).Once()
+		mockDockerCompose.composeService = composeMock
+
+		err := mockDockerCompose.allocatePorts(labels, "")
+		s.ErrorIs(err, errMockDocker)
+		composeMock.AssertExpectations(s.T())
+	})
+
+	s.Run("free port failure", func() {
+		config.CreateProjectConfig(s.T().TempDir())
+		isPortAvailable = func(port string) bool { return false }
+		getFreePort = func() (string, error) { return "", errMockDocker }
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{}, nil).Once()
+		mockDockerCompose.composeService = composeMock
+
+		err := mockDockerCompose.allocatePorts(labels, "")
+		s.ErrorIs(err, errMockDocker)
+		composeMock.AssertExpectations(s.T())
+	})
+}
+
+func (s *Suite) TestUIPortConfig() {
+	s.Equal(config.CFG.WebserverPort, uiPortConfig(map[string]string{runtimeVersionLabelName: "12.0.0"}))
+	s.Equal(config.CFG.APIServerPort, uiPortConfig(map[string]string{runtimeVersionLabelName: "3.0-1"}))
+}
@@ -0,0 +1,125 @@
+package airflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/pkg/util"
+	"github.com/astronomer/astro-cli/settings"
+	"github.com/pkg/errors"
+)
+
+const (
+	localFilesystemSecretsBackendClass = "airflow.secrets.local_filesystem.LocalFilesystemBackend"
+	vaultSecretsBackendClass           = "airflow.providers.hashicorp.secrets.vault.VaultBackend"
+
+	containerAirflowHome = "/usr/local/airflow"
+
+	defaultVaultImage           = "hashicorp/vault:1.17"
+	defaultVaultToken           = "root"
+	defaultVaultMountPoint      = "secret"
+	defaultVaultConnectionsPath = "connections"
+	defaultVaultVariablesPath   = "variables"
+
+	vaultPortComponent = "Vault server"
+
+	secretsBackendFileNotFoundMsg = "secrets backend file %s not found"
+)
+
+// secretsBackendConfig is how a local secrets backend is wired into the docker-compose config
+type secretsBackendConfig struct {
+	// Backend and BackendKwargs are the values of AIRFLOW__SECRETS__BACKEND and AIRFLOW__SECRETS__BACKEND_KWARGS
+	Backend       string
+	BackendKwargs string
+	// Volumes are the files mounted into the Airflow containers
+	Volumes    []string
+	VaultImage string
+	VaultToken string
+}
+
+// localSecretsBackend returns the secrets backend declared in the settings file of the project, if any
+func localSecretsBackend(settingsFile string) (*settings.SecretsBackend, error) {
+	if settingsFile == "" {
+		return nil, nil
+	}
+	settingsFileExist, err := util.Exists("./" + settingsFile)
+	if err != nil || !settingsFileExist {
+		return nil, nil //nolint:nilerr
+	}
+	return settings.GetSecretsBackend(settingsFile)
+}
+
+// getSecretsBackendConfig maps a local secrets backend onto the Airflow secrets backend settings
+// and the extra containers or mounts it needs
+func getSecretsBackendConfig(airflowHome string, backend *settings.SecretsBackend) (*secretsBackendConfig, error) {
+	var kwargs map[string]string
+	cfg := &secretsBackendConfig{}
+	switch backend.Type {
+	case settings.SecretsBackendLocalFilesystem:
+		cfg.Backend = localFilesystemSecretsBackendClass
+		kwargs = map[string]string{}
+		files := []struct {
+			file, kwarg string
+		}{
+			{backend.ConnectionsFile, "connections_file_path"},
+			{backend.VariablesFile, "variables_file_path"},
+		}
+		for _, f := range files {
+			if f.file == "" {
+				continue
+			}
+			// docker would create a directory in place of a missing file
+			exists, err := util.Exists(filepath.Join(airflowHome, f.file))
+			if err != nil || !exists {
+				return nil, fmt.Errorf(secretsBackendFileNotFoundMsg, f.file)
+			}
+			containerPath := path.Join(containerAirflowHome, filepath.ToSlash(f.file))
+			kwargs[f.kwarg] = containerPath
+			cfg.Volumes = append(cfg.Volumes, fmt.Sprintf("%s:%s", filepath.Join(airflowHome, f.file), containerPath))
+		}
+	case settings.SecretsBackendVault:
+		cfg.Backend = vaultSecretsBackendClass
+		cfg.VaultImage = valueOrDefault(backend.Image, defaultVaultImage)
+		cfg.VaultToken = valueOrDefault(backend.Token, defaultVaultToken)
+		kwargs = map[string]string{
+			"url":              fmt.Sprintf("http://%s:8200", VaultDockerContainerName),
+			"token":            cfg.VaultToken,
+			"mount_point":      valueOrDefault(backend.MountPoint, defaultVaultMountPoint),
+			"connections_path": valueOrDefault(backend.ConnectionsPath, defaultVaultConnectionsPath),
+			"variables_path":   valueOrDefault(backend.VariablesPath, defaultVaultVariablesPath),
+		}
+	default:
+		return nil, errors.New("unsupported secrets backend type " + backend.Type)
+	}
+
+	kwargsJSON, err := json.Marshal(kwargs)
+	if err != nil {
+		return nil, errors.Wrap(err, "error generating secrets backend kwargs")
+	}
+	// quote the kwargs as a JSON string, which is also a valid YAML string
+	quotedKwargs, err := json.Marshal(string(kwargsJSON))
+	if err != nil {
+		return nil, errors.Wrap(err, "error generating secrets backend kwargs")
+	}
+	cfg.BackendKwargs = string(quotedKwargs)
+	return cfg, nil
+}
+
+// vaultPortConfig returns the config setting holding the host port of the Vault server, if the project runs one
+func vaultPortConfig(settingsFile string) portConfig {
+	backend, err := localSecretsBackend(settingsFile)
+	if err != nil || backend == nil || backend.Type != settings.SecretsBackendVault {
+		return nil
+	}
+	return config.CFG.VaultPort
+}
+
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
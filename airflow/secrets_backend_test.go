@@ -0,0 +1,83 @@
+package airflow
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/astronomer/astro-cli/config"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/astronomer/astro-cli/settings"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+func (s *Suite) TestGetSecretsBackendConfig() {
+	s.Run("local filesystem", func() {
+		airflowHome := s.T().TempDir()
+		s.NoError(os.MkdirAll(filepath.Join(airflowHome, "include", "secrets"), 0o755))
+		s.NoError(os.WriteFile(filepath.Join(airflowHome, "include", "secrets", "connections.yaml"), []byte(""), 0o644))
+
+		cfg, err := getSecretsBackendConfig(airflowHome, &settings.SecretsBackend{Type: settings.SecretsBackendLocalFilesystem, ConnectionsFile: "include/secrets/connections.yaml"})
+		s.NoError(err)
+		s.Equal(localFilesystemSecretsBackendClass, cfg.Backend)
+		s.Equal(`"{\"connections_file_path\":\"/usr/local/airflow/include/secrets/connections.yaml\"}"`, cfg.BackendKwargs)
+		s.Equal([]string{filepath.Join(airflowHome, "include", "secrets", "connections.yaml") + ":/usr/local/airflow/include/secrets/connections.yaml"}, cfg.Volumes)
+	})
+
+	s.Run("local filesystem with a missing file", func() {
+		_, err := getSecretsBackendConfig(s.T().TempDir(), &settings.SecretsBackend{Type: settings.SecretsBackendLocalFilesystem, VariablesFile: "variables.yaml"})
+		s.EqualError(err, "secrets backend file variables.yaml not found")
+	})
+
+	s.Run("vault defaults", func() {
+		cfg, err := getSecretsBackendConfig(s.T().TempDir(), &settings.SecretsBackend{Type: settings.SecretsBackendVault})
+		s.NoError(err)
+		s.Equal(vaultSecretsBackendClass, cfg.Backend)
+		s.Equal(defaultVaultImage, cfg.VaultImage)
+		s.Equal(defaultVaultToken, cfg.VaultToken)
+		s.Equal(`"{\"connections_path\":\"connections\",\"mount_point\":\"secret\",\"token\":\"root\",\"url\":\"http://vault:8200\",\"variables_path\":\"variables\"}"`, cfg.BackendKwargs)
+		s.Empty(cfg.Volumes)
+	})
+}
+
+func (s *Suite) TestGenerateConfigWithVault() {
+	fs := afero.NewMemMapFs()
+	configYaml := testUtil.NewTestConfig(testUtil.LocalPlatform)
+	s.NoError(afero.WriteFile(fs, config.HomeConfigFile, configYaml, 0o777))
+	config.InitConfig(fs)
+
+	settingsFile := "test_secrets_backend_settings.yaml"
+	s.NoError(os.WriteFile(settingsFile, []byte("airflow:\n  secrets_backend:\n    type: vault\n    token: test-token\n"), 0o644))
+	defer os.Remove(settingsFile)
+
+	tests := []struct {
+		runtimeVersion string
+		firstService   string
+	}{
+		{triggererAllowedRuntimeVersion, SchedulerDockerContainerName},
+		{"3.0-1", "db-migration"},
+	}
+	for _, tt := range tests {
+		s.Run(tt.runtimeVersion, func() {
+			cfg, err := generateConfig("test-project-name", "airflow_home", ".env", "", settingsFile, map[string]string{runtimeVersionLabelName: tt.runtimeVersion})
+			s.NoError(err)
+
+			var compose struct {
+				Env      map[string]string `yaml:"x-common-env-vars"`
+				Services map[string]struct {
+					Image       string            `yaml:"image"`
+					DependsOn   []string          `yaml:"depends_on"`
+					Ports       []string          `yaml:"ports"`
+					Environment map[string]string `yaml:"environment"`
+				} `yaml:"services"`
+			}
+			s.NoError(yaml.Unmarshal([]byte(cfg), &compose))
+			s.Equal(vaultSecretsBackendClass, compose.Env["AIRFLOW__SECRETS__BACKEND"])
+			s.Equal(`{"connections_path":"connections","mount_point":"secret","token":"test-token","url":"http://vault:8200","variables_path":"variables"}`, compose.Env["AIRFLOW__SECRETS__BACKEND_KWARGS"])
+			s.Equal(defaultVaultImage, compose.Services[VaultDockerContainerName].Image)
+			s.Equal([]string{"127.0.0.1:8200:8200"}, compose.Services[VaultDockerContainerName].Ports)
+			s.Equal("test-token", compose.Services[VaultDockerContainerName].Environment["VAULT_DEV_ROOT_TOKEN_ID"])
+			s.Contains(compose.Services[tt.firstService].DependsOn, VaultDockerContainerName)
+		})
+	}
+}
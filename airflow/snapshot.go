@@ -0,0 +1,235 @@
+package airflow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/docker"
+	"github.com/astronomer/astro-cli/pkg/logger"
+	"github.com/astronomer/astro-cli/pkg/spinner"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/docker/api/types/container"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	snapshotDir           = "snapshots"
+	snapshotExtension     = ".sql"
+	snapshotTimeFormat    = "20060102-150405"
+	snapshotDirPermission = 0o755
+
+	postgresNotRunningMsg = "postgres container not found, run astro dev start to start project"
+)
+
+var (
+	snapshotNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+	errInvalidSnapshotName = errors.New("snapshot names may only contain letters, numbers, '.', '_' and '-'")
+	errSnapshotNotFound    = errors.New("snapshot not found, run astro dev snapshot list to see the available snapshots")
+)
+
+// SaveSnapshot dumps the metadata database of the local Airflow environment into .astro/snapshots
+func (d *DockerCompose) SaveSnapshot(name string) error {
+	if name == "" {
+		name = time.Now().Format(snapshotTimeFormat)
+	}
+	snapshotFile, err := d.snapshotPath(name)
+	if err != nil {
+		return err
+	}
+
+	containerID, err := d.getContainerID(PostgresDockerContainerName)
+	if err != nil {
+		return err
+	}
+	if containerID == "" {
+		return errors.New(postgresNotRunningMsg)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(snapshotFile), snapshotDirPermission); err != nil {
+		return errors.Wrap(err, "error creating snapshot directory")
+	}
+	file, err := os.Create(snapshotFile)
+	if err != nil {
+		return errors.Wrap(err, "error creating snapshot file")
+	}
+	defer file.Close()
+
+	s := spinner.NewSpinner("Saving snapshot of the metadata database…")
+	if !logger.IsLevelEnabled(logrus.DebugLevel) {
+		s.Start()
+		defer s.Stop()
+	}
+
+	// --clean makes the dump drop existing objects, so it can be restored over a live database
+	dumpCmd := []string{"pg_dump", "--clean", "--if-exists", "-U", config.CFG.PostgresUser.GetString()}
+	err = d.execInContainer(containerID, dumpCmd, nil, file)
+	if err != nil {
+		file.Close()
+		os.Remove(snapshotFile)
+		return errors.Wrap(err, "error saving snapshot")
+	}
+
+	spinner.StopWithCheckmark(s, fmt.Sprintf("Snapshot %s saved", name))
+	return nil
+}
+
+// RestoreSnapshot restores the metadata database of the local Airflow environment from a snapshot.
+// The Airflow containers are stopped while the database is restored.
+func (d *DockerCompose) RestoreSnapshot(name string) error {
+	snapshotFile, err := d.snapshotPath(name)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(snapshotFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errSnapshotNotFound
+		}
+		return errors.Wrap(err, "error opening snapshot file")
+	}
+	defer file.Close()
+
+	containerID, err := d.getContainerID(PostgresDockerContainerName)
+	if err != nil {
+		return err
+	}
+	if containerID == "" {
+		return errors.New(postgresNotRunningMsg)
+	}
+
+	psInfo, err := d.composeService.Ps(context.Background(), d.projectName, api.PsOptions{
+		All: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, composeStatusCheckErrMsg)
+	}
+	var services []string
+	for i := range psInfo {
+		// the Vault dev server keeps its secrets in memory, so leave it running
+		if psInfo[i].Service == PostgresDockerContainerName || psInfo[i].Service == VaultDockerContainerName {
+			continue
+		}
+		if checkServiceState(psInfo[i].State, dockerStateUp) {
+			services = append(services, psInfo[i].Service)
+		}
+	}
+	sort.Strings(services)
+
+	s := spinner.NewSpinner("Restoring snapshot of the metadata database…")
+	if !logger.IsLevelEnabled(logrus.DebugLevel) {
+		s.Start()
+		defer s.Stop()
+	}
+
+	// Stop Airflow so it doesn't hold locks on the tables being restored
+	if len(services) > 0 {
+		err = d.composeService.Stop(context.Background(), d.projectName, api.StopOptions{Services: services})
+		if err != nil {
+			return errors.Wrap(err, composePauseErrMsg)
+		}
+	}
+
+	restoreCmd := []string{"psql", "-q", "-v", "ON_ERROR_STOP=1", "-U", config.CFG.PostgresUser.GetString()}
+	restoreErr := d.execInContainer(containerID, restoreCmd, file, io.Discard)
+
+	if len(services) > 0 {
+		err = d.composeService.Start(context.Background(), d.projectName, api.StartOptions{Services: services})
+		if err != nil {
+			return errors.Wrap(err, composeRecreateErrMsg)
+		}
+	}
+	if restoreErr != nil {
+		return errors.Wrap(restoreErr, "error restoring snapshot")
+	}
+
+	spinner.StopWithCheckmark(s, fmt.Sprintf("Snapshot %s restored", name))
+	return nil
+}
+
+// ListSnapshots lists the snapshots of the metadata database saved in this project
+func (d *DockerCompose) ListSnapshots() error {
+	entries, err := os.ReadDir(filepath.Join(d.airflowHome, config.ConfigDir, snapshotDir))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "error reading snapshot directory")
+	}
+
+	// Columns for table
+	infoColumns := []string{"Name", "Created", "Size"}
+
+	// Create a new tabwriter
+	tw := new(tabwriter.Writer)
+	tw.Init(os.Stdout, 0, 8, 2, '\t', tabwriter.AlignRight) //nolint:mnd
+
+	// Append data to table
+	fmt.Fprintln(tw, strings.Join(infoColumns, "\t"))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != snapshotExtension {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return errors.Wrap(err, "error reading snapshot")
+		}
+		data := []string{strings.TrimSuffix(entry.Name(), snapshotExtension), info.ModTime().Format(time.RFC3339), fmt.Sprintf("%d KB", info.Size()/1024)} //nolint:mnd
+		fmt.Fprintln(tw, strings.Join(data, "\t"))
+	}
+
+	// Flush to stdout
+	return tw.Flush()
+}
+
+func (d *DockerCompose) snapshotPath(name string) (string, error) {
+	if !snapshotNameRegex.MatchString(name) {
+		return "", errInvalidSnapshotName
+	}
+	return filepath.Join(d.airflowHome, config.ConfigDir, snapshotDir, name+snapshotExtension), nil
+}
+
+// execInContainer runs a command in a container, piping stdin into it and its output into stdout
+func (d *DockerCompose) execInContainer(containerID string, cmd []string, stdin io.Reader, stdout io.Writer) error {
+	execConfig := container.ExecOptions{
+		AttachStdin:  stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmd,
+	}
+	response, err := d.cliClient.ContainerExecCreate(context.Background(), containerID, execConfig)
+	if err != nil {
+		return err
+	}
+	if response.ID == "" {
+		return errors.New("exec ID is empty")
+	}
+
+	resp, err := d.cliClient.ContainerExecAttach(context.Background(), response.ID, container.ExecStartOptions{})
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	err = docker.ExecPipe(resp, stdin, stdout, &stderr)
+	if err != nil {
+		return err
+	}
+
+	inspect, err := d.cliClient.ContainerExecInspect(context.Background(), response.ID)
+	if err != nil {
+		return err
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("%s exited with code %d: %s", cmd[0], inspect.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
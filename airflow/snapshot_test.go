@@ -0,0 +1,193 @@
+package airflow
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/astronomer/astro-cli/airflow/mocks"
+	"github.com/docker/compose/v2/pkg/api"
+	docker_types "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/mock"
+)
+
+func multiplexedReader(stdout, stderr string) *bufio.Reader {
+	var buf bytes.Buffer
+	if stdout != "" {
+		stdcopy.NewStdWriter(&buf, stdcopy.Stdout).Write([]byte(stdout))
+	}
+	if stderr != "" {
+		stdcopy.NewStdWriter(&buf, stdcopy.Stderr).Write([]byte(stderr))
+	}
+	return bufio.NewReader(&buf)
+}
+
+func (s *Suite) TestDockerComposeSaveSnapshot() {
+	postgresContainer := api.ContainerSummary{ID: "test-postgres-id", Name: "test-postgres-1", Service: "postgres", State: "running"}
+
+	s.Run("success", func() {
+		airflowHome := s.T().TempDir()
+		mockDockerCompose := DockerCompose{projectName: "test", airflowHome: airflowHome}
+
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{postgresContainer}, nil).Once()
+		mockCLIClient := new(mocks.DockerCLIClient)
+		mockCLIClient.On("ContainerExecCreate", mock.Anything, "test-postgres-id", container.ExecOptions{AttachStdout: true, AttachStderr: true, Cmd: []string{"pg_dump", "--clean", "--if-exists", "-U", "postgres"}}).Return(docker_types.IDResponse{ID: "test-exec-id"}, nil).Once()
+		mockCLIClient.On("ContainerExecAttach", mock.Anything, "test-exec-id", container.ExecStartOptions{}).Return(docker_types.HijackedResponse{Reader: multiplexedReader("test dump", "")}, nil).Once()
+		mockCLIClient.On("ContainerExecInspect", mock.Anything, "test-exec-id").Return(container.ExecInspect{ExitCode: 0}, nil).Once()
+		mockDockerCompose.composeService = composeMock
+		mockDockerCompose.cliClient = mockCLIClient
+
+		err := mockDockerCompose.SaveSnapshot("before-upgrade")
+		s.NoError(err)
+
+		content, err := os.ReadFile(filepath.Join(airflowHome, ".astro", "snapshots", "before-upgrade.sql"))
+		s.NoError(err)
+		s.Equal("test dump", string(content))
+		composeMock.AssertExpectations(s.T())
+		mockCLIClient.AssertExpectations(s.T())
+	})
+
+	s.Run("pg_dump failure", func() {
+		airflowHome := s.T().TempDir()
+		mockDockerCompose := DockerCompose{projectName: "test", airflowHome: airflowHome}
+
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{postgresContainer}, nil).Once()
+		mockCLIClient := new(mocks.DockerCLIClient)
+		mockCLIClient.On("ContainerExecCreate", mock.Anything, "test-postgres-id", mock.Anything).Return(docker_types.IDResponse{ID: "test-exec-id"}, nil).Once()
+		mockCLIClient.On("ContainerExecAttach", mock.Anything, "test-exec-id", container.ExecStartOptions{}).Return(docker_types.HijackedResponse{Reader: multiplexedReader("", "connection refused")}, nil).Once()
+		mockCLIClient.On("ContainerExecInspect", mock.Anything, "test-exec-id").Return(container.ExecInspect{ExitCode: 1}, nil).Once()
+		mockDockerCompose.composeService = composeMock
+		mockDockerCompose.cliClient = mockCLIClient
+
+		err := mockDockerCompose.SaveSnapshot("broken")
+		s.ErrorContains(err, "connection refused")
+
+		_, err = os.Stat(filepath.Join(airflowHome, ".astro", "snapshots", "broken.sql"))
+		s.True(os.IsNotExist(err))
+		composeMock.AssertExpectations(s.T())
+		mockCLIClient.AssertExpectations(s.T())
+	})
+
+	s.Run("postgres not running", func() {
+		mockDockerCompose := DockerCompose{projectName: "test", airflowHome: s.T().TempDir()}
+
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{{ID: "test-scheduler-id", Name: "test-scheduler-1", Service: "scheduler"}}, nil).Once()
+		mockDockerCompose.composeService = composeMock
+
+		err := mockDockerCompose.SaveSnapshot("snapshot")
+		s.EqualError(err, postgresNotRunningMsg)
+		composeMock.AssertExpectations(s.T())
+	})
+
+	s.Run("invalid name", func() {
+		mockDockerCompose := DockerCompose{projectName: "test", airflowHome: s.T().TempDir()}
+
+		err := mockDockerCompose.SaveSnapshot("../escape")
+		s.ErrorIs(err, errInvalidSnapshotName)
+	})
+}
+
+func (s *Suite) TestDockerComposeRestoreSnapshot() {
+	s.Run("success", func() {
+		airflowHome := s.T().TempDir()
+		s.NoError(os.MkdirAll(filepath.Join(airflowHome, ".astro", "snapshots"), 0o755))
+		s.NoError(os.WriteFile(filepath.Join(airflowHome, ".astro", "snapshots", "before-upgrade.sql"), []byte("test dump"), 0o644))
+		mockDockerCompose := DockerCompose{projectName: "test", airflowHome: airflowHome}
+
+		conn, remote := net.Pipe()
+		defer conn.Close()
+		go io.Copy(io.Discard, remote) //nolint:errcheck
+
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{
+			{ID: "test-postgres-id", Name: "test-postgres-1", Service: "postgres", State: "running"},
+			{ID: "test-scheduler-id", Name: "test-scheduler-1", Service: "scheduler", State: "running"},
+			{ID: "test-triggerer-id", Name: "test-triggerer-1", Service: "triggerer", State: "exited"},
+		}, nil).Twice()
+		composeMock.On("Stop", mock.Anything, mockDockerCompose.projectName, api.StopOptions{Services: []string{"scheduler"}}).Return(nil).Once()
+		composeMock.On("Start", mock.Anything, mockDockerCompose.projectName, api.StartOptions{Services: []string{"scheduler"}}).Return(nil).Once()
+		mockCLIClient := new(mocks.DockerCLIClient)
+		mockCLIClient.On("ContainerExecCreate", mock.Anything, "test-postgres-id", container.ExecOptions{AttachStdin: true, AttachStdout: true, AttachStderr: true, Cmd: []string{"psql", "-q", "-v", "ON_ERROR_STOP=1", "-U", "postgres"}}).Return(docker_types.IDResponse{ID: "test-exec-id"}, nil).Once()
+		mockCLIClient.On("ContainerExecAttach", mock.Anything, "test-exec-id", container.ExecStartOptions{}).Return(docker_types.HijackedResponse{Conn: conn, Reader: multiplexedReader("", "")}, nil).Once()
+		mockCLIClient.On("ContainerExecInspect", mock.Anything, "test-exec-id").Return(container.ExecInspect{ExitCode: 0}, nil).Once()
+		mockDockerCompose.composeService = composeMock
+		mockDockerCompose.cliClient = mockCLIClient
+
+		err := mockDockerCompose.RestoreSnapshot("before-upgrade")
+		s.NoError(err)
+		composeMock.AssertExpectations(s.T())
+		mockCLIClient.AssertExpectations(s.T())
+	})
+
+	s.Run("snapshot not found", func() {
+		mockDockerCompose := DockerCompose{projectName: "test", airflowHome: s.T().TempDir()}
+
+		err := mockDockerCompose.RestoreSnapshot("missing")
+		s.ErrorIs(err, errSnapshotNotFound)
+	})
+
+	s.Run("postgres not running", func() {
+		airflowHome := s.T().TempDir()
+		s.NoError(os.MkdirAll(filepath.Join(airflowHome, ".astro", "snapshots"), 0o755))
+		s.NoError(os.WriteFile(filepath.Join(airflowHome, ".astro", "snapshots", "snapshot.sql"), []byte("test dump"), 0o644))
+		mockDockerCompose := DockerCompose{projectName: "test", airflowHome: airflowHome}
+
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{
+			{ID: "test-scheduler-id", Name: "test-scheduler-1", Service: "scheduler", State: "running"},
+		}, nil).Once()
+		mockDockerCompose.composeService = composeMock
+
+		err := mockDockerCompose.RestoreSnapshot("snapshot")
+		s.EqualError(err, postgresNotRunningMsg)
+		composeMock.AssertExpectations(s.T())
+	})
+
+	s.Run("compose stop failure", func() {
+		airflowHome := s.T().TempDir()
+		s.NoError(os.MkdirAll(filepath.Join(airflowHome, ".astro", "snapshots"), 0o755))
+		s.NoError(os.WriteFile(filepath.Join(airflowHome, ".astro", "snapshots", "snapshot.sql"), []byte("test dump"), 0o644))
+		mockDockerCompose := DockerCompose{projectName: "test", airflowHome: airflowHome}
+
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return([]api.ContainerSummary{
+			{ID: "test-postgres-id", Name: "test-postgres-1", Service: "postgres", State: "running"},
+			{ID: "test-scheduler-id", Name: "test-scheduler-1", Service: "scheduler", State: "running"},
+		}, nil).Twice()
+		composeMock.On("Stop", mock.Anything, mockDockerCompose.projectName, api.StopOptions{Services: []string{"scheduler"}}).Return(errMockDocker).Once()
+		mockDockerCompose.composeService = composeMock
+
+		err := mockDockerCompose.RestoreSnapshot("snapshot")
+		s.ErrorIs(err, errMockDocker)
+		composeMock.AssertExpectations(s.T())
+	})
+}
+
+func (s *Suite) TestDockerComposeListSnapshots() {
+	airflowHome := s.T().TempDir()
+	s.NoError(os.MkdirAll(filepath.Join(airflowHome, ".astro", "snapshots"), 0o755))
+	s.NoError(os.WriteFile(filepath.Join(airflowHome, ".astro", "snapshots", "before-upgrade.sql"), []byte("test dump"), 0o644))
+	s.NoError(os.WriteFile(filepath.Join(airflowHome, ".astro", "snapshots", "notes.txt"), []byte("not a snapshot"), 0o644))
+	mockDockerCompose := DockerCompose{projectName: "test", airflowHome: airflowHome}
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := mockDockerCompose.ListSnapshots()
+	s.NoError(err)
+
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = s.origStdout
+
+	s.Contains(string(out), "before-upgrade")
+	s.NotContains(string(out), "notes")
+}
@@ -0,0 +1,113 @@
+package airflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	tableOutputFormat = "table"
+	jsonOutputFormat  = "json"
+	yamlOutputFormat  = "yaml"
+
+	projectHealthy    = "healthy"
+	projectUnhealthy  = "unhealthy"
+	projectNotRunning = "not running"
+)
+
+var errInvalidOutputFormat = errors.New("invalid output format, the output format can be one of: table, json or yaml")
+
+// ProjectStatus is the machine-readable status of a local Airflow environment
+type ProjectStatus struct {
+	Project  string          `json:"project" yaml:"project"`
+	Health   string          `json:"health" yaml:"health"`
+	Services []ServiceStatus `json:"services" yaml:"services"`
+}
+
+// ServiceStatus is the machine-readable status of a container of a local Airflow environment
+type ServiceStatus struct {
+	Name        string       `json:"name" yaml:"name"`
+	ContainerID string       `json:"container_id" yaml:"container_id"`
+	State       string       `json:"state" yaml:"state"`
+	Health      string       `json:"health" yaml:"health"`
+	Ports       []PortStatus `json:"ports" yaml:"ports"`
+	Image       string       `json:"image" yaml:"image"`
+}
+
+// PortStatus is a port published by a container of a local Airflow environment
+type PortStatus struct {
+	HostIP        string `json:"host_ip" yaml:"host_ip"`
+	HostPort      int    `json:"host_port" yaml:"host_port"`
+	ContainerPort int    `json:"container_port" yaml:"container_port"`
+	Protocol      string `json:"protocol" yaml:"protocol"`
+}
+
+// getProjectStatus builds the status of a project from its containers. The overall health of the project
+// is that of its webserver or API server, checked the same way astro dev start does.
+func getProjectStatus(projectName string, psInfo []api.ContainerSummary) ProjectStatus {
+	status := ProjectStatus{
+		Project:  projectName,
+		Health:   projectNotRunning,
+		Services: make([]ServiceStatus, 0, len(psInfo)),
+	}
+	for i := range psInfo {
+		service := ServiceStatus{
+			Name:        psInfo[i].Service,
+			ContainerID: psInfo[i].ID,
+			State:       psInfo[i].State,
+			Health:      psInfo[i].Health,
+			Ports:       make([]PortStatus, 0, len(psInfo[i].Publishers)),
+			Image:       psInfo[i].Image,
+		}
+		for _, publisher := range psInfo[i].Publishers {
+			service.Ports = append(service.Ports, PortStatus{
+				HostIP:        publisher.URL,
+				HostPort:      publisher.PublishedPort,
+				ContainerPort: publisher.TargetPort,
+				Protocol:      publisher.Protocol,
+			})
+		}
+		status.Services = append(status.Services, service)
+
+		if service.Name != WebserverDockerContainerName && service.Name != APIServerDockerContainerName {
+			continue
+		}
+		if !checkServiceState(service.State, dockerStateUp) {
+			continue
+		}
+		status.Health = projectUnhealthy
+		for _, port := range service.Ports {
+			if port.HostPort != 0 && isWebserverHealthy(healthCheckURL(service.Name, strconv.Itoa(port.HostPort))) {
+				status.Health = projectHealthy
+				break
+			}
+		}
+	}
+	return status
+}
+
+func printProjectStatus(projectName string, psInfo []api.ContainerSummary, outputFormat string) error {
+	status := getProjectStatus(projectName, psInfo)
+
+	var out []byte
+	var err error
+	switch outputFormat {
+	case jsonOutputFormat:
+		out, err = json.MarshalIndent(status, "", "    ")
+	case yamlOutputFormat:
+		out, err = yaml.Marshal(status)
+	default:
+		return errInvalidOutputFormat
+	}
+	if err != nil {
+		return errors.Wrap(err, "error formatting project status")
+	}
+	fmt.Fprintln(os.Stdout, string(out))
+	return nil
+}
@@ -0,0 +1,101 @@
+package airflow
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/astronomer/astro-cli/airflow/mocks"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/stretchr/testify/mock"
+	"gopkg.in/yaml.v3"
+)
+
+var testProjectContainers = []api.ContainerSummary{
+	{ID: "test-postgres-id", Name: "test-postgres-1", Service: "postgres", State: "running", Image: "docker.io/postgres:12.6", Publishers: api.PortPublishers{{URL: "127.0.0.1", PublishedPort: 5432, TargetPort: 5432, Protocol: "tcp"}}},
+	{ID: "test-api-server-id", Name: "test-api-server-1", Service: "api-server", State: "running", Image: "test/airflow:latest", Publishers: api.PortPublishers{{URL: "127.0.0.1", PublishedPort: 8080, TargetPort: 8080, Protocol: "tcp"}}},
+	{ID: "test-scheduler-id", Name: "test-scheduler-1", Service: "scheduler", State: "running", Image: "test/airflow:latest"},
+}
+
+func (s *Suite) TestGetProjectStatus() {
+	origIsWebserverHealthy := isWebserverHealthy
+	defer func() { isWebserverHealthy = origIsWebserverHealthy }()
+
+	s.Run("healthy", func() {
+		isWebserverHealthy = func(url string) bool {
+			s.Equal("http://localhost:8080/api/v2/monitor/health", url)
+			return true
+		}
+		status := getProjectStatus("test", testProjectContainers)
+		s.Equal(projectHealthy, status.Health)
+		s.Len(status.Services, 3)
+		s.Equal(ServiceStatus{
+			Name:        "postgres",
+			ContainerID: "test-postgres-id",
+			State:       "running",
+			Ports:       []PortStatus{{HostIP: "127.0.0.1", HostPort: 5432, ContainerPort: 5432, Protocol: "tcp"}},
+			Image:       "docker.io/postgres:12.6",
+		}, status.Services[0])
+	})
+
+	s.Run("unhealthy", func() {
+		isWebserverHealthy = func(url string) bool { return false }
+		status := getProjectStatus("test", testProjectContainers)
+		s.Equal(projectUnhealthy, status.Health)
+	})
+
+	s.Run("not running", func() {
+		isWebserverHealthy = func(url string) bool {
+			s.Fail("stopped projects should not be health checked")
+			return false
+		}
+		status := getProjectStatus("test", []api.ContainerSummary{{ID: "test-webserver-id", Service: "webserver", State: "exited"}})
+		s.Equal(projectNotRunning, status.Health)
+	})
+}
+
+func (s *Suite) TestDockerComposePSOutput() {
+	origIsWebserverHealthy := isWebserverHealthy
+	isWebserverHealthy = func(url string) bool { return true }
+	defer func() { isWebserverHealthy = origIsWebserverHealthy }()
+	mockDockerCompose := DockerCompose{projectName: "test"}
+
+	for _, format := range []string{jsonOutputFormat, yamlOutputFormat} {
+		s.Run(format, func() {
+			composeMock := new(mocks.DockerComposeAPI)
+			composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return(testProjectContainers, nil).Once()
+			mockDockerCompose.composeService = composeMock
+
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := mockDockerCompose.PS(format)
+			s.NoError(err)
+
+			w.Close()
+			out, _ := io.ReadAll(r)
+			os.Stdout = s.origStdout
+
+			var status ProjectStatus
+			if format == jsonOutputFormat {
+				s.NoError(json.Unmarshal(out, &status))
+			} else {
+				s.NoError(yaml.Unmarshal(out, &status))
+			}
+			s.Equal("test", status.Project)
+			s.Equal(projectHealthy, status.Health)
+			s.Len(status.Services, 3)
+			composeMock.AssertExpectations(s.T())
+		})
+	}
+
+	s.Run("invalid format", func() {
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Ps", mock.Anything, mockDockerCompose.projectName, api.PsOptions{All: true}).Return(testProjectContainers, nil).Once()
+		mockDockerCompose.composeService = composeMock
+
+		err := mockDockerCompose.PS("xml")
+		s.ErrorIs(err, errInvalidOutputFormat)
+		composeMock.AssertExpectations(s.T())
+	})
+}
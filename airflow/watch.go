@@ -0,0 +1,154 @@
+package airflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/astronomer/astro-cli/pkg/logger"
+	"github.com/astronomer/astro-cli/pkg/spinner"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	watchStartMsg   = "Watching %s for changes. Press Ctrl+C to stop watching, your project will keep running.\n"
+	watchStopMsg    = "\nStopped watching project files"
+	watchChangeMsg  = "\nDetected changes in %s, rebuilding image…\n"
+	watchFailureMsg = "Unable to reload project, the Airflow containers keep running the previous image: %s\n"
+)
+
+// watchInterval is how often the watched files are checked for changes
+var watchInterval = 1 * time.Second
+
+type watchedFileState struct {
+	exists  bool
+	size    int64
+	modTime int64
+}
+
+// Watch rebuilds the project image whenever the Dockerfile, requirements.txt or packages.txt change
+// and recreates the Airflow containers with the new image. The Postgres container and its metadata
+// are left untouched. Watch blocks until it is interrupted.
+func (d *DockerCompose) Watch(settingsFile, composeFile, buildSecretString string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return d.watch(ctx, settingsFile, composeFile, buildSecretString)
+}
+
+func (d *DockerCompose) watch(ctx context.Context, settingsFile, composeFile, buildSecretString string) error {
+	files := []string{d.dockerfile, "requirements.txt", "packages.txt"}
+	lastStates := d.watchedFileStates(files)
+
+	fmt.Printf(watchStartMsg, strings.Join(files, ", "))
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println(watchStopMsg)
+			return nil
+		case <-ticker.C:
+			states := d.watchedFileStates(files)
+			var changed []string
+			for _, file := range files {
+				if states[file] != lastStates[file] {
+					changed = append(changed, file)
+				}
+			}
+			if len(changed) == 0 {
+				continue
+			}
+
+			fmt.Printf(watchChangeMsg, strings.Join(changed, ", "))
+			err := d.reload(settingsFile, composeFile, buildSecretString)
+			if err != nil {
+				fmt.Printf(watchFailureMsg, err.Error())
+			}
+			// building the image touches requirements.txt, so take a fresh snapshot afterwards
+			lastStates = d.watchedFileStates(files)
+		}
+	}
+}
+
+// reload rebuilds the project image and recreates all services but Postgres, Vault and Redis with it
+func (d *DockerCompose) reload(settingsFile, composeFile, buildSecretString string) error {
+	err := d.buildProjectImage(buildSecretString, false)
+	if err != nil {
+		return err
+	}
+
+	imageLabels, err := d.imageHandler.ListLabels()
+	if err != nil {
+		return err
+	}
+
+	// the task pods of the KubernetesExecutor run the image loaded into the local cluster
+	if kubernetesExecutorEnabled() {
+		err = d.reloadKubernetesImage()
+		if err != nil {
+			return err
+		}
+	}
+
+	s := spinner.NewSpinner("Recreating Airflow containers…")
+	if !logger.IsLevelEnabled(logrus.DebugLevel) {
+		s.Start()
+		defer s.Stop()
+	}
+
+	project, err := createDockerProject(d.projectName, d.airflowHome, d.envFile, "", settingsFile, composeFile, imageLabels)
+	if err != nil {
+		return errors.Wrap(err, composeCreateErrMsg)
+	}
+
+	var services []string
+	for _, name := range project.ServiceNames() {
+		// the Vault dev server and the Celery broker keep their state in memory, so they must not be recreated
+		if name != PostgresDockerContainerName && name != VaultDockerContainerName && name != RedisDockerContainerName {
+			services = append(services, name)
+		}
+	}
+	sort.Strings(services)
+
+	err = d.composeService.Up(context.Background(), project, api.UpOptions{
+		Create: api.CreateOptions{
+			Services:             services,
+			Recreate:             api.RecreateForce,
+			RecreateDependencies: api.RecreateNever,
+			QuietPull:            logger.IsLevelEnabled(logrus.DebugLevel),
+		},
+		Start: api.StartOptions{
+			Project:  project,
+			Services: services,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, composeRecreateErrMsg)
+	}
+
+	spinner.StopWithCheckmark(s, "Project reloaded with the new image")
+	return nil
+}
+
+func (d *DockerCompose) watchedFileStates(files []string) map[string]watchedFileState {
+	states := make(map[string]watchedFileState, len(files))
+	for _, file := range files {
+		info, err := os.Stat(filepath.Join(d.airflowHome, file))
+		if err != nil {
+			states[file] = watchedFileState{}
+			continue
+		}
+		states[file] = watchedFileState{exists: true, size: info.Size(), modTime: info.ModTime().UnixNano()}
+	}
+	return states
+}
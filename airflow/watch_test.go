@@ -0,0 +1,103 @@
+package airflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/astronomer/astro-cli/airflow/mocks"
+	airflowTypes "github.com/astronomer/astro-cli/airflow/types"
+	"github.com/astronomer/astro-cli/config"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/stretchr/testify/mock"
+)
+
+func (s *Suite) TestDockerComposeWatch() {
+	origWatchInterval := watchInterval
+	watchInterval = 10 * time.Millisecond
+	defer func() { watchInterval = origWatchInterval }()
+	config.CFG.DisableAstroRun.SetHomeString("true")
+	defer config.CFG.DisableAstroRun.SetHomeString("false")
+
+	s.Run("recreates airflow containers on requirements change", func() {
+		airflowHome := s.T().TempDir()
+		requirements := filepath.Join(airflowHome, "requirements.txt")
+		s.NoError(os.WriteFile(requirements, []byte(""), 0o644))
+		mockDockerCompose := DockerCompose{projectName: "test", airflowHome: airflowHome, dockerfile: "Dockerfile"}
+
+		imageHandler := new(mocks.ImageHandler)
+		imageHandler.On("Build", "Dockerfile", "", airflowTypes.ImageBuildConfig{Path: airflowHome}).Return(nil).Once()
+		imageHandler.On("ListLabels").Return(labels, nil).Once()
+
+		reloaded := make(chan api.UpOptions, 1)
+		composeMock := new(mocks.DockerComposeAPI)
+		composeMock.On("Up", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			reloaded <- args.Get(2).(api.UpOptions)
+		}).Return(nil).Once()
+
+		mockDockerCompose.composeService = composeMock
+		mockDockerCompose.imageHandler = imageHandler
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- mockDockerCompose.watch(ctx, "", "", "")
+		}()
+
+		// make sure the initial snapshot is taken before changing the file
+		time.Sleep(5 * watchInterval)
+		s.NoError(os.WriteFile(requirements, []byte("pandas\n"), 0o644))
+
+		select {
+		case opts := <-reloaded:
+			s.NotContains(opts.Create.Services, PostgresDockerContainerName)
+			s.Contains(opts.Create.Services, SchedulerDockerContainerName)
+			s.Equal(api.RecreateForce, opts.Create.Recreate)
+			s.Equal(api.RecreateNever, opts.Create.RecreateDependencies)
+		case <-time.After(5 * time.Second):
+			s.Fail("timed out waiting for the project to be reloaded")
+		}
+
+		cancel()
+		s.NoError(<-done)
+		imageHandler.AssertExpectations(s.T())
+		composeMock.AssertExpectations(s.T())
+	})
+
+	s.Run("keeps containers when the build fails", func() {
+		airflowHome := s.T().TempDir()
+		mockDockerCompose := DockerCompose{projectName: "test", airflowHome: airflowHome, dockerfile: "Dockerfile"}
+
+		built := make(chan struct{}, 1)
+		imageHandler := new(mocks.ImageHandler)
+		imageHandler.On("Build", "Dockerfile", "", airflowTypes.ImageBuildConfig{Path: airflowHome}).Run(func(args mock.Arguments) {
+			built <- struct{}{}
+		}).Return(errMockDocker).Once()
+
+		composeMock := new(mocks.DockerComposeAPI)
+
+		mockDockerCompose.composeService = composeMock
+		mockDockerCompose.imageHandler = imageHandler
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- mockDockerCompose.watch(ctx, "", "", "")
+		}()
+
+		time.Sleep(5 * watchInterval)
+		s.NoError(os.WriteFile(filepath.Join(airflowHome, "packages.txt"), []byte("git\n"), 0o644))
+
+		select {
+		case <-built:
+		case <-time.After(5 * time.Second):
+			s.Fail("timed out waiting for the image to be rebuilt")
+		}
+
+		cancel()
+		s.NoError(<-done)
+		imageHandler.AssertExpectations(s.T())
+		composeMock.AssertNotCalled(s.T(), "Up", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
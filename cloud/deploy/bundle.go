@@ -2,10 +2,14 @@ package deploy
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	airflowversions "github.com/astronomer/astro-cli/airflow_versions"
@@ -13,11 +17,19 @@ import (
 	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
 	"github.com/astronomer/astro-cli/cloud/deployment"
 	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/pkg/azure"
 	"github.com/astronomer/astro-cli/pkg/fileutil"
 	"github.com/astronomer/astro-cli/pkg/git"
 	"github.com/astronomer/astro-cli/pkg/logger"
+	"github.com/astronomer/astro-cli/pkg/printutil"
 )
 
+// tarballChecksumMetadataKey is the metadata of an uploaded tarball holding its SHA-256
+const tarballChecksumMetadataKey = azure.SHA256MetadataKey
+
+// bundleExcludedPaths are the paths of a bundle left out of its tarball
+var bundleExcludedPaths = []string{".git/"}
+
 type DeployBundleInput struct {
 	BundlePath         string
 	MountPath          string
@@ -141,6 +153,68 @@ func DeleteBundle(input *DeleteBundleInput) error {
 	return nil
 }
 
+type ListBundlesInput struct {
+	DeploymentID string
+	BundleType   string
+	CoreClient   astrocore.CoreClient
+}
+
+// ListBundles prints the bundles deployed to a Deployment, with their mount paths and versions. Only the bundles of the
+// given type are listed, if any.
+func ListBundles(input *ListBundlesInput, out io.Writer) error {
+	c, err := config.GetCurrentContext()
+	if err != nil {
+		return err
+	}
+
+	resp, err := input.CoreClient.GetDeploymentWithResponse(context.Background(), c.Organization, input.DeploymentID)
+	if err != nil {
+		return err
+	}
+	err = astrocore.NormalizeAPIError(resp.HTTPResponse, resp.Body)
+	if err != nil {
+		return err
+	}
+
+	bundles := []astrocore.Bundle{}
+	if resp.JSON200.Bundles != nil {
+		for _, bundle := range *resp.JSON200.Bundles {
+			if input.BundleType == "" || bundleField(bundle.BundleType) == input.BundleType {
+				bundles = append(bundles, bundle)
+			}
+		}
+	}
+	if len(bundles) == 0 {
+		fmt.Fprintf(out, "No bundles found in Deployment %s\n", resp.JSON200.Name)
+		return nil
+	}
+	sort.Slice(bundles, func(i, j int) bool { return bundleField(bundles[i].MountPath) < bundleField(bundles[j].MountPath) })
+
+	tab := printutil.Table{
+		Padding:        []int{50, 10, 30, 30, 30},
+		DynamicPadding: true,
+		Header:         []string{"MOUNT PATH", "TYPE", "CURRENT VERSION", "DESIRED VERSION", "DEPLOY ID"},
+	}
+	for i := range bundles {
+		tab.AddRow([]string{
+			bundleField(bundles[i].MountPath),
+			bundleField(bundles[i].BundleType),
+			bundleField(bundles[i].CurrentVersion),
+			bundleField(bundles[i].DesiredVersion),
+			bundleField(bundles[i].DeployId),
+		}, false)
+	}
+	return tab.Print(out)
+}
+
+// bundleField returns a field of a bundle for display, a bundle having no version until it is first deployed
+func bundleField(field *string) string {
+	if field == nil || *field == "" {
+		return "N/A"
+	}
+	return *field
+}
+
 // ValidateBundleSymlinks checks if any symlinks within the bundlePath point outside of it
 func ValidateBundleSymlinks(bundlePath string) error {
 	absBundlePath, err := filepath.Abs(bundlePath)
@@ -187,12 +261,33 @@ func ValidateBundleSymlinks(bundlePath string) error {
 	return nil
 }
 
+// bundleIgnoreMatcher returns the matcher of the .astroignore file of a bundle. The file is read from the directory the
+// tar paths are relative to: the project for DAG deploys, which prepend the DAGs folder to the paths, and the bundle
+// otherwise.
+// bundleIgnoreMatcher returns the matcher of the .astroignore file UploadBundle filters the files of a bundle with
+func bundleIgnoreMatcher(tarDirPath, bundlePath string, prependBaseDir bool) (*fileutil.IgnoreMatcher, error) {
+	ignoreDir := bundlePath
+	if prependBaseDir {
+		ignoreDir = tarDirPath
+	}
+	return fileutil.NewIgnoreMatcher(filepath.Join(ignoreDir, fileutil.AstroIgnoreFileName))
+}
+
+// UploadBundle uploads the files of a bundle as a tarball, with its SHA-256 attached as metadata, and returns the version
+// of the uploaded tarball
 func UploadBundle(tarDirPath, bundlePath, uploadURL string, prependBaseDir bool, currentRuntimeVersion string) (string, error) {
+	versionID, _, err := uploadBundle(tarDirPath, bundlePath, uploadURL, prependBaseDir, currentRuntimeVersion, "")
+	return versionID, err
+}
+
+// uploadBundle uploads the files of a bundle as a tarball like UploadBundle, unless the SHA-256 of the tarball is
+// unchangedChecksum. It returns the version of the uploaded tarball and whether it was uploaded.
+func uploadBundle(tarDirPath, bundlePath, uploadURL string, prependBaseDir bool, currentRuntimeVersion, unchangedChecksum string) (string, bool, error) {
 	// If Airflow 3.x, check for symlinks pointing outside the bundle directory
 	if airflowversions.AirflowMajorVersionForRuntimeVersion(currentRuntimeVersion) == "3" {
 		err := ValidateBundleSymlinks(bundlePath)
 		if err != nil {
-			return "", err
+			return "", false, err
 		}
 	}
 
@@ -212,30 +307,45 @@ func UploadBundle(tarDirPath, bundlePath, uploadURL string, prependBaseDir bool,
 		}
 	}()
 
+	ignore, err := bundleIgnoreMatcher(tarDirPath, bundlePath, prependBaseDir)
+	if err != nil {
+		return "", false, err
+	}
+
 	// Generate the bundle tar
-	err := fileutil.Tar(bundlePath, tarFilePath, prependBaseDir, []string{".git/"})
+	err = fileutil.TarWithIgnore(bundlePath, tarFilePath, prependBaseDir, bundleExcludedPaths, ignore)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	// Gzip the tar
 	err = fileutil.GzipFile(tarFilePath, tarGzFilePath)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	tarGzFile, err := os.Open(tarGzFilePath)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	defer tarGzFile.Close()
 
-	versionID, err := azureUploader(uploadURL, tarGzFile)
+	// the tarball is reproducible, so its SHA-256 identifies the deployed files
+	checksum, err := tarballChecksum(tarGzFile)
 	if err != nil {
-		return "", err
+		return "", false, err
+	}
+	fmt.Println("Tarball SHA-256: " + checksum)
+	if checksum == unchangedChecksum {
+		return "", false, nil
+	}
+
+	versionID, err := azureUploader(uploadURL, tarGzFile, map[string]string{tarballChecksumMetadataKey: checksum})
+	if err != nil {
+		return "", false, err
 	}
 
-	return versionID, nil
+	return versionID, true, nil
 }
 
 func createBundleDeploy(organizationID string, input *DeployBundleInput, deployGit *astrocore.DeployGit, coreClient astrocore.CoreClient) (*astrocore.Deploy, error) {
@@ -355,3 +465,15 @@ func retrieveLocalGitMetadata(bundlePath string) (deployGit *astrocore.DeployGit
 
 	return deployGit, message
 }
+
+// tarballChecksum returns the hex encoded SHA-256 of a file, leaving it at its start for the upload
+func tarballChecksum(file io.ReadSeeker) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
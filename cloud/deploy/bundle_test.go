@@ -1,6 +1,11 @@
 package deploy
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"io"
 	"net/http"
 	"os"
@@ -8,6 +13,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	astrocore "github.com/astronomer/astro-cli/astro-client-core"
 	astrocore_mocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
@@ -68,7 +74,7 @@ func (s *BundleSuite) TestBundleDeploy_Success() {
 	mockCreateDeploy(s.mockCoreClient, "http://bundle-upload-url", expectedDeploy)
 	mockUpdateDeploy(s.mockCoreClient, "version-id")
 
-	azureUploader = func(sasLink string, file io.Reader) (string, error) {
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
 		return "version-id", nil
 	}
 
@@ -146,7 +152,7 @@ func (s *BundleSuite) TestBundleDeploy_GitMetadataRetrieved() {
 	mockCreateDeploy(s.mockCoreClient, "http://bundle-upload-url", expectedDeploy)
 	mockUpdateDeploy(s.mockCoreClient, "version-id")
 
-	azureUploader = func(sasLink string, file io.Reader) (string, error) {
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
 		return "version-id", nil
 	}
 
@@ -179,7 +185,7 @@ func (s *BundleSuite) TestBundleDeploy_GitHasUncommittedChanges() {
 	mockCreateDeploy(s.mockCoreClient, "http://bundle-upload-url", expectedDeploy)
 	mockUpdateDeploy(s.mockCoreClient, "version-id")
 
-	azureUploader = func(sasLink string, file io.Reader) (string, error) {
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
 		return "version-id", nil
 	}
 
@@ -200,7 +206,7 @@ func (s *BundleSuite) TestBundleDeploy_BundleUploadUrlMissing() {
 
 	mockCreateDeploy(s.mockCoreClient, "", nil)
 
-	azureUploader = func(sasLink string, file io.Reader) (string, error) {
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
 		return "version-id", nil
 	}
 
@@ -229,6 +235,40 @@ func (s *BundleSuite) TestBundleDelete_Success() {
 	s.mockPlatformCoreClient.AssertExpectations(s.T())
 }
 
+func (s *BundleSuite) TestBundleList() {
+	sqlType, dbtType := "sql", "dbt"
+	sqlMountPath, dbtMountPath := "/usr/local/airflow/sql", "/usr/local/airflow/dbt/project"
+	sqlDeployID, dbtDeployID := "deploy-2", "deploy-1"
+	currentVersion, desiredVersion := "version-1", "version-2"
+	s.mockCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, "test-deployment-id").Return(&astrocore.GetDeploymentResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200: &astrocore.Deployment{
+			Id:   "test-deployment-id",
+			Name: "test-deployment",
+			Bundles: &[]astrocore.Bundle{
+				{BundleType: &sqlType, MountPath: &sqlMountPath, DeployId: &sqlDeployID, CurrentVersion: &currentVersion, DesiredVersion: &desiredVersion},
+				{BundleType: &dbtType, MountPath: &dbtMountPath, DeployId: &dbtDeployID, CurrentVersion: &currentVersion},
+			},
+		},
+	}, nil).Twice()
+
+	out := new(strings.Builder)
+	err := ListBundles(&ListBundlesInput{DeploymentID: "test-deployment-id", CoreClient: s.mockCoreClient}, out)
+	assert.NoError(s.T(), err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(s.T(), lines, 3)
+	assert.Contains(s.T(), lines[0], "MOUNT PATH")
+	assert.Regexp(s.T(), `/usr/local/airflow/dbt/project\s+dbt\s+version-1\s+N/A\s+deploy-1`, lines[1])
+	assert.Regexp(s.T(), `/usr/local/airflow/sql\s+sql\s+version-1\s+version-2\s+deploy-2`, lines[2])
+
+	out.Reset()
+	err = ListBundles(&ListBundlesInput{DeploymentID: "test-deployment-id", BundleType: "model", CoreClient: s.mockCoreClient}, out)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "No bundles found in Deployment test-deployment\n", out.String())
+
+	s.mockCoreClient.AssertExpectations(s.T())
+}
+
 func (s *BundleSuite) TestValidateBundleSymlinks() {
 	t := s.T()
 
@@ -418,3 +458,83 @@ func (s *BundleSuite) createTestGitRepository(withUncommittedFile bool) (sha, pa
 
 	return strings.TrimSpace(string(shaBytes)), dir
 }
+
+func (s *BundleSuite) TestUploadBundle_AstroIgnore() {
+	readTarNames := func(file io.Reader) []string {
+		gzipReader, err := gzip.NewReader(file)
+		s.Require().NoError(err)
+		tarReader := tar.NewReader(gzipReader)
+		names := []string{}
+		for {
+			header, err := tarReader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			s.Require().NoError(err)
+			names = append(names, header.Name)
+		}
+		return names
+	}
+
+	projectPath := s.T().TempDir()
+	dagsPath := filepath.Join(projectPath, "dags")
+	s.Require().NoError(os.MkdirAll(filepath.Join(dagsPath, "tests"), 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(dagsPath, "my_dag.py"), []byte("dag"), 0o600))
+	s.Require().NoError(os.WriteFile(filepath.Join(dagsPath, "analysis.ipynb"), []byte("notebook"), 0o600))
+	s.Require().NoError(os.WriteFile(filepath.Join(dagsPath, "tests", "test_dag.py"), []byte("test"), 0o600))
+
+	s.Run("DAG deploy reads the project .astroignore", func() {
+		s.Require().NoError(os.WriteFile(filepath.Join(projectPath, ".astroignore"), []byte("# local files\n*.ipynb\ndags/tests/\n"), 0o600))
+		var names []string
+		azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
+			names = readTarNames(file)
+			return "version-id", nil
+		}
+
+		_, err := UploadBundle(projectPath, dagsPath, "http://upload-url", true, "12.0.0")
+		s.NoError(err)
+		s.Equal([]string{"dags/my_dag.py"}, names)
+	})
+
+	s.Run("bundle deploy reads the bundle .astroignore", func() {
+		s.Require().NoError(os.WriteFile(filepath.Join(dagsPath, ".astroignore"), []byte("tests\n"), 0o600))
+		var names []string
+		azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
+			names = readTarNames(file)
+			return "version-id", nil
+		}
+
+		_, err := UploadBundle(projectPath, dagsPath, "http://upload-url", false, "12.0.0")
+		s.NoError(err)
+		s.ElementsMatch([]string{".astroignore", "analysis.ipynb", "my_dag.py"}, names)
+	})
+}
+
+func (s *BundleSuite) TestUploadBundle_Checksum() {
+	projectPath := s.T().TempDir()
+	dagsPath := filepath.Join(projectPath, "dags")
+	s.Require().NoError(os.MkdirAll(dagsPath, 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(dagsPath, "my_dag.py"), []byte("dag"), 0o600))
+
+	checksums := []string{}
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
+		data, err := io.ReadAll(file)
+		s.Require().NoError(err)
+		sum := sha256.Sum256(data)
+		s.Equal(hex.EncodeToString(sum[:]), metadata[tarballChecksumMetadataKey])
+		checksums = append(checksums, metadata[tarballChecksumMetadataKey])
+		return "version-id", nil
+	}
+
+	_, err := UploadBundle(projectPath, dagsPath, "http://upload-url", true, "12.0.0")
+	s.NoError(err)
+
+	// the same DAGs give the same tarball even when the files were touched
+	modTime := time.Now().Add(time.Hour)
+	s.Require().NoError(os.Chtimes(filepath.Join(dagsPath, "my_dag.py"), modTime, modTime))
+	_, err = UploadBundle(projectPath, dagsPath, "http://upload-url", true, "12.0.0")
+	s.NoError(err)
+
+	s.Len(checksums, 2)
+	s.Equal(checksums[0], checksums[1])
+}
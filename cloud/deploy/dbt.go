@@ -0,0 +1,155 @@
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/astronomer/astro-cli/airflow"
+	"github.com/astronomer/astro-cli/airflow/types"
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/pkg/fileutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	// dbtValidateImageName is the image built from the Astro project to validate the dbt project in
+	dbtValidateImageName = "astro-dbt-validate"
+	// dbtValidateMountDir is where the dbt project is mounted in the container validating it
+	dbtValidateMountDir = "/usr/local/airflow/dbt_validate"
+	// dbtValidateProfilesDir is where the directory of the dbt profiles is mounted, when outside of the dbt project
+	dbtValidateProfilesDir = "/tmp/dbt_validate_profiles"
+	// dbtValidateProjectDir is the copy of the mounted dbt project the validation runs in, so that the packages,
+	// artifacts and logs of dbt are not written to the dbt project
+	dbtValidateProjectDir = "/tmp/dbt_validate_project"
+
+	dbtProfilesFilename = "profiles.yml"
+	dbtProfilesDirEnv   = "DBT_PROFILES_DIR"
+)
+
+var (
+	errDbtValidationFailed         = errors.New("dbt project validation failed")
+	errDbtValidationNoAstroProject = errors.New("an Astro project is required to build the image validating the dbt project, use --astro-project-path to set it or --validate-image to validate the dbt project in another image")
+
+	// dbtMissingSourceRegex matches the error dbt reports for a model referencing a source that is not declared
+	dbtMissingSourceRegex = regexp.MustCompile(`Model '([^']+)' \(([^)]+)\) depends on a source named '([^']+)' which was not found`)
+)
+
+type ValidateDbtProjectInput struct {
+	DbtProjectPath string
+	// ProfilesDir is the directory of the profiles.yml of the project, found the way dbt finds it by default
+	ProfilesDir string
+	// AstroProjectPath is the Astro project whose image the dbt project is validated in, unless Image is set
+	AstroProjectPath string
+	// Image overrides the image dbt runs in, which must have dbt and the dbt adapter of the project installed
+	Image string
+}
+
+// ValidateDbtProject installs the packages of a dbt project, then parses and compiles it in a container of the image
+// of the Astro project, failing on the compilation errors of the project and reporting the models depending on
+// sources that are not declared
+func ValidateDbtProject(input *ValidateDbtProjectInput) error {
+	projectPath, err := filepath.Abs(input.DbtProjectPath)
+	if err != nil {
+		return err
+	}
+	profilesDir := input.ProfilesDir
+	if profilesDir == "" {
+		profilesDir, err = dbtProfilesDir(projectPath)
+		if err != nil {
+			return err
+		}
+	}
+	profilesDir, err = filepath.Abs(profilesDir)
+	if err != nil {
+		return err
+	}
+
+	imageHandler, err := dbtValidateImageHandler(input)
+	if err != nil {
+		return err
+	}
+
+	mountDirs := map[string]string{projectPath: dbtValidateMountDir}
+	containerProfilesDir := dbtValidateProjectDir
+	if profilesDir != projectPath {
+		mountDirs[profilesDir] = dbtValidateProfilesDir
+		containerProfilesDir = dbtValidateProfilesDir
+	}
+
+	// dbt runs in a copy of the project since dbt deps installs the packages in it, all the commands run in the same
+	// container for dbt parse and dbt compile to find those packages
+	dbtArgs := fmt.Sprintf("--project-dir %s --profiles-dir %s", dbtValidateProjectDir, containerProfilesDir)
+	script := strings.Join([]string{
+		fmt.Sprintf("cp -R %s %s", dbtValidateMountDir, dbtValidateProjectDir),
+		"dbt deps " + dbtArgs,
+		"dbt parse " + dbtArgs,
+		"dbt compile --no-introspect " + dbtArgs,
+	}, " && ")
+
+	out := new(bytes.Buffer)
+	writer := io.MultiWriter(os.Stdout, out)
+	err = imageHandler.RunCommand([]string{"bash", "-c", script}, mountDirs, writer, writer)
+	if err != nil {
+		if missingSources := dbtMissingSources(out.String()); missingSources != "" {
+			return fmt.Errorf("%w, models depend on sources that were not found:\n%s", errDbtValidationFailed, missingSources)
+		}
+		return fmt.Errorf("%w: %w", errDbtValidationFailed, err)
+	}
+	fmt.Println("dbt project validated successfully")
+	return nil
+}
+
+// dbtValidateImageHandler returns the image handler of the image to validate a dbt project in, building the image of
+// the Astro project unless another image is given
+func dbtValidateImageHandler(input *ValidateDbtProjectInput) (airflow.ImageHandler, error) {
+	if input.Image != "" {
+		fmt.Printf("Validating the dbt project in the image %s\n", input.Image)
+		return airflowImageHandler(input.Image), nil
+	}
+
+	isProjectDir, err := config.IsProjectDir(input.AstroProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	if !isProjectDir {
+		return nil, errDbtValidationNoAstroProject
+	}
+
+	imageHandler := airflowImageHandler(airflow.ImageName(dbtValidateImageName, "latest"))
+	fmt.Printf("Building the image of the Astro project %s to validate the dbt project in\n", input.AstroProjectPath)
+	err = imageHandler.Build("", "", types.ImageBuildConfig{Path: input.AstroProjectPath})
+	if err != nil {
+		return nil, err
+	}
+	return imageHandler, nil
+}
+
+// dbtProfilesDir returns the directory dbt reads the profiles.yml of a project from by default: the directory of the
+// DBT_PROFILES_DIR environment variable, else the dbt project when it has one, else ~/.dbt
+func dbtProfilesDir(projectPath string) (string, error) {
+	if dir := os.Getenv(dbtProfilesDirEnv); dir != "" {
+		return dir, nil
+	}
+	exists, err := fileutil.Exists(filepath.Join(projectPath, dbtProfilesFilename), nil)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return projectPath, nil
+	}
+	return filepath.Join(config.HomePath, ".dbt"), nil
+}
+
+// dbtMissingSources lists the models of a dbt output depending on sources that are not declared, one per line
+func dbtMissingSources(dbtOutput string) string {
+	var missingSources []string
+	for _, match := range dbtMissingSourceRegex.FindAllStringSubmatch(dbtOutput, -1) {
+		missingSources = append(missingSources, fmt.Sprintf("  - %s (%s): source '%s'", match[1], match[2], match[3]))
+	}
+	return strings.Join(missingSources, "\n")
+}
@@ -0,0 +1,171 @@
+package deploy
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/astronomer/astro-cli/airflow"
+	"github.com/astronomer/astro-cli/airflow/mocks"
+	"github.com/astronomer/astro-cli/airflow/types"
+	"github.com/astronomer/astro-cli/config"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testDbtMissingSourceOutput = `Encountered an error:
+Compilation Error
+  Model 'model.jaffle_shop.stg_orders' (models/staging/stg_orders.sql) depends on a source named 'jaffle_shop.orders' which was not found
+`
+
+func dbtValidateScript(profilesDir string) string {
+	dbtArgs := " --project-dir " + dbtValidateProjectDir + " --profiles-dir " + profilesDir
+	return "cp -R " + dbtValidateMountDir + " " + dbtValidateProjectDir +
+		" && dbt deps" + dbtArgs +
+		" && dbt parse" + dbtArgs +
+		" && dbt compile --no-introspect" + dbtArgs
+}
+
+func TestValidateDbtProject(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+	t.Setenv(dbtProfilesDirEnv, "")
+	projectPath := t.TempDir()
+	profilesDir := t.TempDir()
+	astroProjectPath := t.TempDir()
+	err := os.MkdirAll(filepath.Join(astroProjectPath, config.ConfigDir), os.ModePerm)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(astroProjectPath, config.ConfigDir, config.ConfigFileNameWithExt), []byte(""), os.ModePerm)
+	assert.NoError(t, err)
+	mountDirs := map[string]string{projectPath: dbtValidateMountDir, profilesDir: dbtValidateProfilesDir}
+	validateCmd := []string{"bash", "-c", dbtValidateScript(dbtValidateProfilesDir)}
+
+	t.Run("installs the packages, parses and compiles the project in the image of the Astro project", func(t *testing.T) {
+		var image string
+		mockImageHandler := new(mocks.ImageHandler)
+		mockImageHandler.On("Build", "", "", types.ImageBuildConfig{Path: astroProjectPath}).Return(nil).Once()
+		mockImageHandler.On("RunCommand", validateCmd, mountDirs, mock.Anything, mock.Anything).Return(nil).Once()
+		airflowImageHandler = func(imageName string) airflow.ImageHandler {
+			image = imageName
+			return mockImageHandler
+		}
+
+		err := ValidateDbtProject(&ValidateDbtProjectInput{DbtProjectPath: projectPath, ProfilesDir: profilesDir, AstroProjectPath: astroProjectPath})
+		assert.NoError(t, err)
+		assert.Equal(t, airflow.ImageName(dbtValidateImageName, "latest"), image)
+		mockImageHandler.AssertExpectations(t)
+	})
+
+	t.Run("validates the project in the image given instead", func(t *testing.T) {
+		var image string
+		mockImageHandler := new(mocks.ImageHandler)
+		mockImageHandler.On("RunCommand", validateCmd, mountDirs, mock.Anything, mock.Anything).Return(nil).Once()
+		airflowImageHandler = func(imageName string) airflow.ImageHandler {
+			image = imageName
+			return mockImageHandler
+		}
+
+		err := ValidateDbtProject(&ValidateDbtProjectInput{DbtProjectPath: projectPath, ProfilesDir: profilesDir, Image: "dbt-image"})
+		assert.NoError(t, err)
+		assert.Equal(t, "dbt-image", image)
+		mockImageHandler.AssertExpectations(t)
+	})
+
+	t.Run("requires an Astro project without an image", func(t *testing.T) {
+		airflowImageHandler = func(imageName string) airflow.ImageHandler {
+			t.Fatal("no image should be built")
+			return nil
+		}
+
+		err := ValidateDbtProject(&ValidateDbtProjectInput{DbtProjectPath: projectPath, AstroProjectPath: t.TempDir()})
+		assert.ErrorIs(t, err, errDbtValidationNoAstroProject)
+	})
+
+	t.Run("fails when the image of the Astro project does not build", func(t *testing.T) {
+		mockImageHandler := new(mocks.ImageHandler)
+		mockImageHandler.On("Build", "", "", types.ImageBuildConfig{Path: astroProjectPath}).Return(errMock).Once()
+		airflowImageHandler = func(imageName string) airflow.ImageHandler {
+			return mockImageHandler
+		}
+
+		err := ValidateDbtProject(&ValidateDbtProjectInput{DbtProjectPath: projectPath, AstroProjectPath: astroProjectPath})
+		assert.ErrorIs(t, err, errMock)
+		mockImageHandler.AssertExpectations(t)
+	})
+
+	t.Run("reads the profiles of the dbt project", func(t *testing.T) {
+		err := os.WriteFile(filepath.Join(projectPath, dbtProfilesFilename), []byte("jaffle_shop: {}\n"), os.ModePerm)
+		assert.NoError(t, err)
+		defer os.Remove(filepath.Join(projectPath, dbtProfilesFilename))
+
+		mockImageHandler := new(mocks.ImageHandler)
+		mockImageHandler.On("RunCommand", []string{"bash", "-c", dbtValidateScript(dbtValidateProjectDir)}, map[string]string{projectPath: dbtValidateMountDir}, mock.Anything, mock.Anything).Return(nil).Once()
+		airflowImageHandler = func(imageName string) airflow.ImageHandler {
+			return mockImageHandler
+		}
+
+		err = ValidateDbtProject(&ValidateDbtProjectInput{DbtProjectPath: projectPath, Image: "dbt-image"})
+		assert.NoError(t, err)
+		mockImageHandler.AssertExpectations(t)
+	})
+
+	t.Run("reports the models with missing sources", func(t *testing.T) {
+		mockImageHandler := new(mocks.ImageHandler)
+		mockImageHandler.On("RunCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(func(args []string, mountDirs map[string]string, stdout, stderr io.Writer) error {
+			_, err := stdout.Write([]byte(testDbtMissingSourceOutput))
+			assert.NoError(t, err)
+			return errMock
+		}).Once()
+		airflowImageHandler = func(imageName string) airflow.ImageHandler {
+			return mockImageHandler
+		}
+
+		err := ValidateDbtProject(&ValidateDbtProjectInput{DbtProjectPath: projectPath, Image: "dbt-image"})
+		assert.ErrorIs(t, err, errDbtValidationFailed)
+		assert.ErrorContains(t, err, "  - model.jaffle_shop.stg_orders (models/staging/stg_orders.sql): source 'jaffle_shop.orders'")
+		mockImageHandler.AssertExpectations(t)
+	})
+
+	t.Run("fails on compilation errors", func(t *testing.T) {
+		mockImageHandler := new(mocks.ImageHandler)
+		mockImageHandler.On("RunCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errMock).Once()
+		airflowImageHandler = func(imageName string) airflow.ImageHandler {
+			return mockImageHandler
+		}
+
+		err := ValidateDbtProject(&ValidateDbtProjectInput{DbtProjectPath: projectPath, Image: "dbt-image"})
+		assert.ErrorIs(t, err, errDbtValidationFailed)
+		assert.ErrorIs(t, err, errMock)
+		mockImageHandler.AssertExpectations(t)
+	})
+}
+
+func TestDbtProfilesDir(t *testing.T) {
+	projectPath := t.TempDir()
+
+	t.Run("defaults to the dbt directory of the home directory", func(t *testing.T) {
+		t.Setenv(dbtProfilesDirEnv, "")
+		dir, err := dbtProfilesDir(projectPath)
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(config.HomePath, ".dbt"), dir)
+	})
+
+	t.Run("uses the dbt project when it has profiles", func(t *testing.T) {
+		t.Setenv(dbtProfilesDirEnv, "")
+		err := os.WriteFile(filepath.Join(projectPath, dbtProfilesFilename), []byte("jaffle_shop: {}\n"), os.ModePerm)
+		assert.NoError(t, err)
+		defer os.Remove(filepath.Join(projectPath, dbtProfilesFilename))
+
+		dir, err := dbtProfilesDir(projectPath)
+		assert.NoError(t, err)
+		assert.Equal(t, projectPath, dir)
+	})
+
+	t.Run("uses the DBT_PROFILES_DIR environment variable", func(t *testing.T) {
+		t.Setenv(dbtProfilesDirEnv, "/profiles")
+		dir, err := dbtProfilesDir(projectPath)
+		assert.NoError(t, err)
+		assert.Equal(t, "/profiles", dir)
+	})
+}
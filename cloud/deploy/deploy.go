@@ -59,6 +59,7 @@ var (
 	airflowImageHandler  = airflow.ImageHandlerInit
 	containerHandlerInit = airflow.ContainerHandlerInit
 	azureUploader        = azure.Upload
+	azureBlobChecksum    = azure.BlobChecksum
 	canCiCdDeploy        = deployment.CanCiCdDeploy
 	dagTarballVersion    = ""
 	dagsUploadURL        = ""
@@ -68,6 +69,7 @@ var (
 var (
 	errDagsParseFailed = errors.New("your local DAGs did not parse. Fix the listed errors or use `astro deploy [deployment-id] -f` to force deploy") //nolint:revive
 	envFileMissing     = errors.New("Env file path is incorrect: ")                                                                                  //nolint:revive
+	errDeployCanceled  = errors.New("deploy canceled")
 )
 
 var (
@@ -108,6 +110,15 @@ type InputDeploy struct {
 	Description       string
 	BuildSecretString string
 	ForceUpgradeToAF3 bool
+	DryRun            bool
+	Force             bool
+	ForceUpload       bool
+	SkipLock          bool
+	WaitForLock       bool
+	SBOM              bool
+	SBOMFormat        string
+	Sign              bool
+	SignKey           string
 }
 
 const accessYourDeploymentFmt = `
@@ -155,7 +166,10 @@ func shouldIncludeMonitoringDag(deploymentType astroplatformcore.DeploymentType)
 	return !organization.IsOrgHosted() && !deployment.IsDeploymentDedicated(deploymentType) && !deployment.IsDeploymentStandard(deploymentType)
 }
 
-func deployDags(path, dagsPath, dagsUploadURL, currentRuntimeVersion string, deploymentType astroplatformcore.DeploymentType) (string, error) {
+// deployDags uploads the DAGs of a project and returns the version of the uploaded tarball. The tarball is reproducible,
+// so the upload is skipped when it is the tarball of deployedDagTarballVersion, which is then returned, unless
+// forceUpload is set.
+func deployDags(path, dagsPath, dagsUploadURL, currentRuntimeVersion string, deploymentType astroplatformcore.DeploymentType, deployedDagTarballVersion string, forceUpload bool) (string, error) {
 	if shouldIncludeMonitoringDag(deploymentType) {
 		monitoringDagPath := filepath.Join(dagsPath, "astronomer_monitoring_dag.py")
 
@@ -169,20 +183,48 @@ func deployDags(path, dagsPath, dagsUploadURL, currentRuntimeVersion string, dep
 		defer os.Remove(monitoringDagPath)
 	}
 
-	versionID, err := UploadBundle(path, dagsPath, dagsUploadURL, true, currentRuntimeVersion)
+	var deployedChecksum string
+	if !forceUpload {
+		deployedChecksum = deployedDagsChecksum(dagsUploadURL, deployedDagTarballVersion)
+	}
+	versionID, uploaded, err := uploadBundle(path, dagsPath, dagsUploadURL, true, currentRuntimeVersion, deployedChecksum)
 	if err != nil {
 		return "", err
 	}
-
+	if !uploaded {
+		fmt.Printf("No DAG changes since the deploy of the DAG bundle %s. Skipping the DAG upload, use --force-upload to upload the DAGs anyway\n", ansi.Bold(deployedDagTarballVersion))
+		return deployedDagTarballVersion, nil
+	}
 	return versionID, nil
 }
 
+// deployedDagsChecksum returns the SHA-256 of the DAG tarball of a Deployment, read from the blob of its upload URL. It
+// is empty when the blob is not the tarball of dagTarballVersion or can't be read, like with a write-only URL.
+func deployedDagsChecksum(dagsUploadURL, dagTarballVersion string) string {
+	if dagTarballVersion == "" {
+		return ""
+	}
+	versionID, checksum, err := azureBlobChecksum(dagsUploadURL)
+	if err != nil {
+		logger.Debugf("Failed to read the SHA-256 of the deployed DAGs: %s", err)
+		return ""
+	}
+	if versionID != dagTarballVersion {
+		return ""
+	}
+	return checksum
+}
+
 // Deploy pushes a new docker image
 func Deploy(deployInput InputDeploy, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient) error { //nolint
 	c, err := config.GetCurrentContext()
 	if err != nil {
 		return err
 	}
+	err = checkProvenanceInput(deployInput)
+	if err != nil {
+		return err
+	}
 
 	var dagsPath string
 	if deployInput.DagsPath != "" {
@@ -220,6 +262,26 @@ func Deploy(deployInput InputDeploy, platformCoreClient astroplatformcore.CoreCl
 		}
 	}
 
+	if deployInput.DryRun {
+		return printDeployPlan(deployInput, deployInfo, dagsPath, platformCoreClient, os.Stdout)
+	}
+
+	// list the DAGs changed since the last DAG deploy to the Deployment from this project
+	var dagManifestFiles map[string]string
+	if deployInfo.dagDeployEnabled && !deployInput.Image {
+		dagManifestFiles, err = checkDagChanges(deployInput.Path, dagsPath, deployInfo, os.Stdout)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !deployInput.SkipLock {
+		err = checkDeployLock(deployInfo, deployInput.WaitForLock, coreClient)
+		if err != nil {
+			return err
+		}
+	}
+
 	deploymentURL, err := deployment.GetDeploymentURL(deployInfo.deploymentID, deployInfo.workspaceID)
 	if err != nil {
 		return err
@@ -239,6 +301,12 @@ func Deploy(deployInput InputDeploy, platformCoreClient astroplatformcore.CoreCl
 	if err != nil {
 		return err
 	}
+	finalized := false
+	defer func() {
+		if !finalized {
+			warnUnfinalizedDeploy(deploy.Id)
+		}
+	}()
 	deployID := deploy.Id
 	if deploy.DagsUploadUrl != nil {
 		dagsUploadURL = *deploy.DagsUploadUrl
@@ -250,6 +318,17 @@ func Deploy(deployInput InputDeploy, platformCoreClient astroplatformcore.CoreCl
 	} else {
 		nextTag = ""
 	}
+	// the pre-deploy hooks run once the deploy is created, so they get its ID and image tag
+	hookEnv := deployHookEnv{
+		deploymentID:   deployInfo.deploymentID,
+		deploymentName: deployInfo.name,
+		deployID:       deployID,
+		imageTag:       nextTag,
+	}
+	err = runDeployHooks(preDeployHook, config.CFG.DeployPreHooks.GetProjectStringSlice(), deployInput.Path, hookEnv)
+	if err != nil {
+		return err
+	}
 
 	if deployInput.Dags {
 		if len(dagFiles) == 0 && config.CFG.ShowWarnings.GetBool() {
@@ -277,7 +356,7 @@ func Deploy(deployInput InputDeploy, platformCoreClient astroplatformcore.CoreCl
 		}
 
 		fmt.Println("Initiating DAG deploy for: " + deployInfo.deploymentID)
-		dagTarballVersion, err = deployDags(deployInput.Path, dagsPath, dagsUploadURL, deployInfo.currentVersion, astroplatformcore.DeploymentType(deployInfo.deploymentType))
+		dagTarballVersion, err = deployDags(deployInput.Path, dagsPath, dagsUploadURL, deployInfo.currentVersion, astroplatformcore.DeploymentType(deployInfo.deploymentType), deployInfo.desiredDagTarballVersion, deployInput.ForceUpload)
 		if err != nil {
 			if strings.Contains(err.Error(), dagDeployDisabled) {
 				return fmt.Errorf(enableDagDeployMsg, deployInfo.deploymentID) //nolint
@@ -291,6 +370,8 @@ func Deploy(deployInput InputDeploy, platformCoreClient astroplatformcore.CoreCl
 		if err != nil {
 			return err
 		}
+		finalized = true
+		recordDagDeploy(deployInput.Path, deployInfo.deploymentID, dagTarballVersion, dagManifestFiles)
 
 		if deployInput.WaitForStatus {
 			// Keeping wait timeout low since dag only deploy is faster
@@ -304,7 +385,8 @@ func Deploy(deployInput InputDeploy, platformCoreClient astroplatformcore.CoreCl
 					fmt.Sprintf(accessYourDeploymentFmt, ansi.Bold(deploymentURL), ansi.Bold(deployInfo.webserverURL)),
 			)
 
-			return nil
+			hookEnv.dagTarballVersion = dagTarballVersion
+			return runDeployHooks(postDeployHook, config.CFG.DeployPostHooks.GetProjectStringSlice(), deployInput.Path, hookEnv)
 		}
 
 		fmt.Println(
@@ -318,17 +400,9 @@ func Deploy(deployInput InputDeploy, platformCoreClient astroplatformcore.CoreCl
 				),
 		)
 	} else {
-		fullpath := filepath.Join(deployInput.Path, ".dockerignore")
-		fileExist, _ := fileutil.Exists(fullpath, nil)
-		if fileExist {
-			err := removeDagsFromDockerIgnore(fullpath)
-			if err != nil {
-				return errors.Wrap(err, "Found dags entry in .dockerignore file. Remove this entry and try again")
-			}
-		}
-		envFileExists, _ := fileutil.Exists(deployInput.EnvFile, nil)
-		if !envFileExists && deployInput.EnvFile != ".env" {
-			return fmt.Errorf("%w %s", envFileMissing, deployInput.EnvFile)
+		err = checkImageDeployInput(deployInput)
+		if err != nil {
+			return err
 		}
 
 		if deployInfo.dagDeployEnabled && len(dagFiles) == 0 && config.CFG.ShowWarnings.GetBool() && !deployInput.Image {
@@ -360,14 +434,18 @@ func Deploy(deployInput InputDeploy, platformCoreClient astroplatformcore.CoreCl
 		remoteImage := fmt.Sprintf("%s:%s", repository, nextTag)
 
 		imageHandler := airflowImageHandler(deployInfo.deployImage)
-		_, err = imageHandler.Push(remoteImage, registryUsername, c.Token, false)
+		imageDigest, err := imageHandler.Push(remoteImage, registryUsername, c.Token, deployInput.SBOM || deployInput.Sign)
+		if err != nil {
+			return err
+		}
+		err = attachProvenance(deployInput, imageHandler, repository, imageDigest, c.Token)
 		if err != nil {
 			return err
 		}
 
 		if deployInfo.dagDeployEnabled && len(dagFiles) > 0 {
 			if !deployInput.Image {
-				dagTarballVersion, err = deployDags(deployInput.Path, dagsPath, dagsUploadURL, deployInfo.currentVersion, astroplatformcore.DeploymentType(deployInfo.deploymentType))
+				dagTarballVersion, err = deployDags(deployInput.Path, dagsPath, dagsUploadURL, deployInfo.currentVersion, astroplatformcore.DeploymentType(deployInfo.deploymentType), deployInfo.desiredDagTarballVersion, deployInput.ForceUpload)
 				if err != nil {
 					return err
 				}
@@ -380,6 +458,8 @@ func Deploy(deployInput InputDeploy, platformCoreClient astroplatformcore.CoreCl
 		if err != nil {
 			return err
 		}
+		finalized = true
+		recordDagDeploy(deployInput.Path, deployInfo.deploymentID, dagTarballVersion, dagManifestFiles)
 
 		if deployInput.WaitForStatus {
 			err = deployment.HealthPoll(deployInfo.deploymentID, deployInfo.workspaceID, sleepTime, tickNum, timeoutNum, platformCoreClient)
@@ -392,6 +472,24 @@ func Deploy(deployInput InputDeploy, platformCoreClient astroplatformcore.CoreCl
 			fmt.Sprintf(accessYourDeploymentFmt, ansi.Bold("https://"+deploymentURL), ansi.Bold("https://"+deployInfo.webserverURL)))
 	}
 
+	hookEnv.dagTarballVersion = dagTarballVersion
+	return runDeployHooks(postDeployHook, config.CFG.DeployPostHooks.GetProjectStringSlice(), deployInput.Path, hookEnv)
+}
+
+// checkImageDeployInput checks the project is ready for an image deploy
+func checkImageDeployInput(deployInput InputDeploy) error {
+	fullpath := filepath.Join(deployInput.Path, ".dockerignore")
+	fileExist, _ := fileutil.Exists(fullpath, nil)
+	if fileExist {
+		err := removeDagsFromDockerIgnore(fullpath)
+		if err != nil {
+			return errors.Wrap(err, "Found dags entry in .dockerignore file. Remove this entry and try again")
+		}
+	}
+	envFileExists, _ := fileutil.Exists(deployInput.EnvFile, nil)
+	if !envFileExists && deployInput.EnvFile != ".env" {
+		return fmt.Errorf("%w %s", envFileMissing, deployInput.EnvFile)
+	}
 	return nil
 }
 
@@ -568,6 +666,7 @@ func getImageName(deploymentID, organizationID string, platformCoreClient astrop
 		dagDeployEnabled:         dagDeployEnabled,
 		desiredDagTarballVersion: desiredDagTarballVersion,
 		cicdEnforcement:          cicdEnforcement,
+		name:                     resp.JSON200.Name,
 	}, nil
 }
 
@@ -676,26 +775,16 @@ func buildImage(path, currentVersion, deployImage, imageName, organizationID, bu
 
 	if config.CFG.ShowWarnings.GetBool() && version == "" {
 		fmt.Printf(warningInvalidImageNameMsg, DockerfileImage)
-		fmt.Println("Canceling deploy...")
-		os.Exit(1)
+		return "", errDeployCanceled
 	}
 
-	resp, err := platformCoreClient.GetDeploymentOptionsWithResponse(httpContext.Background(), organizationID, &astroplatformcore.GetDeploymentOptionsParams{})
-	if err != nil {
-		return "", err
-	}
-	err = astrocore.NormalizeAPIError(resp.HTTPResponse, resp.Body)
+	deploymentOptionsRuntimeVersions, err := getDeploymentOptionsRuntimeVersions(organizationID, platformCoreClient)
 	if err != nil {
 		return "", err
 	}
-	deploymentOptionsRuntimeVersions := []string{}
-	for _, runtimeRelease := range resp.JSON200.RuntimeReleases {
-		deploymentOptionsRuntimeVersions = append(deploymentOptionsRuntimeVersions, runtimeRelease.Version)
-	}
 
 	if !ValidRuntimeVersion(currentVersion, version, deploymentOptionsRuntimeVersions, forceUpgradeToAF3) {
-		fmt.Println("Canceling deploy...")
-		os.Exit(1)
+		return "", errDeployCanceled
 	}
 
 	WarnIfNonLatestVersion(version, httputil.NewHTTPClient())
@@ -703,6 +792,23 @@ func buildImage(path, currentVersion, deployImage, imageName, organizationID, bu
 	return version, nil
 }
 
+// getDeploymentOptionsRuntimeVersions returns the Runtime versions a Deployment of the organization can use
+func getDeploymentOptionsRuntimeVersions(organizationID string, platformCoreClient astroplatformcore.CoreClient) ([]string, error) {
+	resp, err := platformCoreClient.GetDeploymentOptionsWithResponse(httpContext.Background(), organizationID, &astroplatformcore.GetDeploymentOptionsParams{})
+	if err != nil {
+		return nil, err
+	}
+	err = astrocore.NormalizeAPIError(resp.HTTPResponse, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	deploymentOptionsRuntimeVersions := []string{}
+	for _, runtimeRelease := range resp.JSON200.RuntimeReleases {
+		deploymentOptionsRuntimeVersions = append(deploymentOptionsRuntimeVersions, runtimeRelease.Version)
+	}
+	return deploymentOptionsRuntimeVersions, nil
+}
+
 // finalize deploy
 func finalizeDeploy(deployID, deploymentID, organizationID, dagTarballVersion string, dagDeploy bool, platformCoreClient astroplatformcore.CoreClient) error {
 	finalizeDeployRequest := astroplatformcore.FinalizeDeployRequest{}
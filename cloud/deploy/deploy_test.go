@@ -11,6 +11,7 @@ import (
 
 	"github.com/astronomer/astro-cli/airflow"
 	"github.com/astronomer/astro-cli/airflow/mocks"
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
 	astrocore_mocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
 	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
 	astroplatformcore_mocks "github.com/astronomer/astro-cli/astro-client-platform-core/mocks"
@@ -74,6 +75,14 @@ var (
 			DagsUploadUrl:     &dagsUploadTestURL,
 		},
 	}
+	listDeploysResponse = astrocore.ListDeploysResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: 200,
+		},
+		JSON200: &astrocore.DeploysPaginated{
+			Deploys: []astrocore.Deploy{},
+		},
+	}
 	finalizeDeployResponse = astroplatformcore.FinalizeDeployResponse{
 		HTTPResponse: &http.Response{
 			StatusCode: 200,
@@ -149,6 +158,7 @@ var (
 
 func TestDeployWithoutDagsDeploySuccess(t *testing.T) {
 	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
 	deployInput := InputDeploy{
 		Path:           "./testfiles/",
 		RuntimeID:      "",
@@ -280,6 +290,7 @@ func TestDeployOnCiCdEnforcedDeployment(t *testing.T) {
 	assert.Contains(t, err.Error(), "cannot deploy since ci/cd enforcement is enabled for the deployment test-deployment. Please use API Tokens instead")
 
 	defer os.RemoveAll("./testfiles/dags/")
+	defer os.RemoveAll("./testfiles/.astro/")
 
 	mockPlatformCoreClient.AssertExpectations(t)
 }
@@ -289,6 +300,7 @@ func TestDeployWithDagsDeploySuccess(t *testing.T) {
 	path := "./testfiles/dags/test.py"
 	fileutil.WriteStringToFile(path, "testing")
 	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
 	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
 
 	deployInput := InputDeploy{
@@ -312,7 +324,7 @@ func TestDeployWithDagsDeploySuccess(t *testing.T) {
 	mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployResponse, nil).Times(7)
 	mockPlatformCoreClient.On("FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&finalizeDeployResponse, nil).Times(7)
 
-	azureUploader = func(sasLink string, file io.Reader) (string, error) {
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
 		return "version-id", nil
 	}
 
@@ -411,6 +423,7 @@ func TestDeployWithDagsDeploySuccess(t *testing.T) {
 
 	defer os.RemoveAll("./testfiles1/")
 	defer os.RemoveAll("./testfiles/dags/")
+	defer os.RemoveAll("./testfiles/.astro/")
 
 	mockCoreClient.AssertExpectations(t)
 	mockImageHandler.AssertExpectations(t)
@@ -420,6 +433,7 @@ func TestDeployWithDagsDeploySuccess(t *testing.T) {
 
 func TestDagsDeploySuccess(t *testing.T) {
 	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
 	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
 
 	deployInput := InputDeploy{
@@ -443,7 +457,7 @@ func TestDagsDeploySuccess(t *testing.T) {
 	mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployResponse, nil).Times(6)
 	mockPlatformCoreClient.On("FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&finalizeDeployResponse, nil).Times(6)
 
-	azureUploader = func(sasLink string, file io.Reader) (string, error) {
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
 		return "version-id", nil
 	}
 
@@ -498,6 +512,7 @@ func TestDagsDeploySuccess(t *testing.T) {
 	assert.ErrorIs(t, err, deployment.ErrTimedOut)
 
 	defer os.RemoveAll("./testfiles/dags/")
+	defer os.RemoveAll("./testfiles/.astro/")
 
 	mockCoreClient.AssertExpectations(t)
 	mockPlatformCoreClient.AssertExpectations(t)
@@ -505,6 +520,7 @@ func TestDagsDeploySuccess(t *testing.T) {
 
 func TestImageOnlyDeploySuccess(t *testing.T) {
 	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
 	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
 
 	deployInput := InputDeploy{
@@ -551,6 +567,7 @@ func TestImageOnlyDeploySuccess(t *testing.T) {
 	assert.NoError(t, err)
 
 	defer os.RemoveAll("./testfiles/dags/")
+	defer os.RemoveAll("./testfiles/.astro/")
 
 	mockCoreClient.AssertExpectations(t)
 	mockPlatformCoreClient.AssertExpectations(t)
@@ -560,6 +577,7 @@ func TestNoDagsDeploy(t *testing.T) {
 	testUtil.InitTestConfig(testUtil.LocalPlatform)
 	config.CFG.ShowWarnings.SetHomeString("true")
 	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
 	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
 
 	ctx, err := config.GetCurrentContext()
@@ -596,6 +614,7 @@ func TestDagsDeployFailed(t *testing.T) {
 	testUtil.InitTestConfig(testUtil.LocalPlatform)
 	config.CFG.ShowWarnings.SetHomeString("false")
 	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
 	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
 
 	deployInput := InputDeploy{
@@ -653,12 +672,14 @@ func TestDeployFailure(t *testing.T) {
 	fileutil.WriteStringToFile(path, "testing")
 
 	defer os.RemoveAll("./testfiles/dags/")
+	defer os.RemoveAll("./testfiles/.astro/")
 
 	// no context set failure
 	testUtil.InitTestConfig(testUtil.LocalPlatform)
 	err := config.ResetCurrentContext()
 	assert.NoError(t, err)
 	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
 	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
 
 	deployInput := InputDeploy{
@@ -753,6 +774,7 @@ func TestDeployMonitoringDAGNonHosted(t *testing.T) {
 	testUtil.InitTestConfig(testUtil.LocalPlatform)
 	config.CFG.ShowWarnings.SetHomeString("false")
 	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
 	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
 
 	ctx, err := config.GetCurrentContext()
@@ -767,7 +789,7 @@ func TestDeployMonitoringDAGNonHosted(t *testing.T) {
 	mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployResponse, nil).Times(4)
 	mockPlatformCoreClient.On("FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&finalizeDeployResponse, nil).Times(4)
 
-	azureUploader = func(sasLink string, file io.Reader) (string, error) {
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
 		_, err = os.Stat("./testfiles/dags/astronomer_monitoring_dag.py")
 		assert.NoError(t, err)
 		return "version-id", nil
@@ -810,6 +832,7 @@ func TestDeployMonitoringDAGNonHosted(t *testing.T) {
 	assert.NoError(t, err)
 
 	defer os.RemoveAll("./testfiles/dags/")
+	defer os.RemoveAll("./testfiles/.astro/")
 
 	mockCoreClient.AssertExpectations(t)
 	mockPlatformCoreClient.AssertExpectations(t)
@@ -836,6 +859,7 @@ func TestDeployNoMonitoringDAGHosted(t *testing.T) {
 	testUtil.InitTestConfig(testUtil.LocalPlatform)
 	config.CFG.ShowWarnings.SetHomeString("false")
 	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
 	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
 
 	ctx, err := config.GetCurrentContext()
@@ -850,7 +874,7 @@ func TestDeployNoMonitoringDAGHosted(t *testing.T) {
 	mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployResponse, nil).Times(4)
 	mockPlatformCoreClient.On("FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&finalizeDeployResponse, nil).Times(4)
 
-	azureUploader = func(sasLink string, file io.Reader) (string, error) {
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
 		_, err = os.Stat("./testfiles/dags/astronomer_monitoring_dag.py")
 		assert.ErrorIs(t, err, os.ErrNotExist)
 		return "version-id", nil
@@ -893,6 +917,7 @@ func TestDeployNoMonitoringDAGHosted(t *testing.T) {
 	assert.NoError(t, err)
 
 	defer os.RemoveAll("./testfiles/dags/")
+	defer os.RemoveAll("./testfiles/.astro/")
 
 	mockCoreClient.AssertExpectations(t)
 	mockPlatformCoreClient.AssertExpectations(t)
@@ -903,6 +928,7 @@ func TestBuildImageFailure(t *testing.T) {
 
 	mockImageHandler := new(mocks.ImageHandler)
 	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
 	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
 
 	// image build failure
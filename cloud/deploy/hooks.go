@@ -0,0 +1,56 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	preDeployHook  = "pre-deploy"
+	postDeployHook = "post-deploy"
+)
+
+// deployHookEnv describes a deploy to the deploy hooks, through environment variables. The pre-deploy hooks run once the
+// deploy is created but before the DAGs are uploaded, so the DAG tarball version is empty for them.
+type deployHookEnv struct {
+	deploymentID      string
+	deploymentName    string
+	deployID          string
+	imageTag          string
+	dagTarballVersion string
+}
+
+func (e deployHookEnv) environ() []string {
+	return append(os.Environ(),
+		"ASTRO_DEPLOYMENT_ID="+e.deploymentID,
+		"ASTRO_DEPLOYMENT_NAME="+e.deploymentName,
+		"ASTRO_DEPLOY_ID="+e.deployID,
+		"ASTRO_IMAGE_TAG="+e.imageTag,
+		"ASTRO_DAG_TARBALL_VERSION="+e.dagTarballVersion,
+	)
+}
+
+// runDeployHooks runs the commands of the deploy.pre_hooks or deploy.post_hooks project config in order from the
+// project directory, stopping at the first command that fails
+func runDeployHooks(hookType string, hooks []string, projectPath string, env deployHookEnv) error {
+	for _, hook := range hooks {
+		fmt.Printf("Running %s hook: %s\n", hookType, hook)
+		shell, shellFlag := "sh", "-c"
+		if runtime.GOOS == "windows" {
+			shell, shellFlag = "cmd", "/C"
+		}
+		cmd := exec.Command(shell, shellFlag, hook) //nolint:gosec
+		cmd.Dir = projectPath
+		cmd.Env = env.environ()
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return errors.Wrapf(err, "%s hook %q failed", hookType, hook)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package deploy
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	astrocore_mocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	astroplatformcore_mocks "github.com/astronomer/astro-cli/astro-client-platform-core/mocks"
+	"github.com/astronomer/astro-cli/config"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// initHooksTestConfig sets up a project config declaring deploy hooks
+func initHooksTestConfig(t *testing.T, projectConfig string) {
+	t.Helper()
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, config.HomeConfigFile, testUtil.NewTestConfig(testUtil.LocalPlatform), 0o600))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(config.WorkingPath, config.ConfigDir, config.ConfigFileNameWithExt), []byte(projectConfig), 0o600))
+	config.InitConfig(fs)
+	config.CFG.ShowWarnings.SetHomeString("false")
+	t.Cleanup(func() { testUtil.InitTestConfig(testUtil.LocalPlatform) })
+}
+
+func TestRunDeployHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks of the test are shell commands")
+	}
+	env := deployHookEnv{
+		deploymentID:      "test-deployment-id",
+		deploymentName:    "test-deployment",
+		deployID:          "test-deploy-id",
+		imageTag:          "deploy-2024",
+		dagTarballVersion: "test-version",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		projectPath := t.TempDir()
+		hooks := []string{
+			"echo $ASTRO_DEPLOYMENT_ID $ASTRO_DEPLOYMENT_NAME > hook.out",
+			"echo $ASTRO_DEPLOY_ID $ASTRO_IMAGE_TAG $ASTRO_DAG_TARBALL_VERSION >> hook.out",
+		}
+		err := runDeployHooks(postDeployHook, hooks, projectPath, env)
+		assert.NoError(t, err)
+
+		out, err := os.ReadFile(filepath.Join(projectPath, "hook.out"))
+		assert.NoError(t, err)
+		assert.Equal(t, "test-deployment-id test-deployment\ntest-deploy-id deploy-2024 test-version\n", string(out))
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		projectPath := t.TempDir()
+		err := runDeployHooks(preDeployHook, []string{"exit 3", "touch hook.out"}, projectPath, env)
+		assert.ErrorContains(t, err, `pre-deploy hook "exit 3" failed`)
+		assert.NoFileExists(t, filepath.Join(projectPath, "hook.out"))
+	})
+}
+
+func TestDeployHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks of the test are shell commands")
+	}
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
+		return "version-id", nil
+	}
+
+	deploy := *createDeployResponse.JSON200
+	deploy.ImageTag = "deploy-2024"
+	createDeployWithTag := astroplatformcore.CreateDeployResponse{HTTPResponse: createDeployResponse.HTTPResponse, JSON200: &deploy}
+
+	t.Run("runs the hooks around a DAG deploy", func(t *testing.T) {
+		initHooksTestConfig(t, "deploy:\n  pre_hooks:\n    - echo pre $ASTRO_DEPLOYMENT_ID $ASTRO_DEPLOY_ID $ASTRO_IMAGE_TAG ${ASTRO_DAG_TARBALL_VERSION:-none} > hooks.out\n  post_hooks:\n    - echo post $ASTRO_DEPLOY_ID $ASTRO_IMAGE_TAG $ASTRO_DAG_TARBALL_VERSION >> hooks.out\n")
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponseDags, nil).Once()
+		mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployWithTag, nil).Once()
+		mockPlatformCoreClient.On("FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&finalizeDeployResponse, nil).Once()
+
+		projectPath := writePlanProject(t, "12.0.0")
+		err := Deploy(InputDeploy{Path: projectPath, RuntimeID: deploymentID, WsID: ws, Dags: true}, mockPlatformCoreClient, mockCoreClient)
+		assert.NoError(t, err)
+
+		out, err := os.ReadFile(filepath.Join(projectPath, "hooks.out"))
+		assert.NoError(t, err)
+		// the DAG tarball version is only known once the DAGs are uploaded
+		assert.Equal(t, "pre "+deploymentID+" test-id deploy-2024 none\npost test-id deploy-2024 version-id\n", string(out))
+		mockPlatformCoreClient.AssertExpectations(t)
+	})
+
+	t.Run("a failing pre-deploy hook aborts the deploy", func(t *testing.T) {
+		initHooksTestConfig(t, "deploy:\n  pre_hooks: exit 1\n  post_hooks: touch hooks.out\n")
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponseDags, nil).Once()
+		mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployWithTag, nil).Once()
+		uploaded := false
+		azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
+			uploaded = true
+			return "version-id", nil
+		}
+
+		projectPath := writePlanProject(t, "12.0.0")
+		err := Deploy(InputDeploy{Path: projectPath, RuntimeID: deploymentID, WsID: ws, Dags: true}, mockPlatformCoreClient, mockCoreClient)
+		assert.ErrorContains(t, err, `pre-deploy hook "exit 1" failed`)
+
+		assert.False(t, uploaded)
+		assert.NoFileExists(t, filepath.Join(projectPath, "hooks.out"))
+		mockPlatformCoreClient.AssertExpectations(t)
+		mockPlatformCoreClient.AssertNotCalled(t, "FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
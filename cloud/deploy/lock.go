@@ -0,0 +1,116 @@
+package deploy
+
+import (
+	httpContext "context"
+	"fmt"
+	"strings"
+	"time"
+
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	"github.com/astronomer/astro-cli/cloud/deployment"
+	"github.com/astronomer/astro-cli/pkg/ansi"
+	"github.com/pkg/errors"
+)
+
+const (
+	// deployLockStaleAge is the age after which an unfinished deploy is considered abandoned, like by a failed or
+	// canceled CI job, and no longer blocks the deploys to its Deployment. unfinalizedDeployMsg tells it to users.
+	deployLockStaleAge = time.Hour
+
+	// deployLockListLimit is the number of the most recent deploys of a Deployment checked for a deploy in progress
+	deployLockListLimit = 20
+
+	deployLockedMsg      = "%w: the deploy %s%s started %s by %s. Use --wait-for-lock to wait for it to finish, or --skip-lock to deploy anyway if it failed"
+	unfinalizedDeployMsg = "\nThe deploy %s was not finalized. It blocks the other deploys to the Deployment for up to an hour, use --skip-lock to deploy anyway\n"
+)
+
+var (
+	errDeployLocked       = errors.New("another deploy to the Deployment is in progress")
+	errDeployLockTimedOut = errors.New("timed out waiting for the other deploy to the Deployment to finish")
+)
+
+var (
+	deployLockSleepTime = 15 * time.Second
+	deployLockTimeout   = time.Hour
+)
+
+// inProgressDeploy returns the most recent deploy of a Deployment that was created but not finalized yet, if any. A
+// deploy is only considered in progress while it is recent and no deploy created after it was finalized, since a
+// deploy that failed before it was finalized stays initialized.
+func inProgressDeploy(organizationID, deploymentID string, coreClient astrocore.CoreClient) (*astrocore.Deploy, error) {
+	limit := deployLockListLimit
+	resp, err := coreClient.ListDeploysWithResponse(httpContext.Background(), organizationID, deploymentID, &astrocore.ListDeploysParams{Limit: &limit})
+	if err != nil {
+		return nil, err
+	}
+	err = astrocore.NormalizeAPIError(resp.HTTPResponse, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var inProgress *astrocore.Deploy
+	for i := range resp.JSON200.Deploys {
+		d := &resp.JSON200.Deploys[i]
+		if d.Status == astrocore.INITIALIZED && time.Since(d.CreatedAt) < deployLockStaleAge && (inProgress == nil || d.CreatedAt.After(inProgress.CreatedAt)) {
+			inProgress = d
+		}
+	}
+	if inProgress == nil {
+		return nil, nil
+	}
+	// a deploy finalized after it was created supersedes it
+	for i := range resp.JSON200.Deploys {
+		d := &resp.JSON200.Deploys[i]
+		if d.Status != astrocore.INITIALIZED && d.CreatedAt.After(inProgress.CreatedAt) {
+			return nil, nil
+		}
+	}
+	return inProgress, nil
+}
+
+// checkDeployLock makes sure no other deploy to the Deployment is in progress before starting one, since two deploys
+// finalized out of order can leave the Deployment with the image of one and the DAGs of the other. With waitForLock,
+// it waits for the other deploy to finish instead of failing.
+func checkDeployLock(deployInfo deploymentInfo, waitForLock bool, coreClient astrocore.CoreClient) error {
+	other, err := inProgressDeploy(deployInfo.organizationID, deployInfo.deploymentID, coreClient)
+	if err != nil || other == nil {
+		return err
+	}
+	if !waitForLock {
+		return fmt.Errorf(deployLockedMsg, errDeployLocked, ansi.Bold(other.Id), deployLockDescription(other), strings.ToLower(deployment.TimeAgo(other.CreatedAt)), deployLockAuthor(other)) //nolint
+	}
+
+	fmt.Printf("Waiting for the deploy %s%s started %s by %s to finish...\n", ansi.Bold(other.Id), deployLockDescription(other), strings.ToLower(deployment.TimeAgo(other.CreatedAt)), deployLockAuthor(other))
+	timeout := time.After(deployLockTimeout)
+	ticker := time.NewTicker(deployLockSleepTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-timeout:
+			return errDeployLockTimedOut
+		case <-ticker.C:
+			other, err = inProgressDeploy(deployInfo.organizationID, deployInfo.deploymentID, coreClient)
+			if err != nil || other == nil {
+				return err
+			}
+		}
+	}
+}
+
+// warnUnfinalizedDeploy tells that a deploy which failed before it was finalized holds the lock of its Deployment
+func warnUnfinalizedDeploy(deployID string) {
+	fmt.Printf(unfinalizedDeployMsg, ansi.Bold(deployID))
+}
+
+func deployLockDescription(d *astrocore.Deploy) string {
+	if d.Description == nil || *d.Description == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", *d.Description)
+}
+
+func deployLockAuthor(d *astrocore.Deploy) string {
+	if author := deployment.DeployCreatedBy(d); author != "" {
+		return author
+	}
+	return "an unknown user"
+}
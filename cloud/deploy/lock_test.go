@@ -0,0 +1,184 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	astrocore_mocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	astroplatformcore_mocks "github.com/astronomer/astro-cli/astro-client-platform-core/mocks"
+	"github.com/astronomer/astro-cli/config"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckDeployLock(t *testing.T) {
+	deployInfo := deploymentInfo{deploymentID: deploymentID, organizationID: "test-org-id"}
+	description := "release 1.2"
+	author := "Jane Doe"
+	inProgress := astrocore.Deploy{
+		Id:               "other-deploy-id",
+		Status:           astrocore.INITIALIZED,
+		Description:      &description,
+		CreatedAt:        time.Now().Add(-time.Minute),
+		CreatedBySubject: &astrocore.BasicSubjectProfile{FullName: &author},
+	}
+	listDeploys := func(deploys ...astrocore.Deploy) *astrocore.ListDeploysResponse {
+		return &astrocore.ListDeploysResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			JSON200:      &astrocore.DeploysPaginated{Deploys: deploys},
+		}
+	}
+
+	t.Run("no deploy in progress", func(t *testing.T) {
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, "test-org-id", deploymentID, mock.Anything).Return(listDeploys(astrocore.Deploy{Id: "done", Status: astrocore.DEPLOYED}), nil).Once()
+
+		err := checkDeployLock(deployInfo, false, mockCoreClient)
+		assert.NoError(t, err)
+		mockCoreClient.AssertExpectations(t)
+	})
+
+	t.Run("fails naming the deploy in progress", func(t *testing.T) {
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, "test-org-id", deploymentID, mock.Anything).Return(listDeploys(inProgress), nil).Once()
+
+		err := checkDeployLock(deployInfo, false, mockCoreClient)
+		assert.ErrorIs(t, err, errDeployLocked)
+		assert.ErrorContains(t, err, "other-deploy-id")
+		assert.ErrorContains(t, err, "(release 1.2)")
+		assert.ErrorContains(t, err, "by Jane Doe")
+		mockCoreClient.AssertExpectations(t)
+	})
+
+	t.Run("ignores an abandoned deploy", func(t *testing.T) {
+		abandoned := inProgress
+		abandoned.CreatedAt = time.Now().Add(-2 * deployLockStaleAge)
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(listDeploys(abandoned), nil).Once()
+
+		err := checkDeployLock(deployInfo, false, mockCoreClient)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ignores a deploy superseded by a finalized deploy", func(t *testing.T) {
+		finalized := astrocore.Deploy{Id: "done", Status: astrocore.DEPLOYED, CreatedAt: inProgress.CreatedAt.Add(time.Second)}
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(listDeploys(finalized, inProgress), nil).Once()
+
+		err := checkDeployLock(deployInfo, false, mockCoreClient)
+		assert.NoError(t, err)
+	})
+
+	t.Run("waits for the deploy in progress", func(t *testing.T) {
+		deployLockSleepTime = time.Millisecond
+		defer func() { deployLockSleepTime = 15 * time.Second }()
+		finished := inProgress
+		finished.Status = astrocore.DEPLOYED
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(listDeploys(inProgress), nil).Twice()
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(listDeploys(finished), nil).Once()
+
+		err := checkDeployLock(deployInfo, true, mockCoreClient)
+		assert.NoError(t, err)
+		mockCoreClient.AssertExpectations(t)
+	})
+
+	t.Run("times out waiting for the deploy in progress", func(t *testing.T) {
+		deployLockSleepTime = time.Millisecond
+		deployLockTimeout = 20 * time.Millisecond
+		defer func() {
+			deployLockSleepTime = 15 * time.Second
+			deployLockTimeout = time.Hour
+		}()
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(listDeploys(inProgress), nil)
+
+		err := checkDeployLock(deployInfo, true, mockCoreClient)
+		assert.ErrorIs(t, err, errDeployLockTimedOut)
+	})
+
+	t.Run("Deploy fails before creating a deploy", func(t *testing.T) {
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(listDeploys(inProgress), nil).Once()
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponseDags, nil).Once()
+
+		testUtil.InitTestConfig(testUtil.LocalPlatform)
+		// --force does not skip the lock
+		err := Deploy(InputDeploy{Path: writePlanProject(t, "12.0.0"), RuntimeID: deploymentID, WsID: ws, Dags: true, Force: true}, mockPlatformCoreClient, mockCoreClient)
+		assert.ErrorIs(t, err, errDeployLocked)
+		mockPlatformCoreClient.AssertExpectations(t)
+		mockPlatformCoreClient.AssertNotCalled(t, "CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Deploy ignores the lock with skip-lock", func(t *testing.T) {
+		azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
+			return "version-id", nil
+		}
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponseDags, nil).Once()
+		mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployResponse, nil).Once()
+		mockPlatformCoreClient.On("FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&finalizeDeployResponse, nil).Once()
+
+		testUtil.InitTestConfig(testUtil.LocalPlatform)
+		err := Deploy(InputDeploy{Path: writePlanProject(t, "12.0.0"), RuntimeID: deploymentID, WsID: ws, Dags: true, SkipLock: true}, mockPlatformCoreClient, mockCoreClient)
+		assert.NoError(t, err)
+		mockPlatformCoreClient.AssertExpectations(t)
+		mockCoreClient.AssertNotCalled(t, "ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFailedDeployLocksUntilSuperseded(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+	config.CFG.ShowWarnings.SetHomeString("false")
+	projectPath := writePlanProject(t, "12.0.0")
+
+	// the deploys of the Deployment as listed by Astro, a created deploy stays initialized until it is finalized
+	var deploys []astrocore.Deploy
+	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(func(context.Context, string, string, *astrocore.ListDeploysParams, ...astrocore.RequestEditorFn) (*astrocore.ListDeploysResponse, error) {
+		return &astrocore.ListDeploysResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			JSON200:      &astrocore.DeploysPaginated{Deploys: deploys},
+		}, nil
+	})
+	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+	mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponseDags, nil).Times(4)
+	mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployResponse, nil).Run(func(args mock.Arguments) {
+		request := args.Get(3).(astroplatformcore.CreateDeployRequest)
+		deploys = append(deploys, astrocore.Deploy{Id: fmt.Sprintf("deploy-%d", len(deploys)), Status: astrocore.INITIALIZED, Description: request.Description, CreatedAt: time.Now().Add(time.Duration(len(deploys)) * time.Second)})
+	}).Times(3)
+	mockPlatformCoreClient.On("FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&finalizeDeployResponse, nil).Run(func(args mock.Arguments) {
+		deploys[len(deploys)-1].Status = astrocore.DEPLOYED
+	}).Twice()
+
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
+		return "", errMock
+	}
+	err := Deploy(InputDeploy{Path: projectPath, RuntimeID: deploymentID, WsID: ws, Dags: true, Description: "first"}, mockPlatformCoreClient, mockCoreClient)
+	assert.ErrorIs(t, err, errMock)
+
+	// the failed deploy stays initialized, so it holds the lock until --skip-lock is used
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
+		return "version-id", nil
+	}
+	err = Deploy(InputDeploy{Path: projectPath, RuntimeID: deploymentID, WsID: ws, Dags: true, Description: "second"}, mockPlatformCoreClient, mockCoreClient)
+	assert.ErrorIs(t, err, errDeployLocked)
+	assert.ErrorContains(t, err, "deploy-0")
+	err = Deploy(InputDeploy{Path: projectPath, RuntimeID: deploymentID, WsID: ws, Dags: true, Description: "second", SkipLock: true}, mockPlatformCoreClient, mockCoreClient)
+	assert.NoError(t, err)
+
+	// the finalized deploy supersedes the failed one, so the next deploys are not locked
+	err = Deploy(InputDeploy{Path: projectPath, RuntimeID: deploymentID, WsID: ws, Dags: true, Description: "third"}, mockPlatformCoreClient, mockCoreClient)
+	assert.NoError(t, err)
+	assert.Equal(t, astrocore.INITIALIZED, deploys[0].Status)
+	mockPlatformCoreClient.AssertExpectations(t)
+}
@@ -0,0 +1,202 @@
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/astronomer/astro-cli/pkg/fileutil"
+	"github.com/astronomer/astro-cli/pkg/printutil"
+)
+
+// dagManifestFile holds the manifests of the last DAG deploys of the project, relative to the project
+const dagManifestFile = ".astro/dag_manifest.json"
+
+const (
+	dagChangeAdded    = "ADDED"
+	dagChangeModified = "MODIFIED"
+	dagChangeDeleted  = "DELETED"
+)
+
+// dagManifest is the content of the DAGs folder at a DAG deploy, the SHA-256 of each file keyed by its path
+type dagManifest struct {
+	DagTarballVersion string            `json:"dag_tarball_version"`
+	Files             map[string]string `json:"files"`
+}
+
+type dagChange struct {
+	file   string
+	change string
+}
+
+// computeDagManifest returns the SHA-256 of each file of the DAGs folder keyed by its path, skipping the .git folders
+// and the files matched by ignore like the DAG tarball
+func computeDagManifest(dagsPath string, ignore *fileutil.IgnoreMatcher) (map[string]string, error) {
+	files := map[string]string{}
+	if _, err := os.Stat(dagsPath); os.IsNotExist(err) {
+		return files, nil
+	}
+	err := filepath.WalkDir(dagsPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dagsPath, path)
+		if err != nil {
+			return err
+		}
+		ignored, err := ignore.Matches(filepath.ToSlash(filepath.Join(filepath.Base(dagsPath), rel)))
+		if err != nil {
+			return err
+		}
+		if ignored {
+			return nil
+		}
+		hash, err := hashFile(path, d)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = hash
+		return nil
+	})
+	return files, err
+}
+
+// hashFile returns the SHA-256 of a file, or of its target for a symlink
+func hashFile(path string, d fs.DirEntry) (string, error) {
+	h := sha256.New()
+	if d.Type()&fs.ModeSymlink != 0 {
+		link, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte(link))
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// diffDagManifests returns the files added, modified and deleted between two manifests, sorted by path
+func diffDagManifests(previous, current map[string]string) []dagChange {
+	changes := []dagChange{}
+	for file, hash := range current {
+		previousHash, ok := previous[file]
+		switch {
+		case !ok:
+			changes = append(changes, dagChange{file, dagChangeAdded})
+		case previousHash != hash:
+			changes = append(changes, dagChange{file, dagChangeModified})
+		}
+	}
+	for file := range previous {
+		if _, ok := current[file]; !ok {
+			changes = append(changes, dagChange{file, dagChangeDeleted})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].file < changes[j].file
+	})
+	return changes
+}
+
+func readDagManifests(projectPath string) (map[string]dagManifest, error) {
+	manifests := map[string]dagManifest{}
+	data, err := os.ReadFile(filepath.Join(projectPath, dagManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifests, nil
+		}
+		return nil, err
+	}
+	err = json.Unmarshal(data, &manifests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", dagManifestFile, err)
+	}
+	return manifests, nil
+}
+
+// saveDagManifest records the manifest of the last DAG deploy to a Deployment
+func saveDagManifest(projectPath, deploymentID string, manifest dagManifest) error {
+	manifests, err := readDagManifests(projectPath)
+	if err != nil {
+		return err
+	}
+	manifests[deploymentID] = manifest
+	data, err := json.MarshalIndent(manifests, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(projectPath, dagManifestFile)
+	err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644) //nolint:gosec,mnd
+}
+
+// recordDagDeploy saves the manifest of the DAGs of a deploy, only warning on failures since the deploy succeeded
+func recordDagDeploy(projectPath, deploymentID, dagTarballVersion string, files map[string]string) {
+	if dagTarballVersion == "" || files == nil {
+		return
+	}
+	err := saveDagManifest(projectPath, deploymentID, dagManifest{DagTarballVersion: dagTarballVersion, Files: files})
+	if err != nil {
+		fmt.Printf("Failed to save the DAG manifest %s: %s\n", dagManifestFile, err.Error())
+	}
+}
+
+// checkDagChanges compares the DAGs folder with the manifest of the last DAG deploy to the Deployment from the project
+// and prints the changed files. It returns the manifest of the DAGs folder. The last manifest is only trusted when the
+// Deployment still runs the DAGs it describes. The manifest only describes the changes, whether the DAGs are uploaded
+// is decided from the SHA-256 of the deployed tarball, since the manifest is missing from fresh checkouts like in CI.
+func checkDagChanges(projectPath, dagsPath string, deployInfo deploymentInfo, out io.Writer) (map[string]string, error) {
+	ignore, err := fileutil.NewIgnoreMatcher(filepath.Join(projectPath, fileutil.AstroIgnoreFileName))
+	if err != nil {
+		return nil, err
+	}
+	files, err := computeDagManifest(dagsPath, ignore)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := readDagManifests(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	previous, ok := manifests[deployInfo.deploymentID]
+	if !ok || previous.DagTarballVersion == "" || previous.DagTarballVersion != deployInfo.desiredDagTarballVersion {
+		return files, nil
+	}
+
+	changes := diffDagManifests(previous.Files, files)
+	if len(changes) == 0 {
+		return files, nil
+	}
+	fmt.Fprintf(out, "DAG changes since the last deploy of the DAG bundle %s:\n\n", previous.DagTarballVersion)
+	tab := &printutil.Table{
+		DynamicPadding: true,
+		Header:         []string{"FILE", "CHANGE"},
+	}
+	for _, change := range changes {
+		tab.AddRow([]string{change.file, change.change}, false)
+	}
+	return files, tab.Print(out)
+}
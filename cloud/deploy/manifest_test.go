@@ -0,0 +1,119 @@
+package deploy
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	astrocore_mocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	astroplatformcore_mocks "github.com/astronomer/astro-cli/astro-client-platform-core/mocks"
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/pkg/azure"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDiffDagManifests(t *testing.T) {
+	previous := map[string]string{"a.py": "1", "b.py": "2", "c.py": "3"}
+	current := map[string]string{"a.py": "1", "b.py": "4", "d.py": "5"}
+
+	changes := diffDagManifests(previous, current)
+	assert.Equal(t, []dagChange{
+		{"b.py", dagChangeModified},
+		{"c.py", dagChangeDeleted},
+		{"d.py", dagChangeAdded},
+	}, changes)
+	assert.Empty(t, diffDagManifests(previous, previous))
+}
+
+func TestCheckDagChanges(t *testing.T) {
+	projectPath := t.TempDir()
+	dagsPath := filepath.Join(projectPath, "dags")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dagsPath, ".git"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dagsPath, "my_dag.py"), []byte("print('hello')\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dagsPath, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0o600))
+	deployInfo := deploymentInfo{deploymentID: deploymentID, desiredDagTarballVersion: "version-1"}
+
+	files, err := checkDagChanges(projectPath, dagsPath, deployInfo, new(bytes.Buffer))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	recordDagDeploy(projectPath, deploymentID, "version-1", files)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dagsPath, "my_dag.py"), []byte("print('bye')\n"), 0o600))
+	out := new(bytes.Buffer)
+	_, err = checkDagChanges(projectPath, dagsPath, deployInfo, out)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "my_dag.py")
+	assert.Contains(t, out.String(), dagChangeModified)
+
+	// the Deployment runs DAGs deployed from somewhere else
+	otherDeployInfo := deployInfo
+	otherDeployInfo.desiredDagTarballVersion = "version-2"
+	out = new(bytes.Buffer)
+	_, err = checkDagChanges(projectPath, dagsPath, otherDeployInfo, out)
+	assert.NoError(t, err)
+	assert.Empty(t, out.String())
+}
+
+func TestDagsDeploySkippedWithoutChanges(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+	config.CFG.ShowWarnings.SetHomeString("false")
+	defer func() { azureBlobChecksum = azure.BlobChecksum }()
+	projectPath := writePlanProject(t, "12.0.0")
+
+	// the deployed tarball, as uploaded by a previous deploy from another checkout
+	var deployedChecksum string
+	azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
+		deployedChecksum = metadata[tarballChecksumMetadataKey]
+		return tarballVersion, nil
+	}
+	_, err := deployDags(projectPath, filepath.Join(projectPath, "dags"), dagsUploadTestURL, "12.0.0", hybridType, "", false)
+	assert.NoError(t, err)
+	assert.NoError(t, os.RemoveAll(filepath.Join(projectPath, dagManifestFile)))
+
+	deploy := func(deployInput InputDeploy) (uploaded bool, finalizedVersion string) {
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponseDags, nil).Once()
+		mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployResponse, nil).Once()
+		mockPlatformCoreClient.On("FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&finalizeDeployResponse, nil).Run(func(args mock.Arguments) {
+			finalizedVersion = *args.Get(4).(astroplatformcore.FinalizeDeployRequest).DagTarballVersion
+		}).Once()
+		azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
+			uploaded = true
+			return "new-version", nil
+		}
+		azureBlobChecksum = func(sasLink string) (string, string, error) {
+			assert.Equal(t, dagsUploadTestURL, sasLink)
+			return tarballVersion, deployedChecksum, nil
+		}
+
+		deployInput.Path = projectPath
+		deployInput.RuntimeID = deploymentID
+		deployInput.WsID = ws
+		deployInput.Dags = true
+		err := Deploy(deployInput, mockPlatformCoreClient, mockCoreClient)
+		assert.NoError(t, err)
+		mockPlatformCoreClient.AssertExpectations(t)
+		return uploaded, finalizedVersion
+	}
+
+	// the Deployment runs the tarball of the same DAGs, so the deploy keeps it
+	uploaded, finalizedVersion := deploy(InputDeploy{})
+	assert.False(t, uploaded)
+	assert.Equal(t, tarballVersion, finalizedVersion)
+
+	uploaded, finalizedVersion = deploy(InputDeploy{ForceUpload: true})
+	assert.True(t, uploaded)
+	assert.Equal(t, "new-version", finalizedVersion)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(projectPath, "dags", "new_dag.py"), []byte("print('new')\n"), 0o600))
+	uploaded, finalizedVersion = deploy(InputDeploy{})
+	assert.True(t, uploaded)
+	assert.Equal(t, "new-version", finalizedVersion)
+}
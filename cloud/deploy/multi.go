@@ -0,0 +1,251 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/astronomer/astro-cli/airflow"
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	"github.com/astronomer/astro-cli/cloud/deployment"
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/pkg/ansi"
+	"github.com/astronomer/astro-cli/pkg/fileutil"
+	"github.com/astronomer/astro-cli/pkg/input"
+	"github.com/astronomer/astro-cli/pkg/printutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	targetStatusDeployed = "DEPLOYED"
+	targetStatusHealthy  = "HEALTHY"
+	targetStatusFailed   = "FAILED"
+	targetStatusSkipped  = "SKIPPED"
+)
+
+var (
+	errMultiDeployDags      = errors.New("cannot deploy only DAGs to multiple Deployments, DAG-only deploys target a single Deployment")
+	errMultiDeployDagDeploy = errors.New("DAG-only deploys must be either enabled or disabled for all the Deployments, since a single image is built for them")
+	errMultiDeployFailed    = errors.New("the deploy did not succeed for all the Deployments")
+)
+
+// targetDeploy is the result of deploying to one of the Deployments of a multi-Deployment deploy
+type targetDeploy struct {
+	info     deploymentInfo
+	deployID string
+	imageTag string
+	status   string
+}
+
+// DeployToDeployments builds the image of a project once and deploys it to each of the Deployments in order, like a
+// promotion pipeline. When deployInput.WaitForStatus is set, each deploy waits for its Deployment to become healthy
+// and the following Deployments are skipped when it does not.
+func DeployToDeployments(deployInput InputDeploy, deploymentIDs []string, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient) error { //nolint
+	c, err := config.GetCurrentContext()
+	if err != nil {
+		return err
+	}
+	if deployInput.Dags {
+		return errMultiDeployDags
+	}
+	err = checkProvenanceInput(deployInput)
+	if err != nil {
+		return err
+	}
+
+	dagsPath := deployInput.DagsPath
+	if dagsPath == "" {
+		dagsPath = filepath.Join(deployInput.Path, "dags")
+	}
+	dagFiles := fileutil.GetFilesWithSpecificExtension(dagsPath, ".py")
+
+	targets := make([]targetDeploy, 0, len(deploymentIDs))
+	for _, deploymentID := range deploymentIDs {
+		deployInfo, err := getDeploymentInfo(deploymentID, deployInput.WsID, "", false, platformCoreClient, coreClient)
+		if err != nil {
+			return err
+		}
+		if deployInfo.cicdEnforcement && !canCiCdDeploy(c.Token) {
+			return fmt.Errorf(errCiCdEnforcementUpdate, deployInfo.name) //nolint
+		}
+		if len(targets) > 0 && deployInfo.dagDeployEnabled != targets[0].info.dagDeployEnabled {
+			return errMultiDeployDagDeploy
+		}
+		if deployInput.Image && !deployInfo.dagDeployEnabled {
+			return fmt.Errorf(enableDagDeployMsg, deployInfo.deploymentID) //nolint
+		}
+		targets = append(targets, targetDeploy{info: deployInfo})
+	}
+
+	if deployInput.DryRun {
+		for i := range targets {
+			if i > 0 {
+				fmt.Println()
+			}
+			err = printDeployPlan(deployInput, targets[i].info, dagsPath, platformCoreClient, os.Stdout)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err = checkImageDeployInput(deployInput)
+	if err != nil {
+		return err
+	}
+
+	first := targets[0].info
+	if first.dagDeployEnabled && len(dagFiles) == 0 && config.CFG.ShowWarnings.GetBool() && !deployInput.Image {
+		i, _ := input.Confirm("Warning: No DAGs found. This will delete any existing DAGs of all the Deployments. Are you sure you want to deploy?")
+
+		if !i {
+			fmt.Println("Canceling deploy...")
+			return nil
+		}
+	}
+
+	// Build the image once, buildImage checks its Runtime version against the first Deployment
+	runtimeVersion, err := buildImage(deployInput.Path, first.currentVersion, first.deployImage, deployInput.ImageName, first.organizationID, deployInput.BuildSecretString, first.dagDeployEnabled, deployInput.ForceUpgradeToAF3, platformCoreClient)
+	if err != nil {
+		return err
+	}
+	deploymentOptionsRuntimeVersions, err := getDeploymentOptionsRuntimeVersions(first.organizationID, platformCoreClient)
+	if err != nil {
+		return err
+	}
+	for i := range targets[1:] {
+		info := targets[i+1].info
+		if !ValidRuntimeVersion(info.currentVersion, runtimeVersion, deploymentOptionsRuntimeVersions, deployInput.ForceUpgradeToAF3) {
+			return errors.Errorf("the image cannot be deployed to the Deployment %s", info.deploymentID)
+		}
+	}
+
+	if len(dagFiles) > 0 {
+		err = parseOrPytestDAG(deployInput.Pytest, runtimeVersion, deployInput.EnvFile, first.deployImage, first.namespace, deployInput.BuildSecretString)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println("No DAGs found. Skipping testing...")
+	}
+
+	imageHandler := airflowImageHandler(first.deployImage)
+	failed := false
+	for i := range targets {
+		if failed {
+			targets[i].status = targetStatusSkipped
+			continue
+		}
+		fmt.Printf("\nDeploying to the Deployment %s (%d/%d)\n", ansi.Bold(targets[i].info.deploymentID), i+1, len(targets))
+		err = deployImageToTarget(deployInput, &targets[i], imageHandler, c.Token, dagsPath, len(dagFiles) > 0, platformCoreClient, coreClient)
+		if err != nil {
+			fmt.Printf("Failed to deploy to the Deployment %s: %s\n", targets[i].info.deploymentID, err.Error())
+			targets[i].status = targetStatusFailed
+			failed = true
+		}
+	}
+
+	fmt.Println()
+	err = printTargetDeploys(targets)
+	if err != nil {
+		return err
+	}
+	if failed {
+		return errMultiDeployFailed
+	}
+	return nil
+}
+
+// deployImageToTarget pushes the built image and uploads the DAGs to one of the Deployments of a multi-Deployment deploy
+func deployImageToTarget(deployInput InputDeploy, target *targetDeploy, imageHandler airflow.ImageHandler, token, dagsPath string, hasDags bool, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient) error {
+	info := target.info
+	if !deployInput.SkipLock {
+		err := checkDeployLock(info, deployInput.WaitForLock, coreClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	createDeployRequest := astroplatformcore.CreateDeployRequest{
+		Description: &deployInput.Description,
+		Type:        astroplatformcore.CreateDeployRequestTypeIMAGEANDDAG,
+	}
+	if deployInput.Image {
+		createDeployRequest.Type = astroplatformcore.CreateDeployRequestTypeIMAGEONLY
+	}
+	deploy, err := createDeploy(info.organizationID, info.deploymentID, createDeployRequest, platformCoreClient)
+	if err != nil {
+		return err
+	}
+	finalized := false
+	defer func() {
+		if !finalized {
+			warnUnfinalizedDeploy(deploy.Id)
+		}
+	}()
+	target.deployID = deploy.Id
+	target.imageTag = deploy.ImageTag
+	hookEnv := deployHookEnv{
+		deploymentID:   info.deploymentID,
+		deploymentName: info.name,
+		deployID:       deploy.Id,
+		imageTag:       deploy.ImageTag,
+	}
+	err = runDeployHooks(preDeployHook, config.CFG.DeployPreHooks.GetProjectStringSlice(), deployInput.Path, hookEnv)
+	if err != nil {
+		return err
+	}
+
+	remoteImage := fmt.Sprintf("%s:%s", deploy.ImageRepository, deploy.ImageTag)
+	imageDigest, err := imageHandler.Push(remoteImage, registryUsername, token, deployInput.SBOM || deployInput.Sign)
+	if err != nil {
+		return err
+	}
+	err = attachProvenance(deployInput, imageHandler, deploy.ImageRepository, imageDigest, token)
+	if err != nil {
+		return err
+	}
+
+	var dagTarballVersion string
+	if info.dagDeployEnabled && hasDags && !deployInput.Image {
+		if deploy.DagsUploadUrl == nil {
+			return errors.New("no DAGs upload URL received from Astro")
+		}
+		dagTarballVersion, err = deployDags(deployInput.Path, dagsPath, *deploy.DagsUploadUrl, info.currentVersion, astroplatformcore.DeploymentType(info.deploymentType), info.desiredDagTarballVersion, deployInput.ForceUpload)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = finalizeDeploy(deploy.Id, info.deploymentID, info.organizationID, dagTarballVersion, info.dagDeployEnabled, platformCoreClient)
+	if err != nil {
+		return err
+	}
+	finalized = true
+	target.status = targetStatusDeployed
+
+	if deployInput.WaitForStatus {
+		err = deployment.HealthPoll(info.deploymentID, info.workspaceID, sleepTime, tickNum, timeoutNum, platformCoreClient)
+		if err != nil {
+			return err
+		}
+		target.status = targetStatusHealthy
+	}
+
+	hookEnv.dagTarballVersion = dagTarballVersion
+	return runDeployHooks(postDeployHook, config.CFG.DeployPostHooks.GetProjectStringSlice(), deployInput.Path, hookEnv)
+}
+
+func printTargetDeploys(targets []targetDeploy) error {
+	tab := &printutil.Table{
+		DynamicPadding: true,
+		Header:         []string{"DEPLOYMENT NAME", "DEPLOYMENT ID", "DEPLOY ID", "IMAGE TAG", "STATUS"},
+	}
+	for i := range targets {
+		t := &targets[i]
+		tab.AddRow([]string{t.info.name, t.info.deploymentID, t.deployID, t.imageTag, t.status}, t.status == targetStatusFailed)
+	}
+	return tab.Print(os.Stdout)
+}
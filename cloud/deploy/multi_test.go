@@ -0,0 +1,104 @@
+package deploy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/astronomer/astro-cli/airflow"
+	"github.com/astronomer/astro-cli/airflow/mocks"
+	astrocore_mocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	astroplatformcore_mocks "github.com/astronomer/astro-cli/astro-client-platform-core/mocks"
+	"github.com/astronomer/astro-cli/config"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeployToDeployments(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+	config.CFG.ShowWarnings.SetHomeString("false")
+
+	mockContainerHandler := new(mocks.ContainerHandler)
+	containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+		mockContainerHandler.On("Parse", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		return mockContainerHandler, nil
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Times(2)
+		mockPlatformCoreClient.On("GetDeploymentOptionsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&getDeploymentOptionsResponse, nil).Times(2)
+		mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployResponse, nil).Times(2)
+		mockPlatformCoreClient.On("FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&finalizeDeployResponse, nil).Times(2)
+
+		mockImageHandler := new(mocks.ImageHandler)
+		mockImageHandler.On("Build", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		mockImageHandler.On("GetLabel", mock.Anything, runtimeImageLabel).Return("12.0.0", nil).Once()
+		mockImageHandler.On("Push", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", nil).Times(2)
+		airflowImageHandler = func(image string) airflow.ImageHandler {
+			return mockImageHandler
+		}
+
+		deployInput := InputDeploy{
+			Path:    writePlanProject(t, "12.0.0"),
+			WsID:    ws,
+			EnvFile: ".env",
+			Pytest:  parse,
+		}
+		err := DeployToDeployments(deployInput, []string{"dev-id", "prod-id"}, mockPlatformCoreClient, mockCoreClient)
+		assert.NoError(t, err)
+
+		mockImageHandler.AssertExpectations(t)
+		mockPlatformCoreClient.AssertExpectations(t)
+	})
+
+	t.Run("skips the remaining Deployments after a failure", func(t *testing.T) {
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&listDeploysResponse, nil).Maybe()
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Times(2)
+		mockPlatformCoreClient.On("GetDeploymentOptionsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&getDeploymentOptionsResponse, nil).Times(2)
+		mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployResponse, nil).Once()
+
+		mockImageHandler := new(mocks.ImageHandler)
+		mockImageHandler.On("Build", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		mockImageHandler.On("GetLabel", mock.Anything, runtimeImageLabel).Return("12.0.0", nil).Once()
+		mockImageHandler.On("Push", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", errMock).Once()
+		airflowImageHandler = func(image string) airflow.ImageHandler {
+			return mockImageHandler
+		}
+
+		deployInput := InputDeploy{
+			Path:    writePlanProject(t, "12.0.0"),
+			WsID:    ws,
+			EnvFile: ".env",
+		}
+		err := DeployToDeployments(deployInput, []string{"dev-id", "prod-id"}, mockPlatformCoreClient, mockCoreClient)
+		assert.ErrorIs(t, err, errMultiDeployFailed)
+
+		mockImageHandler.AssertExpectations(t)
+		mockPlatformCoreClient.AssertExpectations(t)
+		mockPlatformCoreClient.AssertNotCalled(t, "FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("DAG-only deploy", func(t *testing.T) {
+		err := DeployToDeployments(InputDeploy{Dags: true}, []string{"dev-id", "prod-id"}, nil, nil)
+		assert.ErrorIs(t, err, errMultiDeployDags)
+	})
+
+	t.Run("mixed DAG deploy settings", func(t *testing.T) {
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, "dev-id").Return(&deploymentResponse, nil).Once()
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, "prod-id").Return(&astroplatformcore.GetDeploymentResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			JSON200:      &astroplatformcore.Deployment{Id: "prod-id", RuntimeVersion: "12.0.0", IsDagDeployEnabled: true},
+		}, nil).Once()
+
+		err := DeployToDeployments(InputDeploy{Path: t.TempDir(), WsID: ws}, []string{"dev-id", "prod-id"}, mockPlatformCoreClient, nil)
+		assert.ErrorIs(t, err, errMultiDeployDagDeploy)
+		mockPlatformCoreClient.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,282 @@
+package deploy
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/astronomer/astro-cli/airflow"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	"github.com/astronomer/astro-cli/docker"
+	"github.com/astronomer/astro-cli/pkg/ansi"
+	"github.com/astronomer/astro-cli/pkg/fileutil"
+	"github.com/astronomer/astro-cli/pkg/printutil"
+	"github.com/docker/go-units"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+	"github.com/pkg/errors"
+)
+
+var (
+	errPlanRuntimeVersionNotFound = errors.New("unable to find the Astro Runtime version of the image")
+	errPlanInvalidRuntimeVersion  = errors.New("the Astro Runtime version of the image cannot be deployed to the Deployment")
+)
+
+// planFile is a file of the DAGs folder a deploy would include
+type planFile struct {
+	name string
+	size int64
+}
+
+// printDeployPlan prints what a deploy would do to a Deployment, without building, pushing or creating anything
+func printDeployPlan(deployInput InputDeploy, deployInfo deploymentInfo, dagsPath string, platformCoreClient astroplatformcore.CoreClient, out io.Writer) error {
+	fmt.Fprintf(out, "Deploy plan for the Deployment %s (dry run, nothing will be deployed)\n\n", ansi.Bold(deployInfo.name))
+	fmt.Fprintf(out, " Deployment ID: %s\n", deployInfo.deploymentID)
+	fmt.Fprintf(out, " Workspace ID: %s\n", deployInfo.workspaceID)
+	fmt.Fprintf(out, " Current Astro Runtime Version: %s\n", deployInfo.currentVersion)
+
+	imageDeploy := !deployInput.Dags
+	switch {
+	case deployInput.Dags:
+		if !deployInfo.dagDeployEnabled {
+			return fmt.Errorf(enableDagDeployMsg, deployInfo.deploymentID) //nolint
+		}
+		fmt.Fprintln(out, " Deploy Type: DAG-only deploy")
+	case deployInput.Image:
+		fmt.Fprintln(out, " Deploy Type: image-only deploy, the DAGs of the Deployment are not affected")
+	case deployInfo.dagDeployEnabled:
+		fmt.Fprintln(out, " Deploy Type: image and DAG deploy, the DAGs are uploaded separately from the image")
+	default:
+		fmt.Fprintln(out, " Deploy Type: image deploy, the DAGs are built into the image")
+	}
+
+	if imageDeploy {
+		version, err := planRuntimeVersion(deployInput, deployInfo)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, " Astro Runtime Version: %s -> %s\n", deployInfo.currentVersion, version)
+		deploymentOptionsRuntimeVersions, err := getDeploymentOptionsRuntimeVersions(deployInfo.organizationID, platformCoreClient)
+		if err != nil {
+			return err
+		}
+		if !ValidRuntimeVersion(deployInfo.currentVersion, version, deploymentOptionsRuntimeVersions, deployInput.ForceUpgradeToAF3) {
+			return errPlanInvalidRuntimeVersion
+		}
+	}
+
+	if deployInput.Image {
+		fmt.Fprintln(out, "\nRun the command again without --dry-run to deploy")
+		return nil
+	}
+
+	// the image is filtered by .dockerignore, the DAG tarball by .astroignore like UploadBundle
+	var files []planFile
+	var tarFiles []fileutil.TarFile
+	if deployInput.Dags || deployInfo.dagDeployEnabled {
+		ignore, err := bundleIgnoreMatcher(deployInput.Path, dagsPath, true)
+		if err != nil {
+			return err
+		}
+		tarFiles, err = fileutil.TarFiles(dagsPath, "", true, bundleExcludedPaths, ignore)
+		if err != nil {
+			return err
+		}
+		for _, file := range tarFiles {
+			files = append(files, planFile{name: file.Name, size: file.Info.Size()})
+		}
+	} else {
+		var err error
+		files, err = planImageDagFiles(deployInput.Path, dagsPath)
+		if err != nil {
+			return err
+		}
+	}
+	tab := &printutil.Table{
+		DynamicPadding: true,
+		Header:         []string{"FILE", "SIZE"},
+		NoResultsMsg:   "No DAG files found",
+	}
+	var totalSize int64
+	for _, file := range files {
+		tab.AddRow([]string{file.name, units.HumanSize(float64(file.size))}, false)
+		totalSize += file.size
+	}
+
+	if !deployInput.Dags && !deployInfo.dagDeployEnabled {
+		fmt.Fprintf(out, "\nFiles built into the image from %s:\n\n", dagsPath)
+		if err := tab.Print(out); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\n%d files, %s\n", len(files), units.HumanSize(float64(totalSize)))
+		fmt.Fprintln(out, "\nRun the command again without --dry-run to deploy")
+		return nil
+	}
+
+	// an image and DAG deploy only uploads the DAGs when there are some, like Deploy
+	if !deployInput.Dags && !hasDagFiles(files) {
+		fmt.Fprintln(out, "\nNo DAGs found, the DAGs of the Deployment are not affected")
+		fmt.Fprintln(out, "\nRun the command again without --dry-run to deploy")
+		return nil
+	}
+
+	uploadSize, err := estimateUploadSize(tarFiles)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nFiles included in the DAG tarball from %s:\n\n", dagsPath)
+	if err := tab.Print(out); err != nil {
+		return err
+	}
+	if shouldIncludeMonitoringDag(astroplatformcore.DeploymentType(deployInfo.deploymentType)) {
+		fmt.Fprintln(out, "\nThe Astronomer monitoring DAG is added to the DAG tarball")
+	}
+	if !hasDagFiles(files) {
+		fmt.Fprintln(out, "\nWarning: No DAGs found. This deploy would delete any existing DAGs")
+	}
+	fmt.Fprintf(out, "\n%d files, %s, estimated upload size %s\n", len(files), units.HumanSize(float64(totalSize)), units.HumanSize(float64(uploadSize)))
+	fmt.Fprintln(out, "\nRun the command again without --dry-run to deploy")
+	return nil
+}
+
+// planRuntimeVersion returns the Runtime version a deploy would push, from the custom image when there is one or from
+// the Dockerfile otherwise, since the image is not built by a dry run
+func planRuntimeVersion(deployInput InputDeploy, deployInfo deploymentInfo) (string, error) {
+	if deployInput.ImageName != "" {
+		version, err := airflowImageHandler(deployInfo.deployImage).GetLabel(deployInput.ImageName, runtimeImageLabel)
+		if err != nil {
+			return "", err
+		}
+		if version == "" {
+			return "", errPlanRuntimeVersionNotFound
+		}
+		return version, nil
+	}
+
+	dockerfilePath := filepath.Join(deployInput.Path, dockerfile)
+	cmds, err := docker.ParseFile(dockerfilePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse dockerfile: %s", dockerfilePath)
+	}
+	_, tag := docker.GetImageTagFromParsedFile(cmds)
+	if tag == "" || tag == "latest" {
+		return "", errPlanRuntimeVersionNotFound
+	}
+	return airflow.RuntimeVersionFromImageTag(tag), nil
+}
+
+// planImageDagFiles returns the files of the DAGs folder built into the image, skipping the ones matched by the
+// .dockerignore file of the project and the .git folders
+func planImageDagFiles(projectPath, dagsPath string) ([]planFile, error) {
+	patterns, err := readDockerIgnorePatterns(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	pm, err := patternmatcher.New(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []planFile{}
+	if _, err := os.Stat(dagsPath); os.IsNotExist(err) {
+		return files, nil
+	}
+	err = filepath.WalkDir(dagsPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			return err
+		}
+		ignored, err := pm.MatchesOrParentMatches(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		if ignored {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dagsPath, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, planFile{
+			name: filepath.ToSlash(filepath.Join(filepath.Base(dagsPath), name)),
+			size: info.Size(),
+		})
+		return nil
+	})
+	return files, err
+}
+
+// readDockerIgnorePatterns returns the patterns of the .dockerignore file of the project, without the DAGs folder
+// entry the image-only builds add to it
+func readDockerIgnorePatterns(projectPath string) ([]string, error) {
+	f, err := os.Open(filepath.Join(projectPath, ".dockerignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	lines, err := ignorefile.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	patterns := []string{}
+	for _, line := range lines {
+		if strings.TrimSuffix(line, "/") == "dags" {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
+
+func hasDagFiles(files []planFile) bool {
+	for _, file := range files {
+		if filepath.Ext(file.name) == ".py" {
+			return true
+		}
+	}
+	return false
+}
+
+// estimateUploadSize returns the size of the gzipped tarball of the files, written like UploadBundle writes it
+func estimateUploadSize(files []fileutil.TarFile) (int64, error) {
+	counter := &countingWriter{}
+	gzipWriter := gzip.NewWriter(counter)
+	if err := fileutil.WriteTar(gzipWriter, files); err != nil {
+		return 0, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return 0, err
+	}
+	return counter.n, nil
+}
+
+// countingWriter counts the bytes written to it and discards them
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,119 @@
+package deploy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	astrocore_mocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
+	astroplatformcore_mocks "github.com/astronomer/astro-cli/astro-client-platform-core/mocks"
+	"github.com/astronomer/astro-cli/config"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func writePlanProject(t *testing.T, runtimeTag string) string {
+	t.Helper()
+	projectPath := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(projectPath, "dags", "ignored"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectPath, "Dockerfile"), []byte("FROM quay.io/astronomer/astro-runtime:"+runtimeTag+"\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectPath, ".dockerignore"), []byte("dags/\ndags/ignored\n**/*.pyc\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectPath, "dags", "my_dag.py"), []byte("print('hello')\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectPath, "dags", "my_dag.pyc"), []byte("compiled"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectPath, "dags", "ignored", "other_dag.py"), []byte("print('ignored')\n"), 0o600))
+	return projectPath
+}
+
+func TestDeployDryRun(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+	config.CFG.ShowWarnings.SetHomeString("false")
+	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+	mockPlatformCoreClient.On("ListDeploymentsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&mockListDeploymentsResponse, nil).Once()
+	mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponseDags, nil).Times(2)
+	mockPlatformCoreClient.On("GetDeploymentOptionsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&getDeploymentOptionsResponse, nil).Once()
+
+	projectPath := writePlanProject(t, "12.0.0")
+	deployInput := InputDeploy{
+		Path:   projectPath,
+		WsID:   ws,
+		Prompt: true,
+		DryRun: true,
+	}
+	defer testUtil.MockUserInput(t, "1")()
+	err := Deploy(deployInput, mockPlatformCoreClient, mockCoreClient)
+	assert.NoError(t, err)
+
+	mockCoreClient.AssertExpectations(t)
+	mockPlatformCoreClient.AssertExpectations(t)
+	mockPlatformCoreClient.AssertNotCalled(t, "CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPrintDeployPlan(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+	deployInfo := deploymentInfo{
+		deploymentID:     deploymentID,
+		currentVersion:   "12.0.0",
+		workspaceID:      ws,
+		deploymentType:   string(hybridType),
+		dagDeployEnabled: true,
+		name:             "test-deployment",
+	}
+
+	t.Run("image and DAG deploy", func(t *testing.T) {
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("GetDeploymentOptionsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&getDeploymentOptionsResponse, nil).Once()
+		projectPath := writePlanProject(t, "12.0.0")
+		assert.NoError(t, os.WriteFile(filepath.Join(projectPath, ".astroignore"), []byte("dags/ignored/\n"), 0o600))
+
+		out := new(bytes.Buffer)
+		err := printDeployPlan(InputDeploy{Path: projectPath}, deployInfo, filepath.Join(projectPath, "dags"), mockPlatformCoreClient, out)
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "image and DAG deploy")
+		assert.Contains(t, out.String(), "Astro Runtime Version: 12.0.0 -> 12.0.0")
+		// the DAG tarball is filtered by .astroignore only, like UploadBundle
+		assert.Contains(t, out.String(), "dags/my_dag.py ")
+		assert.Contains(t, out.String(), "dags/my_dag.pyc")
+		assert.NotContains(t, out.String(), "other_dag.py")
+		assert.Contains(t, out.String(), "2 files")
+		assert.Contains(t, out.String(), "estimated upload size")
+		mockPlatformCoreClient.AssertExpectations(t)
+	})
+
+	t.Run("DAGs built into the image", func(t *testing.T) {
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("GetDeploymentOptionsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&getDeploymentOptionsResponse, nil).Once()
+		projectPath := writePlanProject(t, "12.0.0-python-3.11")
+		info := deployInfo
+		info.dagDeployEnabled = false
+
+		out := new(bytes.Buffer)
+		err := printDeployPlan(InputDeploy{Path: projectPath}, info, filepath.Join(projectPath, "dags"), mockPlatformCoreClient, out)
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "the DAGs are built into the image")
+		assert.Contains(t, out.String(), "Files built into the image")
+		assert.Contains(t, out.String(), "dags/my_dag.py")
+		assert.NotContains(t, out.String(), "my_dag.pyc")
+		assert.NotContains(t, out.String(), "other_dag.py")
+		assert.NotContains(t, out.String(), "estimated upload size")
+	})
+
+	t.Run("DAG-only deploy without DAG deploys enabled", func(t *testing.T) {
+		info := deployInfo
+		info.dagDeployEnabled = false
+
+		err := printDeployPlan(InputDeploy{Path: t.TempDir(), Dags: true}, info, "dags", nil, new(bytes.Buffer))
+		assert.ErrorContains(t, err, "DAG-only deploys are not enabled for this Deployment")
+	})
+
+	t.Run("downgraded runtime version", func(t *testing.T) {
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("GetDeploymentOptionsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&getDeploymentOptionsResponse, nil).Once()
+		projectPath := writePlanProject(t, "4.2.6")
+
+		err := printDeployPlan(InputDeploy{Path: projectPath, Image: true}, deployInfo, filepath.Join(projectPath, "dags"), mockPlatformCoreClient, new(bytes.Buffer))
+		assert.ErrorIs(t, err, errPlanInvalidRuntimeVersion)
+	})
+}
@@ -0,0 +1,323 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/astronomer/astro-cli/airflow"
+	"github.com/astronomer/astro-cli/version"
+	"github.com/pkg/errors"
+)
+
+const (
+	SBOMFormatCycloneDX = "cyclonedx"
+	SBOMFormatSPDX      = "spdx"
+
+	cosignCmd = "cosign"
+)
+
+var (
+	errSBOMDagsOnly      = errors.New("--sbom and --sign cannot be used with DAG-only deploys, since they describe the deployed image")
+	errInvalidSBOMFormat = errors.New("invalid SBOM format, the possible values are cyclonedx and spdx")
+	errSignKeyMissing    = errors.New("the signing key was not found, create one with 'cosign generate-key-pair' or pass its path with --sign-key")
+
+	// Monkey patched to write unit tests
+	cosignExec = func(env []string, args ...string) error {
+		if _, err := exec.LookPath(cosignCmd); err != nil {
+			return fmt.Errorf("failed to find the cosign command, install it from https://docs.sigstore.dev/cosign/system_config/installation/ to attach SBOMs and sign images: %w", err)
+		}
+		cmd := exec.Command(cosignCmd, args...) //nolint:gosec
+		cmd.Env = append(os.Environ(), env...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+)
+
+// sbomPackage is a package installed in the deployed image
+type sbomPackage struct {
+	name    string
+	version string
+	purl    string
+}
+
+// checkProvenanceInput checks the SBOM and signing options of a deploy before building anything
+func checkProvenanceInput(deployInput InputDeploy) error {
+	if !deployInput.SBOM && !deployInput.Sign {
+		return nil
+	}
+	if deployInput.Dags {
+		return errSBOMDagsOnly
+	}
+	if deployInput.SBOM && deployInput.SBOMFormat != SBOMFormatCycloneDX && deployInput.SBOMFormat != SBOMFormatSPDX {
+		return errInvalidSBOMFormat
+	}
+	if deployInput.Sign {
+		if _, err := os.Stat(signKeyPath(deployInput)); err != nil {
+			return errSignKeyMissing
+		}
+	}
+	return nil
+}
+
+func signKeyPath(deployInput InputDeploy) string {
+	if filepath.IsAbs(deployInput.SignKey) {
+		return deployInput.SignKey
+	}
+	return filepath.Join(deployInput.Path, deployInput.SignKey)
+}
+
+// attachProvenance attaches the SBOM of the pushed image to it as an OCI artifact and signs its digest with cosign,
+// as requested by the deploy
+func attachProvenance(deployInput InputDeploy, imageHandler airflow.ImageHandler, imageRepository, imageDigest, token string) error {
+	if !deployInput.SBOM && !deployInput.Sign {
+		return nil
+	}
+	if imageDigest == "" {
+		return errors.New("failed to get the digest of the pushed image")
+	}
+	imageRef := imageRepository + "@" + imageDigest
+
+	// cosign reads the registry credentials from a docker config rather than its command line, where the other users
+	// of the machine could see them
+	dockerConfigDir, err := os.MkdirTemp("", "astro-cosign")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dockerConfigDir)
+	err = writeRegistryDockerConfig(dockerConfigDir, imageRepository, token)
+	if err != nil {
+		return err
+	}
+	env := []string{"DOCKER_CONFIG=" + dockerConfigDir}
+
+	if deployInput.SBOM {
+		sbomDir, err := os.MkdirTemp("", "astro-sbom")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(sbomDir)
+		sbomPath := filepath.Join(sbomDir, "sbom.json")
+		err = generateSBOM(imageHandler, imageRef, deployInput.SBOMFormat, sbomPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate the SBOM of the image")
+		}
+		fmt.Printf("Attaching the %s SBOM to the image %s\n", deployInput.SBOMFormat, imageRef)
+		err = cosignExec(env, "attach", "sbom", "--sbom", sbomPath, "--type", deployInput.SBOMFormat, imageRef)
+		if err != nil {
+			return errors.Wrap(err, "failed to attach the SBOM to the image")
+		}
+	}
+
+	if deployInput.Sign {
+		fmt.Printf("Signing the image %s\n", imageRef)
+		err := cosignExec(env, "sign", "--key", signKeyPath(deployInput), "--yes", imageRef)
+		if err != nil {
+			return errors.Wrap(err, "failed to sign the image")
+		}
+	}
+	return nil
+}
+
+// writeRegistryDockerConfig writes a docker config to a directory, holding the credentials of the registry of an image
+// repository
+func writeRegistryDockerConfig(dir, imageRepository, token string) error {
+	registry, _, _ := strings.Cut(imageRepository, "/")
+	dockerConfig := map[string]interface{}{
+		"auths": map[string]interface{}{
+			registry: map[string]string{"auth": base64.StdEncoding.EncodeToString([]byte(registryUsername + ":" + token))},
+		},
+	}
+	data, err := json.Marshal(dockerConfig)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "config.json"), data, 0o600) //nolint:mnd
+}
+
+// generateSBOM writes the SBOM of an image, listing the Python packages of its pip freeze and its OS packages
+func generateSBOM(imageHandler airflow.ImageHandler, imageName, format, sbomPath string) error {
+	pipFreezePath := sbomPath + ".pip-freeze.txt"
+	err := imageHandler.CreatePipFreeze("", pipFreezePath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(pipFreezePath)
+	pipFreeze, err := os.ReadFile(pipFreezePath)
+	if err != nil {
+		return err
+	}
+
+	osPackages := new(bytes.Buffer)
+	err = imageHandler.RunCommand([]string{"dpkg-query", "--show", "--showformat=${Package}\\t${Version}\\n"}, nil, osPackages, os.Stderr)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the OS packages of the image")
+	}
+
+	packages := append(parsePipFreeze(string(pipFreeze)), parseDpkgPackages(osPackages.String())...)
+	var document interface{}
+	if format == SBOMFormatSPDX {
+		document = spdxDocument(imageName, packages, time.Now().UTC())
+	} else {
+		document = cycloneDXDocument(imageName, packages, time.Now().UTC())
+	}
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(sbomPath, data, 0o600) //nolint:mnd
+}
+
+// parsePipFreeze returns the packages of a pip freeze output, skipping the editable and URL installs without version
+func parsePipFreeze(pipFreeze string) []sbomPackage {
+	packages := []sbomPackage{}
+	for _, line := range strings.Split(pipFreeze, "\n") {
+		name, pkgVersion, found := strings.Cut(strings.TrimSpace(line), "==")
+		if !found || name == "" || pkgVersion == "" {
+			continue
+		}
+		packages = append(packages, sbomPackage{
+			name:    name,
+			version: pkgVersion,
+			purl:    fmt.Sprintf("pkg:pypi/%s@%s", strings.ToLower(name), pkgVersion),
+		})
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].name < packages[j].name })
+	return packages
+}
+
+// parseDpkgPackages returns the packages listed by dpkg-query, one "name\tversion" line per package
+func parseDpkgPackages(dpkgPackages string) []sbomPackage {
+	packages := []sbomPackage{}
+	for _, line := range strings.Split(dpkgPackages, "\n") {
+		name, pkgVersion, found := strings.Cut(strings.TrimSpace(line), "\t")
+		if !found || name == "" || pkgVersion == "" {
+			continue
+		}
+		packages = append(packages, sbomPackage{
+			name:    name,
+			version: pkgVersion,
+			purl:    fmt.Sprintf("pkg:deb/debian/%s@%s", name, pkgVersion),
+		})
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].name < packages[j].name })
+	return packages
+}
+
+type cycloneDXBOM struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    cycloneDXMetadata    `json:"metadata"`
+	Components  []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     []cycloneDXTool    `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTool struct {
+	Vendor  string `json:"vendor"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type cycloneDXComponent struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Purl    string `json:"purl,omitempty"`
+}
+
+func cycloneDXDocument(imageName string, packages []sbomPackage, created time.Time) cycloneDXBOM {
+	components := make([]cycloneDXComponent, 0, len(packages))
+	for _, p := range packages {
+		components = append(components, cycloneDXComponent{Type: "library", Name: p.name, Version: p.version, Purl: p.purl})
+	}
+	return cycloneDXBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: created.Format(time.RFC3339),
+			Tools:     []cycloneDXTool{{Vendor: "Astronomer", Name: "astro-cli", Version: version.CurrVersion}},
+			Component: cycloneDXComponent{Type: "container", Name: imageName},
+		},
+		Components: components,
+	}
+}
+
+type spdxDocumentJSON struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+func spdxDocument(imageName string, packages []sbomPackage, created time.Time) spdxDocumentJSON {
+	spdxPackages := []spdxPackage{{Name: imageName, SPDXID: "SPDXRef-Image", DownloadLocation: "NOASSERTION"}}
+	relationships := []spdxRelationship{{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-Image"}}
+	for i, p := range packages {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		spdxPackages = append(spdxPackages, spdxPackage{
+			Name:             p.name,
+			SPDXID:           id,
+			VersionInfo:      p.version,
+			DownloadLocation: "NOASSERTION",
+			ExternalRefs:     []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: p.purl}},
+		})
+		relationships = append(relationships, spdxRelationship{SPDXElementID: "SPDXRef-Image", RelationshipType: "CONTAINS", RelatedSPDXElement: id})
+	}
+	return spdxDocumentJSON{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              imageName,
+		DocumentNamespace: fmt.Sprintf("https://astronomer.io/spdx/%s-%d", strings.NewReplacer("/", "-", ":", "-", "@", "-").Replace(imageName), created.Unix()),
+		CreationInfo: spdxCreationInfo{
+			Created:  created.Format(time.RFC3339),
+			Creators: []string{"Organization: Astronomer", "Tool: astro-cli-" + version.CurrVersion},
+		},
+		Packages:      spdxPackages,
+		Relationships: relationships,
+	}
+}
@@ -0,0 +1,162 @@
+package deploy
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astronomer/astro-cli/airflow/mocks"
+	astroplatformcore_mocks "github.com/astronomer/astro-cli/astro-client-platform-core/mocks"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testPipFreeze    = "apache-airflow==2.10.3\n-e git+https://github.com/org/repo.git#egg=local\nPyYAML==6.0.2\n"
+	testDpkgPackages = "libc6\t2.36-9\nbash\t5.2.15-2\n"
+)
+
+func mockSBOMImageHandler() *mocks.ImageHandler {
+	mockImageHandler := new(mocks.ImageHandler)
+	mockImageHandler.On("CreatePipFreeze", "", mock.Anything).Return(func(altImageName, pipFreezeFile string) error {
+		return os.WriteFile(pipFreezeFile, []byte(testPipFreeze), 0o600)
+	}).Once()
+	mockImageHandler.On("RunCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(func(args []string, mountDirs map[string]string, stdout, stderr io.Writer) error {
+		_, err := stdout.Write([]byte(testDpkgPackages))
+		return err
+	}).Once()
+	return mockImageHandler
+}
+
+func TestParseSBOMPackages(t *testing.T) {
+	assert.Equal(t, []sbomPackage{
+		{name: "PyYAML", version: "6.0.2", purl: "pkg:pypi/pyyaml@6.0.2"},
+		{name: "apache-airflow", version: "2.10.3", purl: "pkg:pypi/apache-airflow@2.10.3"},
+	}, parsePipFreeze(testPipFreeze))
+	assert.Equal(t, []sbomPackage{
+		{name: "bash", version: "5.2.15-2", purl: "pkg:deb/debian/bash@5.2.15-2"},
+		{name: "libc6", version: "2.36-9", purl: "pkg:deb/debian/libc6@2.36-9"},
+	}, parseDpkgPackages(testDpkgPackages))
+}
+
+func TestSBOMDocuments(t *testing.T) {
+	packages := []sbomPackage{{name: "apache-airflow", version: "2.10.3", purl: "pkg:pypi/apache-airflow@2.10.3"}}
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	bom := cycloneDXDocument("repo@sha256:abc", packages, created)
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "2024-01-01T00:00:00Z", bom.Metadata.Timestamp)
+	assert.Equal(t, "repo@sha256:abc", bom.Metadata.Component.Name)
+	assert.Equal(t, []cycloneDXComponent{{Type: "library", Name: "apache-airflow", Version: "2.10.3", Purl: "pkg:pypi/apache-airflow@2.10.3"}}, bom.Components)
+
+	doc := spdxDocument("repo@sha256:abc", packages, created)
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Len(t, doc.Packages, 2)
+	assert.Equal(t, "SPDXRef-Image", doc.Packages[0].SPDXID)
+	assert.Equal(t, "pkg:pypi/apache-airflow@2.10.3", doc.Packages[1].ExternalRefs[0].ReferenceLocator)
+	assert.Equal(t, spdxRelationship{SPDXElementID: "SPDXRef-Image", RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-Package-1"}, doc.Relationships[1])
+}
+
+func TestGenerateSBOM(t *testing.T) {
+	sbomPath := filepath.Join(t.TempDir(), "sbom.json")
+	mockImageHandler := mockSBOMImageHandler()
+
+	err := generateSBOM(mockImageHandler, "repo@sha256:abc", SBOMFormatCycloneDX, sbomPath)
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(sbomPath)
+	assert.NoError(t, err)
+	var bom cycloneDXBOM
+	assert.NoError(t, json.Unmarshal(data, &bom))
+	assert.Len(t, bom.Components, 4)
+	assert.NoFileExists(t, sbomPath+".pip-freeze.txt")
+	mockImageHandler.AssertExpectations(t)
+}
+
+func TestCheckProvenanceInput(t *testing.T) {
+	projectPath := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(projectPath, "cosign.key"), []byte("key"), 0o600))
+
+	assert.NoError(t, checkProvenanceInput(InputDeploy{Dags: true}))
+	assert.NoError(t, checkProvenanceInput(InputDeploy{Path: projectPath, SBOM: true, SBOMFormat: SBOMFormatSPDX, Sign: true, SignKey: "cosign.key"}))
+	assert.ErrorIs(t, checkProvenanceInput(InputDeploy{SBOM: true, SBOMFormat: SBOMFormatCycloneDX, Dags: true}), errSBOMDagsOnly)
+	assert.ErrorIs(t, checkProvenanceInput(InputDeploy{SBOM: true, SBOMFormat: "xml"}), errInvalidSBOMFormat)
+	assert.ErrorIs(t, checkProvenanceInput(InputDeploy{Path: projectPath, Sign: true, SignKey: "missing.key"}), errSignKeyMissing)
+}
+
+func TestAttachProvenance(t *testing.T) {
+	var cosignCalls [][]string
+	var dockerConfigs []string
+	cosignExec = func(env []string, args ...string) error {
+		cosignCalls = append(cosignCalls, args)
+		for _, v := range env {
+			if dir, ok := strings.CutPrefix(v, "DOCKER_CONFIG="); ok {
+				dockerConfig, err := os.ReadFile(filepath.Join(dir, "config.json"))
+				assert.NoError(t, err)
+				dockerConfigs = append(dockerConfigs, string(dockerConfig))
+			}
+		}
+		return nil
+	}
+
+	t.Run("attaches the SBOM and signs the digest", func(t *testing.T) {
+		cosignCalls, dockerConfigs = nil, nil
+		mockImageHandler := mockSBOMImageHandler()
+		deployInput := InputDeploy{Path: "/project", SBOM: true, SBOMFormat: SBOMFormatSPDX, Sign: true, SignKey: "cosign.key"}
+
+		err := attachProvenance(deployInput, mockImageHandler, "images.astronomer.cloud/org/deployment", "sha256:abc", "token")
+		assert.NoError(t, err)
+
+		assert.Len(t, cosignCalls, 2)
+		assert.Equal(t, []string{"attach", "sbom", "--sbom"}, cosignCalls[0][:3])
+		assert.Equal(t, []string{"--type", "spdx", "images.astronomer.cloud/org/deployment@sha256:abc"}, cosignCalls[0][4:])
+		assert.Equal(t, []string{"sign", "--key", filepath.Join("/project", "cosign.key"), "--yes", "images.astronomer.cloud/org/deployment@sha256:abc"}, cosignCalls[1])
+		// the registry credentials are passed through a docker config, base64 of "cli:token"
+		expectedConfig := `{"auths":{"images.astronomer.cloud":{"auth":"Y2xpOnRva2Vu"}}}`
+		assert.Equal(t, []string{expectedConfig, expectedConfig}, dockerConfigs)
+		mockImageHandler.AssertExpectations(t)
+	})
+
+	t.Run("nothing requested", func(t *testing.T) {
+		cosignCalls = nil
+		err := attachProvenance(InputDeploy{}, nil, "repo", "", "token")
+		assert.NoError(t, err)
+		assert.Empty(t, cosignCalls)
+	})
+
+	t.Run("missing digest", func(t *testing.T) {
+		err := attachProvenance(InputDeploy{Sign: true}, nil, "repo", "", "token")
+		assert.ErrorContains(t, err, "failed to get the digest of the pushed image")
+	})
+
+	t.Run("signing failure", func(t *testing.T) {
+		cosignExec = func(env []string, args ...string) error {
+			return errMock
+		}
+		err := attachProvenance(InputDeploy{Sign: true, SignKey: "cosign.key"}, nil, "repo", "sha256:abc", "token")
+		assert.ErrorIs(t, err, errMock)
+	})
+
+	t.Run("a failure leaves the deploy unfinalized", func(t *testing.T) {
+		testUtil.InitTestConfig(testUtil.LocalPlatform)
+		cosignExec = func(env []string, args ...string) error {
+			return errMock
+		}
+		mockImageHandler := new(mocks.ImageHandler)
+		mockImageHandler.On("Push", mock.Anything, mock.Anything, mock.Anything, true).Return("sha256:abc", nil).Once()
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("CreateDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&createDeployResponse, nil).Once()
+
+		deployInput := InputDeploy{Path: t.TempDir(), Sign: true, SignKey: "cosign.key", Description: "signed", SkipLock: true}
+		target := &targetDeploy{info: deploymentInfo{deploymentID: deploymentID, organizationID: "test-org-id"}}
+		err := deployImageToTarget(deployInput, target, mockImageHandler, "token", "", false, mockPlatformCoreClient, nil)
+		assert.ErrorIs(t, err, errMock)
+		mockPlatformCoreClient.AssertExpectations(t)
+		mockPlatformCoreClient.AssertNotCalled(t, "FinalizeDeployWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
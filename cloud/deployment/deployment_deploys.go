@@ -0,0 +1,189 @@
+package deployment
+
+import (
+	httpContext "context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	"github.com/astronomer/astro-cli/pkg/ansi"
+	"github.com/astronomer/astro-cli/pkg/input"
+	"github.com/astronomer/astro-cli/pkg/printutil"
+)
+
+var errRollbackToFailed = errors.New("the deploy did not succeed, choose a successful deploy to roll back to")
+
+const shortCommitLength = 7
+
+func newDeploysTableOut() *printutil.Table {
+	return &printutil.Table{
+		DynamicPadding: true,
+		Header:         []string{"DEPLOY ID", "TYPE", "STATUS", "IMAGE TAG", "DAG TARBALL VERSION", "GIT COMMIT", "CREATED", "CREATED BY", "DESCRIPTION"},
+		NoResultsMsg:   "No deploys found for this Deployment",
+	}
+}
+
+// ListDeploys lists the most recent deploys of a Deployment, newest first
+func ListDeploys(deploymentID, ws, deploymentName string, limit int, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient, out io.Writer) error {
+	currentDeployment, err := GetDeployment(ws, deploymentID, deploymentName, false, nil, platformCoreClient, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := coreClient.ListDeploysWithResponse(httpContext.Background(), currentDeployment.OrganizationId, currentDeployment.Id, &astrocore.ListDeploysParams{Limit: &limit})
+	if err != nil {
+		return err
+	}
+	err = astrocore.NormalizeAPIError(resp.HTTPResponse, resp.Body)
+	if err != nil {
+		return err
+	}
+
+	tab := newDeploysTableOut()
+	for i := range resp.JSON200.Deploys {
+		d := &resp.JSON200.Deploys[i]
+		tab.AddRow([]string{d.Id, string(d.Type), string(d.Status), d.ImageTag, stringValue(d.DagTarballVersion), deployCommit(d), TimeAgo(d.CreatedAt), DeployCreatedBy(d), stringValue(d.Description)}, false)
+	}
+	return tab.Print(out)
+}
+
+// DiffDeploys compares two deploys of a Deployment, highlighting the fields that changed between them
+func DiffDeploys(deploymentID, ws, deploymentName, fromDeployID, toDeployID string, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient, out io.Writer) error {
+	currentDeployment, err := GetDeployment(ws, deploymentID, deploymentName, false, nil, platformCoreClient, nil)
+	if err != nil {
+		return err
+	}
+
+	from, err := getDeploy(currentDeployment.OrganizationId, currentDeployment.Id, fromDeployID, coreClient)
+	if err != nil {
+		return err
+	}
+	to, err := getDeploy(currentDeployment.OrganizationId, currentDeployment.Id, toDeployID, coreClient)
+	if err != nil {
+		return err
+	}
+
+	tab := &printutil.Table{
+		DynamicPadding: true,
+		Header:         []string{"FIELD", from.Id, to.Id},
+	}
+	fromFields, toFields := deployDiffFields(from), deployDiffFields(to)
+	for i := range fromFields {
+		tab.AddRow([]string{fromFields[i][0], fromFields[i][1], toFields[i][1]}, fromFields[i][1] != toFields[i][1])
+	}
+	return tab.Print(out)
+}
+
+// Rollback redeploys the image and the DAGs of a previous deploy of a Deployment, without rebuilding them
+func Rollback(deploymentID, ws, deploymentName, deployID, description string, force bool, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient, out io.Writer) error {
+	currentDeployment, err := GetDeployment(ws, deploymentID, deploymentName, false, nil, platformCoreClient, nil)
+	if err != nil {
+		return err
+	}
+
+	target, err := getDeploy(currentDeployment.OrganizationId, currentDeployment.Id, deployID, coreClient)
+	if err != nil {
+		return err
+	}
+	if target.Status == astrocore.FAILED {
+		return errRollbackToFailed
+	}
+
+	if !force {
+		i, _ := input.Confirm(
+			fmt.Sprintf("\nAre you sure you want to roll back the %s Deployment to the deploy %s with the image tag %s?", ansi.Bold(currentDeployment.Name), ansi.Bold(target.Id), ansi.Bold(target.ImageTag)))
+		if !i {
+			fmt.Fprintln(out, "Canceling rollback")
+			return nil
+		}
+	}
+
+	request := astrocore.DeployRollbackRequest{DeployId: target.Id}
+	if description != "" {
+		request.Description = &description
+	}
+	resp, err := coreClient.DeployRollbackWithResponse(httpContext.Background(), currentDeployment.OrganizationId, currentDeployment.Id, request)
+	if err != nil {
+		return err
+	}
+	err = astrocore.NormalizeAPIError(resp.HTTPResponse, resp.Body)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nSuccessfully started the rollback of the %s Deployment to the deploy %s\n", ansi.Bold(currentDeployment.Name), ansi.Bold(target.Id))
+	if resp.JSON200 != nil {
+		fmt.Fprintf(out, "The rollback is the deploy %s, follow it with 'astro deployment deploys list --deployment-id %s'\n", ansi.Bold(resp.JSON200.Id), currentDeployment.Id)
+	}
+	return nil
+}
+
+func getDeploy(organizationID, deploymentID, deployID string, coreClient astrocore.CoreClient) (*astrocore.Deploy, error) {
+	resp, err := coreClient.GetDeployWithResponse(httpContext.Background(), organizationID, deploymentID, deployID, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = astrocore.NormalizeAPIError(resp.HTTPResponse, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return resp.JSON200, nil
+}
+
+// deployDiffFields returns the fields of a deploy compared by DiffDeploys, in display order
+func deployDiffFields(d *astrocore.Deploy) [][2]string {
+	var repo, branch, commit string
+	if d.Git != nil {
+		repo = d.Git.Account + "/" + d.Git.Repo
+		branch = d.Git.Branch
+		commit = d.Git.CommitSha
+	}
+	return [][2]string{
+		{"TYPE", string(d.Type)},
+		{"STATUS", string(d.Status)},
+		{"IMAGE REPOSITORY", d.ImageRepository},
+		{"IMAGE TAG", d.ImageTag},
+		{"RUNTIME VERSION", stringValue(d.RuntimeVersion)},
+		{"DAG TARBALL VERSION", stringValue(d.DagTarballVersion)},
+		{"GIT REPOSITORY", repo},
+		{"GIT BRANCH", branch},
+		{"GIT COMMIT", commit},
+		{"DESCRIPTION", stringValue(d.Description)},
+		{"CREATED AT", d.CreatedAt.Format(time.RFC3339)},
+		{"CREATED BY", DeployCreatedBy(d)},
+	}
+}
+
+func deployCommit(d *astrocore.Deploy) string {
+	if d.Git == nil {
+		return ""
+	}
+	if len(d.Git.CommitSha) > shortCommitLength {
+		return d.Git.CommitSha[:shortCommitLength]
+	}
+	return d.Git.CommitSha
+}
+
+// DeployCreatedBy returns the name of the user or of the API token that created a deploy
+func DeployCreatedBy(d *astrocore.Deploy) string {
+	if d.CreatedBySubject == nil {
+		return ""
+	}
+	switch {
+	case d.CreatedBySubject.FullName != nil:
+		return *d.CreatedBySubject.FullName
+	case d.CreatedBySubject.ApiTokenName != nil:
+		return *d.CreatedBySubject.ApiTokenName
+	}
+	return ""
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
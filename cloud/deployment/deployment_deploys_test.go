@@ -0,0 +1,170 @@
+package deployment
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	astrocore_mocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
+	astroplatformcore_mocks "github.com/astronomer/astro-cli/astro-client-platform-core/mocks"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	dagTarballVersion = "2024-01-02T00:00:00"
+	deployDescription = "fix the sales DAG"
+	deployCreatedAt   = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	imageDeploy       = astrocore.Deploy{
+		Id:                "deploy-1",
+		DeploymentId:      "test-id-1",
+		Type:              astrocore.DeployTypeIMAGE,
+		Status:            astrocore.DEPLOYED,
+		ImageRepository:   "images.astronomer.cloud/test",
+		ImageTag:          "deploy-2024-01-01",
+		DagTarballVersion: &dagTarballVersion,
+		CreatedAt:         deployCreatedAt,
+		Git: &astrocore.DeployGit{
+			Account:   "astronomer",
+			Repo:      "dags",
+			Branch:    "main",
+			CommitSha: "0123456789abcdef",
+		},
+	}
+	dagOnlyDeploy = astrocore.Deploy{
+		Id:                "deploy-2",
+		DeploymentId:      "test-id-1",
+		Type:              astrocore.DeployTypeDAG,
+		Status:            astrocore.DEPLOYED,
+		ImageRepository:   "images.astronomer.cloud/test",
+		ImageTag:          "deploy-2024-01-01",
+		DagTarballVersion: &dagTarballVersion,
+		Description:       &deployDescription,
+		CreatedAt:         deployCreatedAt,
+	}
+	failedDeploy = astrocore.Deploy{
+		Id:        "deploy-3",
+		Type:      astrocore.DeployTypeIMAGE,
+		Status:    astrocore.FAILED,
+		CreatedAt: deployCreatedAt,
+	}
+)
+
+func getDeployResponse(deploy *astrocore.Deploy) *astrocore.GetDeployResponse {
+	return &astrocore.GetDeployResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200:      deploy,
+	}
+}
+
+func (s *Suite) TestListDeploys() {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+
+	s.Run("success", func() {
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("ListDeploymentsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&mockListDeploymentsResponse, nil).Once()
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Once()
+		limit := 10
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, "test-id-1", &astrocore.ListDeploysParams{Limit: &limit}).Return(&astrocore.ListDeploysResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			JSON200:      &astrocore.DeploysPaginated{Deploys: []astrocore.Deploy{dagOnlyDeploy, imageDeploy}},
+		}, nil).Once()
+
+		out := new(bytes.Buffer)
+		err := ListDeploys("test-id-1", ws, "", limit, mockPlatformCoreClient, mockCoreClient, out)
+		s.NoError(err)
+		s.Contains(out.String(), "deploy-1")
+		s.Contains(out.String(), "deploy-2")
+		s.Contains(out.String(), "0123456")
+		s.NotContains(out.String(), "0123456789")
+		s.Contains(out.String(), deployDescription)
+		mockPlatformCoreClient.AssertExpectations(s.T())
+		mockCoreClient.AssertExpectations(s.T())
+	})
+
+	s.Run("list deploys failure", func() {
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("ListDeploymentsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&mockListDeploymentsResponse, nil).Once()
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Once()
+		mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errMock).Once()
+
+		err := ListDeploys("test-id-1", ws, "", 10, mockPlatformCoreClient, mockCoreClient, new(bytes.Buffer))
+		s.ErrorIs(err, errMock)
+	})
+}
+
+func (s *Suite) TestDiffDeploys() {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+
+	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockPlatformCoreClient.On("ListDeploymentsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&mockListDeploymentsResponse, nil).Once()
+	mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Once()
+	mockCoreClient.On("GetDeployWithResponse", mock.Anything, mock.Anything, "test-id-1", "deploy-1", mock.Anything).Return(getDeployResponse(&imageDeploy), nil).Once()
+	mockCoreClient.On("GetDeployWithResponse", mock.Anything, mock.Anything, "test-id-1", "deploy-2", mock.Anything).Return(getDeployResponse(&dagOnlyDeploy), nil).Once()
+
+	out := new(bytes.Buffer)
+	err := DiffDeploys("test-id-1", ws, "", "deploy-1", "deploy-2", mockPlatformCoreClient, mockCoreClient, out)
+	s.NoError(err)
+	s.Contains(out.String(), "deploy-1")
+	s.Contains(out.String(), "deploy-2")
+	s.Contains(out.String(), "astronomer/dags")
+	s.Contains(out.String(), "0123456789abcdef")
+	s.Contains(out.String(), deployDescription)
+	mockPlatformCoreClient.AssertExpectations(s.T())
+	mockCoreClient.AssertExpectations(s.T())
+}
+
+func (s *Suite) TestRollback() {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+
+	s.Run("success", func() {
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("ListDeploymentsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&mockListDeploymentsResponse, nil).Once()
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Once()
+		mockCoreClient.On("GetDeployWithResponse", mock.Anything, mock.Anything, "test-id-1", "deploy-1", mock.Anything).Return(getDeployResponse(&imageDeploy), nil).Once()
+		mockCoreClient.On("DeployRollbackWithResponse", mock.Anything, mock.Anything, "test-id-1", astrocore.DeployRollbackRequest{DeployId: "deploy-1", Description: &deployDescription}).Return(&astrocore.DeployRollbackResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			JSON200:      &astrocore.Deploy{Id: "deploy-4"},
+		}, nil).Once()
+
+		out := new(bytes.Buffer)
+		err := Rollback("test-id-1", ws, "", "deploy-1", deployDescription, true, mockPlatformCoreClient, mockCoreClient, out)
+		s.NoError(err)
+		s.Contains(out.String(), "Successfully started the rollback")
+		s.Contains(out.String(), "deploy-4")
+		mockPlatformCoreClient.AssertExpectations(s.T())
+		mockCoreClient.AssertExpectations(s.T())
+	})
+
+	s.Run("rollback to a failed deploy", func() {
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("ListDeploymentsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&mockListDeploymentsResponse, nil).Once()
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Once()
+		mockCoreClient.On("GetDeployWithResponse", mock.Anything, mock.Anything, "test-id-1", "deploy-3", mock.Anything).Return(getDeployResponse(&failedDeploy), nil).Once()
+
+		err := Rollback("test-id-1", ws, "", "deploy-3", "", true, mockPlatformCoreClient, mockCoreClient, new(bytes.Buffer))
+		s.ErrorIs(err, errRollbackToFailed)
+		mockCoreClient.AssertNotCalled(s.T(), "DeployRollbackWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	s.Run("cancel rollback", func() {
+		mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockPlatformCoreClient.On("ListDeploymentsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&mockListDeploymentsResponse, nil).Once()
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Once()
+		mockCoreClient.On("GetDeployWithResponse", mock.Anything, mock.Anything, "test-id-1", "deploy-1", mock.Anything).Return(getDeployResponse(&imageDeploy), nil).Once()
+		defer testUtil.MockUserInput(s.T(), "n")()
+
+		out := new(bytes.Buffer)
+		err := Rollback("test-id-1", ws, "", "deploy-1", "", false, mockPlatformCoreClient, mockCoreClient, out)
+		s.NoError(err)
+		s.Contains(out.String(), "Canceling rollback")
+		mockCoreClient.AssertNotCalled(s.T(), "DeployRollbackWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -1,14 +1,33 @@
 package deployment
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	airflowclient "github.com/astronomer/astro-cli/airflow-client"
 	"github.com/astronomer/astro-cli/pkg/printutil"
 	"github.com/astronomer/astro-cli/pkg/util"
+	"github.com/astronomer/astro-cli/settings"
 )
 
+// sensitiveFieldNames are the names Airflow masks by default, a field or variable is sensitive if its name contains one of them
+var sensitiveFieldNames = []string{
+	"access_token",
+	"api_key",
+	"apikey",
+	"authorization",
+	"passphrase",
+	"passwd",
+	"password",
+	"private_key",
+	"secret",
+	"token",
+	"keyfile_dict",
+	"service_account",
+}
+
 // Variable epresents the structure of an Airflow variable
 type Pool struct {
 	Description string `json:"description"`
@@ -298,3 +317,105 @@ func CopyPool(fromAirflowURL, toAirflowURL string, airflowAPIClient airflowclien
 	}
 	return nil
 }
+
+// PullObjects writes the connections, variables and pools of a Deployment to a local settings file, replacing the local
+// objects with the same ids. With redactSecrets the connection passwords, the sensitive connection extra fields and the
+// sensitive variables are left empty.
+func PullObjects(airflowURL, settingsFile string, connections, variables, pools, redactSecrets bool, airflowAPIClient airflowclient.Client, out io.Writer) error {
+	// pull all objects if no object type is specified
+	if !connections && !variables && !pools {
+		connections, variables, pools = true, true, true
+	}
+
+	objects := settings.Airflow{}
+	if connections {
+		resp, err := airflowAPIClient.GetConnections(airflowURL)
+		if err != nil {
+			return err
+		}
+		for i := range resp.Connections {
+			objects.Connections = append(objects.Connections, settingsConnection(&resp.Connections[i], redactSecrets))
+		}
+		fmt.Fprintf(out, "Pulled %d connections\n", len(resp.Connections))
+	}
+	if variables {
+		resp, err := airflowAPIClient.GetVariables(airflowURL)
+		if err != nil {
+			return err
+		}
+		for i := range resp.Variables {
+			value := resp.Variables[i].Value
+			if redactSecrets && isSensitiveField(resp.Variables[i].Key) {
+				value = ""
+			}
+			objects.Variables = append(objects.Variables, settings.Variables{{VariableName: resp.Variables[i].Key, VariableValue: value}}...)
+		}
+		fmt.Fprintf(out, "Pulled %d variables\n", len(resp.Variables))
+	}
+	if pools {
+		resp, err := airflowAPIClient.GetPools(airflowURL)
+		if err != nil {
+			return err
+		}
+		for i := range resp.Pools {
+			objects.Pools = append(objects.Pools, settings.Pools{{PoolName: resp.Pools[i].Name, PoolSlot: resp.Pools[i].Slots, PoolDescription: resp.Pools[i].Description}}...)
+		}
+		fmt.Fprintf(out, "Pulled %d pools\n", len(resp.Pools))
+	}
+
+	if err := settings.MergeObjects(settingsFile, &objects); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Successfully wrote the Deployment objects to %s\n", settingsFile)
+	if redactSecrets {
+		fmt.Fprintln(out, "Secret values were redacted, add them to the settings file before importing the objects")
+	}
+	return nil
+}
+
+// settingsConnection converts an Airflow API connection to a settings file connection
+func settingsConnection(conn *airflowclient.Connection, redactSecrets bool) settings.Connection {
+	newConnection := settings.Connection{
+		ConnID:       conn.ConnID,
+		ConnType:     conn.ConnType,
+		ConnHost:     conn.Host,
+		ConnSchema:   conn.Schema,
+		ConnLogin:    conn.Login,
+		ConnPassword: conn.Password,
+		ConnPort:     conn.Port,
+	}
+	if redactSecrets {
+		newConnection.ConnPassword = ""
+	}
+	if conn.Extra == "" {
+		return newConnection
+	}
+
+	// keep the extra as a mapping when it is a JSON object, so it is readable in the settings file
+	var extra map[string]interface{}
+	if err := json.Unmarshal([]byte(conn.Extra), &extra); err != nil {
+		if !redactSecrets {
+			newConnection.ConnExtra = conn.Extra
+		}
+		return newConnection
+	}
+	if redactSecrets {
+		for key := range extra {
+			if isSensitiveField(key) {
+				extra[key] = ""
+			}
+		}
+	}
+	newConnection.ConnExtra = extra
+	return newConnection
+}
+
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitiveName := range sensitiveFieldNames {
+		if strings.Contains(name, sensitiveName) {
+			return true
+		}
+	}
+	return false
+}
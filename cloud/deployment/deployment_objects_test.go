@@ -3,9 +3,12 @@ package deployment
 import (
 	"bytes"
 	"errors"
+	"os"
+	"path/filepath"
 
 	airflowclient "github.com/astronomer/astro-cli/airflow-client"
 	airflowclient_mocks "github.com/astronomer/astro-cli/airflow-client/mocks"
+	"github.com/astronomer/astro-cli/settings"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -186,6 +189,47 @@ func (s *Suite) TestAirflowVariableList() {
 		s.NoError(err)
 	})
 
+	s.Run("keeps the secrets of the settings file the Deployment does not return", func() {
+		settings.WorkingPath = s.T().TempDir()
+		encryptedPassword := "ENC[AES256_GCM,data:cGFzc3dvcmQ=,iv:aXY=,tag:dGFn,type:str]"
+		s.NoError(os.WriteFile(filepath.Join(settings.WorkingPath, "airflow_settings.yaml"), []byte(`airflow:
+  connections:
+    - conn_id: conn1
+      conn_type: postgres
+      conn_password: "`+encryptedPassword+`"
+    - conn_id: conn2
+      conn_type: http
+      conn_extra:
+        api_key: local-key
+  variables:
+    - variable_name: db_password
+      variable_value: local-password
+`), 0o644))
+		redactedResp := airflowclient.Response{
+			Connections: []airflowclient.Connection{
+				{ConnID: "conn1", ConnType: "postgres", Host: "new-host"},
+				{ConnID: "conn2", ConnType: "http", Extra: `{"api_key": "***", "timeout": 10}`},
+			},
+			Variables: []airflowclient.Variable{{Key: "db_password", Value: "***"}},
+		}
+		out := new(bytes.Buffer)
+		mockClient := new(airflowclient_mocks.Client)
+		mockClient.On("GetConnections", testAirflowURL).Return(redactedResp, nil).Once()
+		mockClient.On("GetVariables", testAirflowURL).Return(redactedResp, nil).Once()
+		err := PullObjects(testAirflowURL, "airflow_settings.yaml", true, true, false, false, mockClient, out)
+		s.NoError(err)
+		mockClient.AssertExpectations(s.T())
+
+		content, err := os.ReadFile(filepath.Join(settings.WorkingPath, "airflow_settings.yaml"))
+		s.NoError(err)
+		s.Contains(string(content), "new-host")
+		s.Contains(string(content), encryptedPassword)
+		s.Contains(string(content), "api_key: local-key")
+		s.Contains(string(content), "timeout: 10")
+		s.Contains(string(content), "local-password")
+		s.NotContains(string(content), "***")
+	})
+
 	s.Run("error path when GetVariables returns an error", func() {
 		out := new(bytes.Buffer)
 		mockClient := new(airflowclient_mocks.Client)
@@ -264,6 +308,47 @@ func (s *Suite) TestCopyVariable() {
 		s.NoError(err)
 	})
 
+	s.Run("keeps the secrets of the settings file the Deployment does not return", func() {
+		settings.WorkingPath = s.T().TempDir()
+		encryptedPassword := "ENC[AES256_GCM,data:cGFzc3dvcmQ=,iv:aXY=,tag:dGFn,type:str]"
+		s.NoError(os.WriteFile(filepath.Join(settings.WorkingPath, "airflow_settings.yaml"), []byte(`airflow:
+  connections:
+    - conn_id: conn1
+      conn_type: postgres
+      conn_password: "`+encryptedPassword+`"
+    - conn_id: conn2
+      conn_type: http
+      conn_extra:
+        api_key: local-key
+  variables:
+    - variable_name: db_password
+      variable_value: local-password
+`), 0o644))
+		redactedResp := airflowclient.Response{
+			Connections: []airflowclient.Connection{
+				{ConnID: "conn1", ConnType: "postgres", Host: "new-host"},
+				{ConnID: "conn2", ConnType: "http", Extra: `{"api_key": "***", "timeout": 10}`},
+			},
+			Variables: []airflowclient.Variable{{Key: "db_password", Value: "***"}},
+		}
+		out := new(bytes.Buffer)
+		mockClient := new(airflowclient_mocks.Client)
+		mockClient.On("GetConnections", testAirflowURL).Return(redactedResp, nil).Once()
+		mockClient.On("GetVariables", testAirflowURL).Return(redactedResp, nil).Once()
+		err := PullObjects(testAirflowURL, "airflow_settings.yaml", true, true, false, false, mockClient, out)
+		s.NoError(err)
+		mockClient.AssertExpectations(s.T())
+
+		content, err := os.ReadFile(filepath.Join(settings.WorkingPath, "airflow_settings.yaml"))
+		s.NoError(err)
+		s.Contains(string(content), "new-host")
+		s.Contains(string(content), encryptedPassword)
+		s.Contains(string(content), "api_key: local-key")
+		s.Contains(string(content), "timeout: 10")
+		s.Contains(string(content), "local-password")
+		s.NotContains(string(content), "***")
+	})
+
 	s.Run("error path when GetVariables returns an error", func() {
 		out := new(bytes.Buffer)
 		mockClient := new(airflowclient_mocks.Client)
@@ -464,3 +549,130 @@ func (s *Suite) TestCopyPool() {
 		s.Equal("error", err.Error())
 	})
 }
+
+func (s *Suite) TestPullObjects() {
+	origWorkingPath := settings.WorkingPath
+	defer func() { settings.WorkingPath = origWorkingPath }()
+
+	pullResp := airflowclient.Response{
+		Connections: []airflowclient.Connection{
+			{ConnID: "conn1", ConnType: "postgres", Host: "new-host", Login: "user", Password: "conn-password", Port: 5432},
+			{ConnID: "conn2", ConnType: "http", Extra: `{"api_key": "extra-key", "timeout": 10}`},
+		},
+		Variables: []airflowclient.Variable{
+			{Key: "var1", Value: "value1"},
+			{Key: "db_password", Value: "var-password"},
+		},
+		Pools: []airflowclient.Pool{
+			{Name: "default_pool", Slots: 128, Description: "Default pool"},
+		},
+	}
+	localSettings := `airflow:
+  connections:
+    - conn_id: conn1
+      conn_type: postgres
+      conn_host: old-host
+    - conn_id: local
+      conn_type: http
+  variables:
+    - variable_name: local_var
+      variable_value: local_value
+`
+
+	s.Run("merges the objects into the settings file", func() {
+		settings.WorkingPath = s.T().TempDir()
+		s.NoError(os.WriteFile(filepath.Join(settings.WorkingPath, "airflow_settings.yaml"), []byte(localSettings), 0o644))
+		out := new(bytes.Buffer)
+		mockClient := new(airflowclient_mocks.Client)
+		mockClient.On("GetConnections", testAirflowURL).Return(pullResp, nil).Once()
+		mockClient.On("GetVariables", testAirflowURL).Return(pullResp, nil).Once()
+		mockClient.On("GetPools", testAirflowURL).Return(pullResp, nil).Once()
+		err := PullObjects(testAirflowURL, "airflow_settings.yaml", false, false, false, false, mockClient, out)
+		s.NoError(err)
+		mockClient.AssertExpectations(s.T())
+		s.Contains(out.String(), "Pulled 2 connections")
+
+		content, err := os.ReadFile(filepath.Join(settings.WorkingPath, "airflow_settings.yaml"))
+		s.NoError(err)
+		s.Contains(string(content), "new-host")
+		s.NotContains(string(content), "old-host")
+		s.Contains(string(content), "conn_id: local")
+		s.Contains(string(content), "local_value")
+		s.Contains(string(content), "conn-password")
+		s.Contains(string(content), "extra-key")
+		s.Contains(string(content), "var-password")
+		s.Contains(string(content), "pool_name: default_pool")
+	})
+
+	s.Run("redacts secrets", func() {
+		settings.WorkingPath = s.T().TempDir()
+		out := new(bytes.Buffer)
+		mockClient := new(airflowclient_mocks.Client)
+		mockClient.On("GetConnections", testAirflowURL).Return(pullResp, nil).Once()
+		mockClient.On("GetVariables", testAirflowURL).Return(pullResp, nil).Once()
+		err := PullObjects(testAirflowURL, "airflow_settings.yaml", true, true, false, true, mockClient, out)
+		s.NoError(err)
+		mockClient.AssertExpectations(s.T())
+
+		content, err := os.ReadFile(filepath.Join(settings.WorkingPath, "airflow_settings.yaml"))
+		s.NoError(err)
+		s.Contains(string(content), "conn_id: conn1")
+		s.Contains(string(content), "timeout: 10")
+		s.Contains(string(content), "value1")
+		s.NotContains(string(content), "conn-password")
+		s.NotContains(string(content), "extra-key")
+		s.NotContains(string(content), "var-password")
+		s.NotContains(string(content), "default_pool")
+	})
+
+	s.Run("keeps the secrets of the settings file the Deployment does not return", func() {
+		settings.WorkingPath = s.T().TempDir()
+		encryptedPassword := "ENC[AES256_GCM,data:cGFzc3dvcmQ=,iv:aXY=,tag:dGFn,type:str]"
+		s.NoError(os.WriteFile(filepath.Join(settings.WorkingPath, "airflow_settings.yaml"), []byte(`airflow:
+  connections:
+    - conn_id: conn1
+      conn_type: postgres
+      conn_password: "`+encryptedPassword+`"
+    - conn_id: conn2
+      conn_type: http
+      conn_extra:
+        api_key: local-key
+  variables:
+    - variable_name: db_password
+      variable_value: local-password
+`), 0o644))
+		redactedResp := airflowclient.Response{
+			Connections: []airflowclient.Connection{
+				{ConnID: "conn1", ConnType: "postgres", Host: "new-host"},
+				{ConnID: "conn2", ConnType: "http", Extra: `{"api_key": "***", "timeout": 10}`},
+			},
+			Variables: []airflowclient.Variable{{Key: "db_password", Value: "***"}},
+		}
+		out := new(bytes.Buffer)
+		mockClient := new(airflowclient_mocks.Client)
+		mockClient.On("GetConnections", testAirflowURL).Return(redactedResp, nil).Once()
+		mockClient.On("GetVariables", testAirflowURL).Return(redactedResp, nil).Once()
+		err := PullObjects(testAirflowURL, "airflow_settings.yaml", true, true, false, false, mockClient, out)
+		s.NoError(err)
+		mockClient.AssertExpectations(s.T())
+
+		content, err := os.ReadFile(filepath.Join(settings.WorkingPath, "airflow_settings.yaml"))
+		s.NoError(err)
+		s.Contains(string(content), "new-host")
+		s.Contains(string(content), encryptedPassword)
+		s.Contains(string(content), "api_key: local-key")
+		s.Contains(string(content), "timeout: 10")
+		s.Contains(string(content), "local-password")
+		s.NotContains(string(content), "***")
+	})
+
+	s.Run("error path when GetVariables returns an error", func() {
+		settings.WorkingPath = s.T().TempDir()
+		out := new(bytes.Buffer)
+		mockClient := new(airflowclient_mocks.Client)
+		mockClient.On("GetVariables", testAirflowURL).Return(airflowclient.Response{}, errTest).Once()
+		err := PullObjects(testAirflowURL, "airflow_settings.yaml", false, true, false, false, mockClient, out)
+		s.ErrorIs(err, errTest)
+		s.NoFileExists(filepath.Join(settings.WorkingPath, "airflow_settings.yaml"))
+	})
+}
@@ -0,0 +1,465 @@
+package fromfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	"github.com/astronomer/astro-cli/cloud/deployment"
+	"github.com/astronomer/astro-cli/cloud/deployment/inspect"
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/pkg/input"
+	"github.com/ghodss/yaml"
+)
+
+const (
+	noOpAction = "no-op"
+	// sensitiveValue replaces the values of secret environment variables in a plan
+	sensitiveValue = "(sensitive value)"
+)
+
+var (
+	errNoDeploymentFiles   = errors.New("no deployment files found, deployment files are YAML or JSON files")
+	errDuplicateDeployment = errors.New("is declared by more than one deployment file")
+
+	// Monkey patched to write unit tests
+	createOrUpdateFromFile = CreateOrUpdate
+	getFormattedDeployment = inspect.GetFormattedDeployment
+)
+
+// deploymentPlan is the change applying a deployment file makes to the Deployment it declares
+type deploymentPlan struct {
+	file          string
+	name          string
+	workspaceName string
+	action        string
+	changes       []fieldChange
+}
+
+// fieldChange is a change of a field of a Deployment. from is nil for an added field and to is nil for a removed one.
+// mayChange is set when the live value cannot be read back to tell whether the field changes.
+type fieldChange struct {
+	field     string
+	from      interface{}
+	to        interface{}
+	mayChange bool
+}
+
+// Apply reads the deployment files of a directory, or a single deployment file, and plans the changes bringing the
+// Deployments to the state the files declare: the Deployments that do not exist yet are created, and the others are
+// updated when their fields differ from the files. The plan is printed, then applied once confirmed.
+// Updating a Deployment replaces its description, environment variables, alert emails and hibernation schedules with
+// those of the file, so the plan reports them as removed when the file leaves them out. The other fields left empty
+// in a file keep their live values. The values of secret environment variables cannot be read back, so the secret
+// environment variables a file sets a value for are reported as changes that may not change them.
+func Apply(inputPath string, autoApprove, dryRun bool, astroPlatformCore astroplatformcore.CoreClient, coreClient astrocore.CoreClient, out io.Writer) error {
+	files, err := deploymentFiles(inputPath)
+	if err != nil {
+		return err
+	}
+	plans, err := planDeployments(files, astroPlatformCore, coreClient)
+	if err != nil {
+		return err
+	}
+	changeCount := printPlan(plans, out)
+	if changeCount == 0 {
+		fmt.Fprintln(out, "\nNo changes. The Deployments match the deployment files.")
+		return nil
+	}
+	if dryRun {
+		return nil
+	}
+	if !autoApprove {
+		fmt.Fprintln(out, "")
+		confirmed, _ := input.Confirm("Do you want to apply these changes?")
+		if !confirmed {
+			fmt.Fprintln(out, "Apply canceled")
+			return nil
+		}
+	}
+	for i := range plans {
+		if plans[i].action == noOpAction {
+			continue
+		}
+		err = createOrUpdateFromFile(plans[i].file, plans[i].action, astroPlatformCore, coreClient, io.Discard)
+		if err != nil {
+			return fmt.Errorf("failed to %s deployment %s from %s: %w", plans[i].action, plans[i].name, plans[i].file, err)
+		}
+		fmt.Fprintf(out, "Deployment %s %sd\n", plans[i].name, plans[i].action)
+	}
+	return nil
+}
+
+// deploymentFiles returns the YAML and JSON files of a directory, or the file itself when the path is a file
+func deploymentFiles(inputPath string) ([]string, error) {
+	info, err := os.Stat(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{inputPath}, nil
+	}
+	entries, err := os.ReadDir(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(inputPath, entry.Name()))
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s: %w", inputPath, errNoDeploymentFiles)
+	}
+	return files, nil
+}
+
+// planDeployments plans the change each deployment file makes against the live state of its Deployment
+func planDeployments(files []string, astroPlatformCore astroplatformcore.CoreClient, coreClient astrocore.CoreClient) ([]deploymentPlan, error) {
+	c, err := config.GetCurrentContext()
+	if err != nil {
+		return nil, err
+	}
+	workspaceIDs := map[string]string{}
+	workspaceDeployments := map[string][]astroplatformcore.Deployment{}
+	declaredIn := map[string]string{}
+	plans := make([]deploymentPlan, 0, len(files))
+	for _, file := range files {
+		var formattedDeployment inspect.FormattedDeployment
+		dataBytes, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if len(dataBytes) == 0 {
+			return nil, fmt.Errorf("%s %w", file, errEmptyFile)
+		}
+		err = yaml.Unmarshal(dataBytes, &formattedDeployment)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		name := formattedDeployment.Deployment.Configuration.Name
+		workspaceName := formattedDeployment.Deployment.Configuration.WorkspaceName
+
+		// a Deployment can only be declared once
+		key := workspaceName + "/" + name
+		if otherFile, ok := declaredIn[key]; ok {
+			return nil, fmt.Errorf("deployment: %s %w: %s and %s", name, errDuplicateDeployment, otherFile, file)
+		}
+		declaredIn[key] = file
+
+		workspaceID, ok := workspaceIDs[workspaceName]
+		if !ok {
+			workspaceID, err = getWorkspaceIDFromName(workspaceName, coreClient)
+			if err != nil {
+				return nil, err
+			}
+			if workspaceID == "" {
+				return nil, fmt.Errorf("%s: workspace: %s %w", file, workspaceName, errNotFound)
+			}
+			workspaceIDs[workspaceName] = workspaceID
+		}
+		existingDeployments, ok := workspaceDeployments[workspaceID]
+		if !ok {
+			existingDeployments, err = deployment.CoreGetDeployments(workspaceID, c.Organization, astroPlatformCore)
+			if err != nil {
+				return nil, err
+			}
+			workspaceDeployments[workspaceID] = existingDeployments
+		}
+
+		plan := deploymentPlan{file: file, name: name, workspaceName: workspaceName, action: createAction}
+		if deploymentExists(existingDeployments, name) {
+			plan.action = updateAction
+		}
+		err = checkRequiredFields(&formattedDeployment, plan.action)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if plan.action == updateAction {
+			existingDeployment, err := deploymentFromName(existingDeployments, name, astroPlatformCore)
+			if err != nil {
+				return nil, err
+			}
+			liveDeployment, err := getFormattedDeployment(&existingDeployment, astroPlatformCore)
+			if err != nil {
+				return nil, err
+			}
+			plan.changes = diffDeployments(&formattedDeployment, &liveDeployment)
+			if len(plan.changes) == 0 {
+				plan.action = noOpAction
+			}
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// printPlan prints the plans of the deployment files and returns the number of Deployments they change
+func printPlan(plans []deploymentPlan, out io.Writer) int {
+	var toCreate, toUpdate, unchanged int
+	for i := range plans {
+		plan := &plans[i]
+		switch plan.action {
+		case createAction:
+			toCreate++
+			fmt.Fprintf(out, "+ deployment %s in workspace %s will be created (%s)\n", plan.name, plan.workspaceName, plan.file)
+		case updateAction:
+			toUpdate++
+			fmt.Fprintf(out, "~ deployment %s in workspace %s will be updated (%s)\n", plan.name, plan.workspaceName, plan.file)
+			for _, change := range plan.changes {
+				fmt.Fprintln(out, "    "+change.String())
+			}
+		default:
+			unchanged++
+			fmt.Fprintf(out, "= deployment %s in workspace %s is up to date (%s)\n", plan.name, plan.workspaceName, plan.file)
+		}
+	}
+	fmt.Fprintf(out, "\nPlan: %d to create, %d to update, %d unchanged.\n", toCreate, toUpdate, unchanged)
+	return toCreate + toUpdate
+}
+
+func (c fieldChange) String() string {
+	switch {
+	case c.mayChange:
+		return fmt.Sprintf("~ %s: %s may change", c.field, formatPlanValue(c.to))
+	case c.from == nil:
+		return fmt.Sprintf("+ %s: %s", c.field, formatPlanValue(c.to))
+	case c.to == nil:
+		return fmt.Sprintf("- %s: %s", c.field, formatPlanValue(c.from))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.field, formatPlanValue(c.from), formatPlanValue(c.to))
+	}
+}
+
+func formatPlanValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if v == sensitiveValue {
+			return v
+		}
+		return fmt.Sprintf("%q", v)
+	case []string:
+		return "[" + strings.Join(v, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// diffDeployments returns the changes updating a live Deployment with a deployment file makes. The worker queues of a
+// Deployment are only changed when the file declares some, and its hibernation schedules are only sent for
+// development Deployments.
+func diffDeployments(fromFile, live *inspect.FormattedDeployment) []fieldChange {
+	fileConfig := fromFile.Deployment.Configuration
+	liveConfig := live.Deployment.Configuration
+	fileConfig.Executor = normalizeExecutor(fileConfig.Executor)
+	liveConfig.Executor = normalizeExecutor(liveConfig.Executor)
+	fileConfig.DeploymentType = string(transformDeploymentType(fileConfig.DeploymentType))
+	liveConfig.DeploymentType = string(transformDeploymentType(liveConfig.DeploymentType))
+	// the scheduler size and the cloud provider of a deployment file are matched regardless of their case
+	fileConfig.SchedulerSize = matchCase(fileConfig.SchedulerSize, liveConfig.SchedulerSize)
+	fileConfig.CloudProvider = matchCase(fileConfig.CloudProvider, liveConfig.CloudProvider)
+	changes := diffFields("configuration", fileConfig, liveConfig)
+	// the description is the only field of the configuration an update clears when the file leaves it empty
+	if fileConfig.Description == "" && liveConfig.Description != "" {
+		changes = append(changes, fieldChange{field: "configuration.description", from: liveConfig.Description})
+	}
+
+	if hasQueues(fromFile) {
+		deploymentType := astroplatformcore.DeploymentType(fileConfig.DeploymentType)
+		// the worker type of a queue is an Astro Machine, matched regardless of its case, except on Hybrid Deployments
+		astroMachines := deployment.IsDeploymentStandard(deploymentType) || deployment.IsDeploymentDedicated(deploymentType)
+		changes = append(changes, diffWorkerQueues(fromFile.Deployment.WorkerQs, live.Deployment.WorkerQs, astroMachines)...)
+	}
+	changes = append(changes, diffEnvVars(fromFile.Deployment.EnvVars, live.Deployment.EnvVars)...)
+	fileEmails := sortedCopy(fromFile.Deployment.AlertEmails)
+	liveEmails := sortedCopy(live.Deployment.AlertEmails)
+	switch {
+	case reflect.DeepEqual(fileEmails, liveEmails):
+	case len(fileEmails) == 0:
+		changes = append(changes, fieldChange{field: "alert_emails", from: liveEmails})
+	default:
+		changes = append(changes, fieldChange{field: "alert_emails", from: liveEmails, to: fileEmails})
+	}
+	if liveConfig.IsDevelopmentMode || len(fromFile.Deployment.HibernationSchedules) > 0 {
+		changes = append(changes, diffHibernationSchedules(fromFile.Deployment.HibernationSchedules, live.Deployment.HibernationSchedules)...)
+	}
+	return changes
+}
+
+// diffFields compares the fields of two structs of a deployment file, keyed by their names in the file. The fields
+// left empty in the file and the fields the live Deployment does not have are not compared.
+func diffFields(prefix string, fromFile, live interface{}) []fieldChange {
+	fileFields := planFields(fromFile)
+	liveFields := planFields(live)
+	keys := make([]string, 0, len(fileFields))
+	for key := range fileFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	changes := []fieldChange{}
+	for _, key := range keys {
+		fileValue := fileFields[key]
+		if fileValue == "" || fileValue == float64(0) {
+			continue
+		}
+		liveValue, ok := liveFields[key]
+		if !ok || reflect.DeepEqual(fileValue, liveValue) {
+			continue
+		}
+		changes = append(changes, fieldChange{field: prefix + "." + key, from: liveValue, to: fileValue})
+	}
+	return changes
+}
+
+// planFields returns the fields of a struct of a deployment file keyed by their names in the file
+func planFields(value interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+func diffWorkerQueues(fileQueues, liveQueues []inspect.Workerq, astroMachines bool) []fieldChange {
+	liveByName := map[string]inspect.Workerq{}
+	for _, queue := range liveQueues {
+		liveByName[queue.Name] = queue
+	}
+	changes := []fieldChange{}
+	fileNames := map[string]bool{}
+	for _, queue := range fileQueues {
+		fileNames[queue.Name] = true
+		field := "worker_queues." + queue.Name
+		liveQueue, ok := liveByName[queue.Name]
+		if !ok {
+			changes = append(changes, fieldChange{field: field, to: "worker_type " + queue.WorkerType})
+			continue
+		}
+		if astroMachines {
+			queue.WorkerType = matchCase(queue.WorkerType, liveQueue.WorkerType)
+		}
+		changes = append(changes, diffFields(field, queue, liveQueue)...)
+	}
+	for _, queue := range liveQueues {
+		if !fileNames[queue.Name] {
+			changes = append(changes, fieldChange{field: "worker_queues." + queue.Name, from: "worker_type " + queue.WorkerType})
+		}
+	}
+	return changes
+}
+
+func diffEnvVars(fileVars, liveVars []inspect.EnvironmentVariable) []fieldChange {
+	liveByKey := map[string]inspect.EnvironmentVariable{}
+	for _, envVar := range liveVars {
+		liveByKey[envVar.Key] = envVar
+	}
+	changes := []fieldChange{}
+	fileKeys := map[string]bool{}
+	for _, envVar := range fileVars {
+		fileKeys[envVar.Key] = true
+		field := "environment_variables." + envVar.Key
+		liveVar, ok := liveByKey[envVar.Key]
+		switch {
+		case !ok:
+			changes = append(changes, fieldChange{field: field, to: envVarValue(envVar)})
+		case envVar.IsSecret != liveVar.IsSecret:
+			changes = append(changes, fieldChange{field: field + ".is_secret", from: liveVar.IsSecret, to: envVar.IsSecret})
+		case !envVar.IsSecret && envVarValue(envVar) != envVarValue(liveVar):
+			changes = append(changes, fieldChange{field: field, from: envVarValue(liveVar), to: envVarValue(envVar)})
+		case envVar.IsSecret && envVar.Value != nil:
+			// the live value of a secret cannot be read back, an update without a value keeps it
+			changes = append(changes, fieldChange{field: field, from: envVarValue(liveVar), to: envVarValue(envVar), mayChange: true})
+		}
+	}
+	for _, envVar := range liveVars {
+		if !fileKeys[envVar.Key] {
+			changes = append(changes, fieldChange{field: "environment_variables." + envVar.Key, from: envVarValue(envVar)})
+		}
+	}
+	return changes
+}
+
+func envVarValue(envVar inspect.EnvironmentVariable) string {
+	if envVar.IsSecret {
+		return sensitiveValue
+	}
+	if envVar.Value == nil {
+		return ""
+	}
+	return *envVar.Value
+}
+
+func diffHibernationSchedules(fileSchedules, liveSchedules []inspect.HibernationSchedule) []fieldChange {
+	fileSet := map[string]bool{}
+	for _, schedule := range fileSchedules {
+		fileSet[hibernationScheduleString(schedule)] = true
+	}
+	liveSet := map[string]bool{}
+	for _, schedule := range liveSchedules {
+		liveSet[hibernationScheduleString(schedule)] = true
+	}
+	changes := []fieldChange{}
+	for _, schedule := range liveSchedules {
+		if s := hibernationScheduleString(schedule); !fileSet[s] {
+			changes = append(changes, fieldChange{field: "hibernation_schedules", from: s})
+		}
+	}
+	for _, schedule := range fileSchedules {
+		if s := hibernationScheduleString(schedule); !liveSet[s] {
+			changes = append(changes, fieldChange{field: "hibernation_schedules", to: s})
+		}
+	}
+	return changes
+}
+
+func hibernationScheduleString(schedule inspect.HibernationSchedule) string {
+	s := fmt.Sprintf("hibernate at %s, wake at %s", schedule.HibernateAt, schedule.WakeAt)
+	if schedule.Description != "" {
+		s += " (" + schedule.Description + ")"
+	}
+	if !schedule.Enabled {
+		s += ", disabled"
+	}
+	return s
+}
+
+// normalizeExecutor returns the executor of a deployment file as the API names it
+func normalizeExecutor(executor string) string {
+	switch {
+	case strings.EqualFold(executor, deployment.CeleryExecutor):
+		return deployment.CELERY
+	case strings.EqualFold(executor, deployment.KubeExecutor):
+		return deployment.KUBERNETES
+	}
+	return strings.ToUpper(executor)
+}
+
+// matchCase returns the live value of a field matched regardless of its case when the value of the file only differs
+// from it by its case, so that it is not reported as changed
+func matchCase(fileValue, liveValue string) string {
+	if strings.EqualFold(fileValue, liveValue) {
+		return liveValue
+	}
+	return fileValue
+}
+
+func sortedCopy(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
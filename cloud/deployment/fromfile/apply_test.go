@@ -0,0 +1,294 @@
+package fromfile
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	astrocore_mocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	"github.com/astronomer/astro-cli/cloud/deployment/inspect"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	applyUpdateFile = `deployment:
+  configuration:
+    name: test-deployment-label
+    workspace_name: test-workspace
+    executor: CeleryExecutor
+    scheduler_size: medium
+  alert_emails:
+    - test@test.com
+`
+	applyCreateFile = `deployment:
+  configuration:
+    name: new-deployment
+    workspace_name: test-workspace
+    executor: CeleryExecutor
+`
+)
+
+// liveTestDeployment is the live state of the deployment of applyUpdateFile, before it is applied
+func liveTestDeployment() inspect.FormattedDeployment {
+	var live inspect.FormattedDeployment
+	live.Deployment.Configuration.Name = "test-deployment-label"
+	live.Deployment.Configuration.WorkspaceName = "test-workspace"
+	live.Deployment.Configuration.Executor = "CELERY"
+	live.Deployment.Configuration.SchedulerSize = "small"
+	live.Deployment.AlertEmails = []string{"test@test.com"}
+	return live
+}
+
+func (s *Suite) writeApplyFiles(files map[string]string) string {
+	dir := s.T().TempDir()
+	for name, data := range files {
+		s.NoError(os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600))
+	}
+	return dir
+}
+
+func (s *Suite) TestApply() {
+	testUtil.InitTestConfig(testUtil.CloudPlatform)
+	var applied []string
+	createOrUpdateFromFile = func(inputFile, action string, astroPlatformCore astroplatformcore.CoreClient, coreClient astrocore.CoreClient, out io.Writer) error {
+		applied = append(applied, action+" "+filepath.Base(inputFile))
+		return nil
+	}
+	live := liveTestDeployment()
+	getFormattedDeployment = func(depl *astroplatformcore.Deployment, platformCoreClient astroplatformcore.CoreClient) (inspect.FormattedDeployment, error) {
+		s.Equal("test-deployment-id", depl.Id)
+		return live, nil
+	}
+	defer func() {
+		createOrUpdateFromFile = CreateOrUpdate
+		getFormattedDeployment = inspect.GetFormattedDeployment
+	}()
+	mockLiveState := func() *astrocore_mocks.ClientWithResponsesInterface {
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListWorkspacesWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&ListWorkspacesResponseOK, nil).Once()
+		mockPlatformCoreClient.On("ListDeploymentsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&mockListDeploymentsCreateResponse, nil).Once()
+		return mockCoreClient
+	}
+
+	s.Run("prints the plan and applies it once confirmed", func() {
+		applied = nil
+		dir := s.writeApplyFiles(map[string]string{"a.yaml": applyUpdateFile, "b.yml": applyCreateFile, "README.md": "not a deployment file"})
+		mockCoreClient := mockLiveState()
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Once()
+		defer testUtil.MockUserInput(s.T(), "y")()
+
+		out := new(bytes.Buffer)
+		err := Apply(dir, false, false, mockPlatformCoreClient, mockCoreClient, out)
+		s.NoError(err)
+		s.Contains(out.String(), "~ deployment test-deployment-label in workspace test-workspace will be updated")
+		s.Contains(out.String(), `    ~ configuration.scheduler_size: "small" -> "medium"`)
+		s.Contains(out.String(), "+ deployment new-deployment in workspace test-workspace will be created")
+		s.Contains(out.String(), "Plan: 1 to create, 1 to update, 0 unchanged.")
+		s.Contains(out.String(), "Deployment new-deployment created")
+		s.Equal([]string{"update a.yaml", "create b.yml"}, applied)
+		mockCoreClient.AssertExpectations(s.T())
+	})
+
+	s.Run("does not apply a dry run", func() {
+		applied = nil
+		dir := s.writeApplyFiles(map[string]string{"b.yaml": applyCreateFile})
+		mockCoreClient := mockLiveState()
+
+		out := new(bytes.Buffer)
+		err := Apply(dir, false, true, mockPlatformCoreClient, mockCoreClient, out)
+		s.NoError(err)
+		s.Contains(out.String(), "Plan: 1 to create, 0 to update, 0 unchanged.")
+		s.Empty(applied)
+	})
+
+	s.Run("does not apply a canceled plan", func() {
+		applied = nil
+		dir := s.writeApplyFiles(map[string]string{"b.yaml": applyCreateFile})
+		mockCoreClient := mockLiveState()
+		defer testUtil.MockUserInput(s.T(), "n")()
+
+		out := new(bytes.Buffer)
+		err := Apply(dir, false, false, mockPlatformCoreClient, mockCoreClient, out)
+		s.NoError(err)
+		s.Contains(out.String(), "Apply canceled")
+		s.Empty(applied)
+	})
+
+	s.Run("has nothing to apply when the Deployments match the files", func() {
+		applied = nil
+		live.Deployment.Configuration.SchedulerSize = "medium"
+		defer func() { live.Deployment.Configuration.SchedulerSize = "small" }()
+		dir := s.writeApplyFiles(map[string]string{"a.json": applyUpdateFile})
+		mockCoreClient := mockLiveState()
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Once()
+
+		out := new(bytes.Buffer)
+		err := Apply(filepath.Join(dir, "a.json"), true, false, mockPlatformCoreClient, mockCoreClient, out)
+		s.NoError(err)
+		s.Contains(out.String(), "= deployment test-deployment-label in workspace test-workspace is up to date")
+		s.Contains(out.String(), "No changes.")
+		s.Empty(applied)
+	})
+
+	s.Run("removes the environment variables the file leaves out", func() {
+		applied = nil
+		value := "value"
+		live.Deployment.Configuration.SchedulerSize = "medium"
+		live.Deployment.EnvVars = []inspect.EnvironmentVariable{{Key: "KEY", Value: &value}, {Key: "SECRET", IsSecret: true}}
+		defer func() {
+			live.Deployment.Configuration.SchedulerSize = "small"
+			live.Deployment.EnvVars = nil
+		}()
+		dir := s.writeApplyFiles(map[string]string{"a.yaml": applyUpdateFile})
+		mockCoreClient := mockLiveState()
+		mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Once()
+
+		out := new(bytes.Buffer)
+		err := Apply(dir, true, false, mockPlatformCoreClient, mockCoreClient, out)
+		s.NoError(err)
+		s.Contains(out.String(), "~ deployment test-deployment-label in workspace test-workspace will be updated")
+		s.Contains(out.String(), `    - environment_variables.KEY: "value"`)
+		s.Contains(out.String(), "    - environment_variables.SECRET: (sensitive value)")
+		s.Equal([]string{"update a.yaml"}, applied)
+	})
+
+	s.Run("returns an error if a Deployment is declared twice", func() {
+		dir := s.writeApplyFiles(map[string]string{"b.yaml": applyCreateFile, "c.yaml": applyCreateFile})
+		mockCoreClient := mockLiveState()
+
+		err := Apply(dir, true, false, mockPlatformCoreClient, mockCoreClient, io.Discard)
+		s.ErrorIs(err, errDuplicateDeployment)
+	})
+
+	s.Run("returns an error if the directory has no deployment files", func() {
+		err := Apply(s.T().TempDir(), true, false, mockPlatformCoreClient, nil, io.Discard)
+		s.ErrorIs(err, errNoDeploymentFiles)
+	})
+
+	s.Run("returns an error if the workspace does not exist", func() {
+		dir := s.writeApplyFiles(map[string]string{"b.yaml": applyCreateFile})
+		mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockCoreClient.On("ListWorkspacesWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&EmptyListWorkspacesResponseOK, nil).Once()
+
+		err := Apply(dir, true, false, mockPlatformCoreClient, mockCoreClient, io.Discard)
+		s.ErrorIs(err, errNotFound)
+	})
+}
+
+func (s *Suite) TestDiffDeployments() {
+	value1, value2 := "value-1", "value-2"
+	live := liveTestDeployment()
+	live.Deployment.Configuration.DeploymentType = "STANDARD"
+	live.Deployment.WorkerQs = []inspect.Workerq{
+		{Name: "default", WorkerType: "A5", MinWorkerCount: 1, MaxWorkerCount: 10},
+		{Name: "old", WorkerType: "A10"},
+	}
+	live.Deployment.EnvVars = []inspect.EnvironmentVariable{
+		{Key: "CHANGED", Value: &value1},
+		{Key: "SECRET", IsSecret: true},
+		{Key: "REMOVED", Value: &value1},
+	}
+	live.Deployment.HibernationSchedules = []inspect.HibernationSchedule{{HibernateAt: "0 18 * * *", WakeAt: "0 8 * * *", Enabled: true}}
+
+	s.Run("compares the fields the file sets and removes the sections it leaves out", func() {
+		live := live
+		live.Deployment.Configuration.Description = "test description"
+		live.Deployment.Configuration.DefaultTaskPodCPU = "0.25"
+		fromFile := liveTestDeployment()
+		fromFile.Deployment.Configuration.Executor = "CeleryExecutor"
+		fromFile.Deployment.Configuration.DeploymentType = HostedStandard
+		fromFile.Deployment.Configuration.SchedulerSize = "medium"
+		fromFile.Deployment.Configuration.APIKeyOnlyDeployments = true
+		fromFile.Deployment.AlertEmails = nil
+
+		changes := diffDeployments(&fromFile, &live)
+		s.Equal([]fieldChange{
+			{field: "configuration.ci_cd_enforcement", from: false, to: true},
+			{field: "configuration.scheduler_size", from: "small", to: "medium"},
+			{field: "configuration.description", from: "test description"},
+			{field: "environment_variables.CHANGED", from: "value-1"},
+			{field: "environment_variables.SECRET", from: sensitiveValue},
+			{field: "environment_variables.REMOVED", from: "value-1"},
+			{field: "alert_emails", from: []string{"test@test.com"}},
+		}, changes)
+	})
+
+	s.Run("removes the hibernation schedules the file leaves out of a development Deployment", func() {
+		live := live
+		live.Deployment.Configuration.IsDevelopmentMode = true
+		live.Deployment.EnvVars = nil
+		fromFile := liveTestDeployment()
+		fromFile.Deployment.Configuration.IsDevelopmentMode = true
+
+		changes := diffDeployments(&fromFile, &live)
+		s.Equal([]fieldChange{
+			{field: "hibernation_schedules", from: "hibernate at 0 18 * * *, wake at 0 8 * * *"},
+		}, changes)
+	})
+
+	s.Run("keeps the secret environment variables the file sets no value for", func() {
+		live := live
+		live.Deployment.EnvVars = []inspect.EnvironmentVariable{{Key: "SECRET", IsSecret: true}}
+		fromFile := liveTestDeployment()
+		fromFile.Deployment.EnvVars = []inspect.EnvironmentVariable{{Key: "SECRET", IsSecret: true}}
+
+		changes := diffDeployments(&fromFile, &live)
+		s.Empty(changes)
+	})
+
+	s.Run("compares the enum-like fields regardless of their case", func() {
+		live := live
+		live.Deployment.Configuration.CloudProvider = "AWS"
+		live.Deployment.EnvVars = nil
+		live.Deployment.HibernationSchedules = nil
+		fromFile := liveTestDeployment()
+		fromFile.Deployment.Configuration.Executor = "celery"
+		fromFile.Deployment.Configuration.DeploymentType = "standard"
+		fromFile.Deployment.Configuration.SchedulerSize = "SMALL"
+		fromFile.Deployment.Configuration.CloudProvider = "aws"
+		fromFile.Deployment.WorkerQs = []inspect.Workerq{
+			{Name: "default", WorkerType: "a5", MinWorkerCount: 1, MaxWorkerCount: 10},
+			{Name: "old", WorkerType: "a10"},
+		}
+
+		changes := diffDeployments(&fromFile, &live)
+		s.Empty(changes)
+	})
+
+	s.Run("compares worker queues, environment variables, alert emails and hibernation schedules", func() {
+		fromFile := liveTestDeployment()
+		fromFile.Deployment.WorkerQs = []inspect.Workerq{
+			{Name: "default", WorkerType: "A5", MinWorkerCount: 1, MaxWorkerCount: 20},
+			{Name: "new", WorkerType: "A20"},
+		}
+		fromFile.Deployment.EnvVars = []inspect.EnvironmentVariable{
+			{Key: "CHANGED", Value: &value2},
+			{Key: "SECRET", IsSecret: true, Value: &value2},
+			{Key: "ADDED", IsSecret: true, Value: &value2},
+		}
+		fromFile.Deployment.AlertEmails = []string{"test@test.com", "new@test.com"}
+		fromFile.Deployment.HibernationSchedules = []inspect.HibernationSchedule{{HibernateAt: "0 20 * * *", WakeAt: "0 8 * * *", Description: "nights"}}
+
+		changes := diffDeployments(&fromFile, &live)
+		lines := make([]string, 0, len(changes))
+		for _, change := range changes {
+			lines = append(lines, change.String())
+		}
+		s.Equal([]string{
+			"~ worker_queues.default.max_worker_count: 10 -> 20",
+			`+ worker_queues.new: "worker_type A20"`,
+			`- worker_queues.old: "worker_type A10"`,
+			`~ environment_variables.CHANGED: "value-1" -> "value-2"`,
+			"~ environment_variables.SECRET: (sensitive value) may change",
+			"+ environment_variables.ADDED: (sensitive value)",
+			`- environment_variables.REMOVED: "value-1"`,
+			"~ alert_emails: [test@test.com] -> [new@test.com, test@test.com]",
+			`- hibernation_schedules: "hibernate at 0 18 * * *, wake at 0 8 * * *"`,
+			`+ hibernation_schedules: "hibernate at 0 20 * * *, wake at 0 8 * * * (nights), disabled"`,
+		}, lines)
+	})
+}
@@ -114,10 +114,10 @@ const (
 
 func Inspect(wsID, deploymentName, deploymentID, outputFormat string, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient, out io.Writer, requestedField string, template, showWorkloadIdentity bool) error {
 	var (
-		requestedDeployment                                                        astroplatformcore.Deployment
-		err                                                                        error
-		infoToPrint                                                                []byte
-		deploymentInfoMap, deploymentConfigMap, additionalMap, printableDeployment map[string]interface{}
+		requestedDeployment astroplatformcore.Deployment
+		err                 error
+		infoToPrint         []byte
+		printableDeployment map[string]interface{}
 	)
 	// get or select the deployment
 	requestedDeployment, err = deployment.GetDeployment(wsID, deploymentID, deploymentName, true, nil, platformCoreClient, coreClient)
@@ -129,30 +129,11 @@ func Inspect(wsID, deploymentName, deploymentID, outputFormat string, platformCo
 		fmt.Printf("%s %s\n", deployment.NoDeploymentInWSMsg, ansi.Bold(wsID))
 		return nil
 	}
-	// create a map for deployment.information
-	deploymentInfoMap, err = getDeploymentInfo(requestedDeployment)
-	if err != nil {
-		return err
-	}
-	// create a map for deployment.configuration
-	deploymentConfigMap, err = getDeploymentConfig(&requestedDeployment, platformCoreClient, showWorkloadIdentity)
+	// create a map for the entire deployment
+	printableDeployment, err = getPrintableDeploymentFor(&requestedDeployment, platformCoreClient, showWorkloadIdentity)
 	if err != nil {
 		return err
 	}
-	// create a map for deployment.alert_emails, deployment.worker_queues and deployment.astronomer_variables
-	nodePools := []astroplatformcore.NodePool{}
-	if requestedDeployment.ClusterId != nil {
-		cluster, err := deployment.CoreGetCluster("", *requestedDeployment.ClusterId, platformCoreClient)
-		if err != nil {
-			return err
-		}
-		if cluster.NodePools != nil {
-			nodePools = *cluster.NodePools
-		}
-	}
-	additionalMap = getAdditionalNullableFields(&requestedDeployment, nodePools)
-	// create a map for the entire deployment
-	printableDeployment = getPrintableDeployment(deploymentInfoMap, deploymentConfigMap, additionalMap)
 	// get specific field if requested
 	if requestedField != "" {
 		value, err := getSpecificField(printableDeployment, requestedField)
@@ -294,20 +275,46 @@ func getAdditionalNullableFields(coreDeployment *astroplatformcore.Deployment, n
 func ReturnSpecifiedValue(depl *astroplatformcore.Deployment, requestedField string, astroPlatformCore astroplatformcore.CoreClient) (value any, err error) {
 	showWorkloadIdentity := strings.Contains(requestedField, "workload_identity") // if the caller has requested for workload_identity, we set the flag to true to fetch the deployment workload_identity
 
+	// create a map for the entire deployment
+	printableDeployment, err := getPrintableDeploymentFor(depl, astroPlatformCore, showWorkloadIdentity)
+	if err != nil {
+		return nil, err
+	}
+	value, err = getSpecificField(printableDeployment, requestedField)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// GetFormattedDeployment returns a deployment in the format of the deployment files, as printed by Inspect
+func GetFormattedDeployment(depl *astroplatformcore.Deployment, platformCoreClient astroplatformcore.CoreClient) (FormattedDeployment, error) {
+	var formattedDeployment FormattedDeployment
+	printableDeployment, err := getPrintableDeploymentFor(depl, platformCoreClient, true)
+	if err != nil {
+		return formattedDeployment, err
+	}
+	err = decodeToStruct(printableDeployment, &formattedDeployment)
+	return formattedDeployment, err
+}
+
+// getPrintableDeploymentFor creates the map of a deployment with its information, configuration, alert emails,
+// worker queues, environment variables and hibernation schedules
+func getPrintableDeploymentFor(depl *astroplatformcore.Deployment, platformCoreClient astroplatformcore.CoreClient, showWorkloadIdentity bool) (map[string]interface{}, error) {
 	// create a map for deployment.information
 	deploymentInfoMap, err := getDeploymentInfo(*depl)
 	if err != nil {
 		return nil, err
 	}
 	// create a map for deployment.configuration
-	deploymentConfigMap, err := getDeploymentConfig(depl, astroPlatformCore, showWorkloadIdentity)
+	deploymentConfigMap, err := getDeploymentConfig(depl, platformCoreClient, showWorkloadIdentity)
 	if err != nil {
 		return nil, err
 	}
-	nodePools := []astroplatformcore.NodePool{}
 	// create a map for deployment.alert_emails, deployment.worker_queues and deployment.environment_variables
+	nodePools := []astroplatformcore.NodePool{}
 	if depl.ClusterId != nil {
-		cluster, err := deployment.CoreGetCluster("", *depl.ClusterId, astroPlatformCore)
+		cluster, err := deployment.CoreGetCluster("", *depl.ClusterId, platformCoreClient)
 		if err != nil {
 			return nil, err
 		}
@@ -316,13 +323,7 @@ func ReturnSpecifiedValue(depl *astroplatformcore.Deployment, requestedField str
 		}
 	}
 	additionalMap := getAdditionalNullableFields(depl, nodePools)
-	// create a map for the entire deployment
-	printableDeployment := getPrintableDeployment(deploymentInfoMap, deploymentConfigMap, additionalMap)
-	value, err = getSpecificField(printableDeployment, requestedField)
-	if err != nil {
-		return nil, err
-	}
-	return value, nil
+	return getPrintableDeployment(deploymentInfoMap, deploymentConfigMap, additionalMap), nil
 }
 
 func getQMap(coreDeploymentPointer *astroplatformcore.Deployment, sourceNodePools []astroplatformcore.NodePool) []map[string]interface{} {
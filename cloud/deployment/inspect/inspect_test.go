@@ -1392,3 +1392,25 @@ func TestReturnSpecifiedValue(t *testing.T) {
 		mockPlatformCoreClient.AssertExpectations(t)
 	})
 }
+
+func TestGetFormattedDeployment(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+
+	t.Run("returns the deployment in the format of the deployment files", func(t *testing.T) {
+		mockPlatformCoreClient.On("GetClusterWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&mockGetClusterResponse, nil).Once()
+
+		formattedDeployment, err := GetFormattedDeployment(&sourceDeployment, mockPlatformCoreClient)
+		assert.NoError(t, err)
+		assert.Equal(t, sourceDeployment.Name, formattedDeployment.Deployment.Configuration.Name)
+		assert.Equal(t, *sourceDeployment.WorkspaceName, formattedDeployment.Deployment.Configuration.WorkspaceName)
+		assert.Equal(t, *sourceDeployment.ContactEmails, formattedDeployment.Deployment.AlertEmails)
+		mockPlatformCoreClient.AssertExpectations(t)
+	})
+	t.Run("get cluster error", func(t *testing.T) {
+		mockPlatformCoreClient.On("GetClusterWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("test cluster error")).Once()
+
+		_, err := GetFormattedDeployment(&sourceDeployment, mockPlatformCoreClient)
+		assert.ErrorContains(t, err, "test cluster error")
+		mockPlatformCoreClient.AssertExpectations(t)
+	})
+}
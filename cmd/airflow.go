@@ -10,10 +10,12 @@ import (
 	"time"
 
 	"github.com/astronomer/astro-cli/airflow"
+	airflowclient "github.com/astronomer/astro-cli/airflow-client"
 	"github.com/astronomer/astro-cli/airflow/runtimes"
 	airflowversions "github.com/astronomer/astro-cli/airflow_versions"
 	astrocore "github.com/astronomer/astro-cli/astro-client-core"
 	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	"github.com/astronomer/astro-cli/cloud/deployment"
 	"github.com/astronomer/astro-cli/cloud/environment"
 	"github.com/astronomer/astro-cli/cmd/utils"
 	"github.com/astronomer/astro-cli/config"
@@ -24,6 +26,7 @@ import (
 	"github.com/astronomer/astro-cli/pkg/httputil"
 	"github.com/astronomer/astro-cli/pkg/input"
 	"github.com/astronomer/astro-cli/pkg/util"
+	"github.com/astronomer/astro-cli/settings"
 	"github.com/iancoleman/strcase"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -63,6 +66,12 @@ var (
 	pools                  bool
 	envExport              bool
 	noBrowser              bool
+	watchProject           bool
+	psOutputFormat         string
+	pruneObjects           bool
+	redactSecrets          bool
+	executor               string
+	fromDeploymentFile     string
 	compose                bool
 	versionTest            bool
 	dagTest                bool
@@ -114,18 +123,25 @@ astro dev init --from-template
 	containerHandlerInit = airflow.ContainerHandlerInit
 	getDefaultImageTag   = airflowversions.GetDefaultImageTag
 	projectNameUnique    = airflow.ProjectNameUnique
+	encryptSettingsFile  = settings.EncryptSettingsFile
+	validateSettingsFile = settings.ValidateSettingsFile
+	getDeployment        = deployment.GetDeployment
+	pullObjects          = deployment.PullObjects
+
+	configureFromDeploymentFile = airflow.ConfigureFromDeploymentFile
 
 	pytestDir = "/tests"
 
 	errPytestArgs               = errors.New("you can only pass one pytest file or directory")
 	buildSecrets                = []string{}
 	errNoCompose                = errors.New("cannot use '--compose-file' without '--compose' flag")
+	errPullNotOnAstro           = errors.New("pulling objects from a Deployment is only supported on Astro, switch to an Astro context with 'astro context switch'")
 	TemplateList                = airflow.FetchTemplateList
 	defaultWaitTime             = 1 * time.Minute
 	directoryPermissions uint32 = 0o755
 )
 
-func newDevRootCmd(platformCoreClient astroplatformcore.CoreClient, astroCoreClient astrocore.CoreClient) *cobra.Command {
+func newDevRootCmd(platformCoreClient astroplatformcore.CoreClient, astroCoreClient astrocore.CoreClient, airflowAPIClient airflowclient.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "dev",
 		Aliases: []string{"d"},
@@ -145,6 +161,7 @@ func newDevRootCmd(platformCoreClient astroplatformcore.CoreClient, astroCoreCli
 		newAirflowStartCmd(astroCoreClient),
 		newAirflowRunCmd(),
 		newAirflowPSCmd(),
+		newAirflowListCmd(),
 		newAirflowLogsCmd(),
 		newAirflowStopCmd(),
 		newAirflowKillCmd(),
@@ -152,7 +169,8 @@ func newDevRootCmd(platformCoreClient astroplatformcore.CoreClient, astroCoreCli
 		newAirflowParseCmd(),
 		newAirflowRestartCmd(astroCoreClient),
 		newAirflowBashCmd(),
-		newAirflowObjectRootCmd(),
+		newAirflowObjectRootCmd(platformCoreClient, airflowAPIClient),
+		newAirflowSnapshotRootCmd(),
 		newAirflowUpgradeTestCmd(platformCoreClient),
 	)
 	return cmd
@@ -259,6 +277,9 @@ func newAirflowStartCmd(astroCoreClient astrocore.CoreClient) *cobra.Command {
 	cmd.Flags().DurationVar(&waitTime, "wait", defaultWaitTime, "Duration to wait for webserver to get healthy. The default is 5 minutes. Use --wait 2m to wait for 2 minutes.")
 	cmd.Flags().StringVarP(&composeFile, "compose-file", "", "", "Location of a custom compose file to use for starting Airflow")
 	cmd.Flags().StringSliceVar(&buildSecrets, "build-secrets", []string{}, "Mimics docker build --secret flag. See https://docs.docker.com/build/building/secrets/ for more information. Example input id=mysecret,src=secrets.txt")
+	cmd.Flags().BoolVarP(&watchProject, "watch", "", false, "Watch the Dockerfile, requirements.txt and packages.txt for changes, and rebuild the image and recreate the Airflow containers when they change. The metadata database is kept intact.")
+	cmd.Flags().StringVarP(&executor, "executor", "", "", "The executor of the local Airflow environment, one of local, celery or kubernetes. The celery executor runs a worker for each queue set with 'astro config set dev.celery_queues'. The kubernetes executor runs each task in a pod of a local kind or k3d cluster, set with 'astro config set dev.kubernetes_provider'. The executor is saved to the project config and used until it is changed.")
+	cmd.Flags().StringVarP(&fromDeploymentFile, "from-deployment-file", "", "", "Configure the local Airflow environment from a deployment file used by 'astro deployment create --deployment-file'. The environment variables, the executor, the worker queues and the scheduler count of the file are used and the runtime version is checked against the Dockerfile. Variables of the env file override the ones of the deployment file.")
 	if !config.CFG.DisableEnvObjects.GetBool() {
 		cmd.Flags().StringVarP(&workspaceID, "workspace-id", "w", "", "ID of the Workspace to retrieve environment connections from. If not specified uses the current Workspace.")
 		cmd.Flags().StringVarP(&deploymentID, "deployment-id", "d", "", "ID of the Deployment to retrieve environment connections from")
@@ -275,6 +296,20 @@ func newAirflowPSCmd() *cobra.Command {
 		PreRunE: SetRuntimeIfExists,
 		RunE:    airflowPS,
 	}
+	cmd.Flags().StringVarP(&psOutputFormat, "output", "o", "table", "Output format can be one of: table, json or yaml. The json and yaml formats include the health of the local Airflow environment.")
+	return cmd
+}
+
+func newAirflowListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List all local Airflow environments",
+		Long:    "List the local Airflow environments of all Astro projects on this machine, along with their state and the URLs of their Airflow UI and Postgres database.",
+		Args:    cobra.NoArgs,
+		PreRunE: SetRuntime,
+		RunE:    airflowList,
+	}
 	return cmd
 }
 
@@ -405,7 +440,7 @@ func newAirflowBashCmd() *cobra.Command {
 	return cmd
 }
 
-func newAirflowObjectRootCmd() *cobra.Command {
+func newAirflowObjectRootCmd(platformCoreClient astroplatformcore.CoreClient, airflowAPIClient airflowclient.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "object",
 		Aliases: []string{"obj", "objects"},
@@ -415,6 +450,9 @@ func newAirflowObjectRootCmd() *cobra.Command {
 	cmd.AddCommand(
 		newObjectImportCmd(),
 		newObjectExportCmd(),
+		newObjectEncryptCmd(),
+		newObjectValidateCmd(),
+		newObjectPullCmd(platformCoreClient, airflowAPIClient),
 	)
 	return cmd
 }
@@ -431,6 +469,7 @@ func newObjectImportCmd() *cobra.Command {
 	cmd.Flags().BoolVarP(&variables, "variables", "v", false, "Import variables from a settings YAML file")
 	cmd.Flags().BoolVarP(&pools, "pools", "p", false, "Import pools from a settings YAML file")
 	cmd.Flags().StringVarP(&settingsFile, "settings-file", "s", "airflow_settings.yaml", "The settings YAML file from which to import Airflow objects. Default is 'airflow_settings.yaml'")
+	cmd.Flags().BoolVarP(&pruneObjects, "prune", "", false, "Remove the Airflow objects which are not in the settings YAML file. Only the object types being imported are pruned, and the default pool is never removed")
 	return cmd
 }
 
@@ -454,6 +493,101 @@ func newObjectExportCmd() *cobra.Command {
 	return cmd
 }
 
+func newObjectEncryptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "encrypt",
+		Short:   "Encrypt the secrets of your Airflow settings file",
+		Long:    "Encrypt the connection passwords, connection URIs and variable values of your Airflow settings file so it can be committed safely. The values are encrypted with the settings.encryption_key of your global config, which is generated if it is not set yet, and are decrypted when the objects are imported into Airflow.",
+		PreRunE: utils.EnsureProjectDir,
+		RunE:    airflowSettingsEncrypt,
+	}
+	cmd.Flags().StringVarP(&settingsFile, "settings-file", "s", "airflow_settings.yaml", "The settings YAML file to encrypt. Default is 'airflow_settings.yaml'")
+	return cmd
+}
+
+func newObjectValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "validate",
+		Short:   "Validate your Airflow settings file",
+		Long:    "Check your Airflow settings file for unknown keys, values of the wrong type, duplicate connections or pools and invalid connection URIs. The settings file is also validated when Airflow starts up.",
+		PreRunE: utils.EnsureProjectDir,
+		RunE:    airflowSettingsValidate,
+	}
+	cmd.Flags().StringVarP(&settingsFile, "settings-file", "s", "airflow_settings.yaml", "The settings YAML file to validate. Default is 'airflow_settings.yaml'")
+	return cmd
+}
+
+func newObjectPullCmd(platformCoreClient astroplatformcore.CoreClient, airflowAPIClient airflowclient.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pull",
+		Short:   "Pull Airflow connections, variables, and pools from a Deployment into your Airflow settings file",
+		Long:    "Pull the Airflow connections, variables, and pools of an Astro Deployment into your Airflow settings file. Local objects with the same ids are replaced and the other local objects are kept. Use '--redact-secrets' to leave connection passwords and sensitive values out of the settings file.",
+		PreRunE: utils.EnsureProjectDir,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return airflowSettingsPull(cmd, platformCoreClient, airflowAPIClient)
+		},
+	}
+	cmd.Flags().StringVarP(&deploymentID, "deployment-id", "d", "", "The ID of the Deployment to pull Airflow objects from")
+	cmd.Flags().BoolVarP(&connections, "connections", "c", false, "Pull connections from the Deployment")
+	cmd.Flags().BoolVarP(&variables, "variables", "v", false, "Pull variables from the Deployment")
+	cmd.Flags().BoolVarP(&pools, "pools", "p", false, "Pull pools from the Deployment")
+	cmd.Flags().StringVarP(&settingsFile, "settings-file", "s", "airflow_settings.yaml", "The settings YAML file to write the Airflow objects to. Default is 'airflow_settings.yaml'")
+	cmd.Flags().BoolVarP(&redactSecrets, "redact-secrets", "", false, "Leave connection passwords and the values of sensitive connection extras and variables empty")
+	return cmd
+}
+
+func newAirflowSnapshotRootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "snapshot",
+		Aliases: []string{"snapshots"},
+		Short:   "Save and restore snapshots of your local Airflow metadata database",
+		Long:    "Save the state of your local Airflow metadata database, including DAG runs, connections, variables and XComs, and restore it later. Snapshots are stored in the .astro/snapshots directory of your project.",
+	}
+	cmd.AddCommand(
+		newSnapshotSaveCmd(),
+		newSnapshotRestoreCmd(),
+		newSnapshotListCmd(),
+	)
+	return cmd
+}
+
+func newSnapshotSaveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "save [snapshot name]",
+		Short:   "Save a snapshot of your local Airflow metadata database",
+		Long:    "Save a snapshot of your local Airflow metadata database. If no name is specified, the snapshot is named after the current time. Airflow must be running locally for this command to work.",
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: SetRuntimeIfExists,
+		RunE:    airflowSnapshotSave,
+	}
+	return cmd
+}
+
+func newSnapshotRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "restore [snapshot name]",
+		Short:   "Restore your local Airflow metadata database from a snapshot",
+		Long:    "Restore your local Airflow metadata database from a snapshot. The Airflow containers are stopped while the database is restored. Airflow must be running locally for this command to work.",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: SetRuntimeIfExists,
+		RunE:    airflowSnapshotRestore,
+	}
+	return cmd
+}
+
+func newSnapshotListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the snapshots of your local Airflow metadata database",
+		Long:    "List the snapshots of your local Airflow metadata database saved in this project.",
+		Args:    cobra.NoArgs,
+		PreRunE: utils.EnsureProjectDir,
+		RunE:    airflowSnapshotList,
+	}
+	return cmd
+}
+
 // Use project name for image name
 func airflowInit(cmd *cobra.Command, args []string) error {
 	name, err := ensureProjectName(args, projectName)
@@ -674,6 +808,15 @@ func airflowUpgradeTest(cmd *cobra.Command, platformCoreClient astroplatformcore
 
 // Start an airflow cluster
 func airflowStart(cmd *cobra.Command, args []string, astroCoreClient astrocore.CoreClient) error {
+	if watchProject && customImageName != "" {
+		return errInvalidBothWatchAndCustomImage
+	}
+	if executor != "" {
+		if err := airflow.ValidateExecutor(executor); err != nil {
+			return err
+		}
+	}
+
 	// Silence Usage as we have now validated command input
 	cmd.SilenceUsage = true
 
@@ -691,6 +834,22 @@ func airflowStart(cmd *cobra.Command, args []string, astroCoreClient astrocore.C
 		}
 	}
 
+	if fromDeploymentFile != "" {
+		var err error
+		envFile, err = configureFromDeploymentFile(fromDeploymentFile, config.WorkingPath, envFile, dockerfile, os.Stdout)
+		if err != nil {
+			return err
+		}
+	}
+
+	// stop, kill and restart manage the environment of the executor saved to the project config
+	if executor != "" {
+		err := config.CFG.DevExecutor.SetProjectString(executor)
+		if err != nil {
+			return err
+		}
+	}
+
 	containerHandler, err := containerHandlerInit(config.WorkingPath, envFile, dockerfile, "")
 	if err != nil {
 		return err
@@ -698,7 +857,15 @@ func airflowStart(cmd *cobra.Command, args []string, astroCoreClient astrocore.C
 
 	buildSecretString = util.GetbuildSecretString(buildSecrets)
 
-	return containerHandler.Start(customImageName, settingsFile, composeFile, buildSecretString, noCache, noBrowser, waitTime, envConns)
+	err = containerHandler.Start(customImageName, settingsFile, composeFile, buildSecretString, noCache, noBrowser, waitTime, envConns)
+	if err != nil {
+		return err
+	}
+
+	if watchProject {
+		return containerHandler.Watch(settingsFile, composeFile, buildSecretString)
+	}
+	return nil
 }
 
 // airflowRun
@@ -728,7 +895,20 @@ func airflowPS(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return containerHandler.PS()
+	return containerHandler.PS(psOutputFormat)
+}
+
+// List the airflow clusters of all local projects
+func airflowList(cmd *cobra.Command, args []string) error {
+	// Silence Usage as we have now validated command input
+	cmd.SilenceUsage = true
+
+	containerHandler, err := containerHandlerInit(config.WorkingPath, "", dockerfile, "")
+	if err != nil {
+		return err
+	}
+
+	return containerHandler.List()
 }
 
 // Outputs logs for a development airflow cluster
@@ -945,7 +1125,7 @@ func airflowSettingsImport(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	return containerHandler.ImportSettings(settingsFile, envFile, connections, variables, pools)
+	return containerHandler.ImportSettings(settingsFile, envFile, connections, variables, pools, pruneObjects)
 }
 
 func airflowSettingsExport(cmd *cobra.Command, args []string) error {
@@ -968,6 +1148,95 @@ func airflowSettingsExport(cmd *cobra.Command, args []string) error {
 	return containerHandler.ExportSettings(settingsFile, envFile, connections, variables, pools, envExport)
 }
 
+func airflowSettingsEncrypt(cmd *cobra.Command, args []string) error {
+	// Silence Usage as we have now validated command input
+	cmd.SilenceUsage = true
+
+	count, err := encryptSettingsFile(settingsFile)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Encrypted %d values in %s\n", count, settingsFile)
+	return nil
+}
+
+func airflowSettingsValidate(cmd *cobra.Command, args []string) error {
+	// Silence Usage as we have now validated command input
+	cmd.SilenceUsage = true
+
+	err := validateSettingsFile(settingsFile)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s is valid\n", settingsFile)
+	return nil
+}
+
+func airflowSettingsPull(cmd *cobra.Command, platformCoreClient astroplatformcore.CoreClient, airflowAPIClient airflowclient.Client) error {
+	if !context.IsCloudContext() {
+		return errPullNotOnAstro
+	}
+
+	// Silence Usage as we have now validated command input
+	cmd.SilenceUsage = true
+
+	c, err := config.GetCurrentContext()
+	if err != nil {
+		return err
+	}
+	requestedDeployment, err := getDeployment(c.Workspace, deploymentID, "", true, nil, platformCoreClient, nil)
+	if err != nil {
+		return err
+	}
+	if err := airflowversions.ValidateNoAirflow3Support(requestedDeployment.RuntimeVersion); err != nil {
+		return err
+	}
+
+	airflowURL := strings.Split(requestedDeployment.WebServerUrl, "?")[0]
+	return pullObjects(airflowURL, settingsFile, connections, variables, pools, redactSecrets, airflowAPIClient, os.Stdout)
+}
+
+func airflowSnapshotSave(cmd *cobra.Command, args []string) error {
+	// Silence Usage as we have now validated command input
+	cmd.SilenceUsage = true
+
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	containerHandler, err := containerHandlerInit(config.WorkingPath, "", dockerfile, "")
+	if err != nil {
+		return err
+	}
+
+	return containerHandler.SaveSnapshot(name)
+}
+
+func airflowSnapshotRestore(cmd *cobra.Command, args []string) error {
+	// Silence Usage as we have now validated command input
+	cmd.SilenceUsage = true
+
+	containerHandler, err := containerHandlerInit(config.WorkingPath, "", dockerfile, "")
+	if err != nil {
+		return err
+	}
+
+	return containerHandler.RestoreSnapshot(args[0])
+}
+
+func airflowSnapshotList(cmd *cobra.Command, args []string) error {
+	// Silence Usage as we have now validated command input
+	cmd.SilenceUsage = true
+
+	containerHandler, err := containerHandlerInit(config.WorkingPath, "", dockerfile, "")
+	if err != nil {
+		return err
+	}
+
+	return containerHandler.ListSnapshots()
+}
+
 func isValidTemplate(templateList []string, template string) bool {
 	return slices.Contains(templateList, template)
 }
@@ -56,6 +56,12 @@ func SetRuntimeIfExists(cmd *cobra.Command, args []string) error {
 	return containerRuntime.Configure()
 }
 
+// SetRuntime is a pre-run hook for commands that don't need a project directory.
+// It sets the container runtime if its running, otherwise we bail with an error message.
+func SetRuntime(_ *cobra.Command, _ []string) error {
+	return containerRuntime.Configure()
+}
+
 // KillPreRunHook sets the container runtime if its running,
 // otherwise we bail with an error message.
 func KillPreRunHook(cmd *cobra.Command, args []string) error {
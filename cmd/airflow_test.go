@@ -11,9 +11,11 @@ import (
 	"testing"
 
 	"github.com/astronomer/astro-cli/airflow"
+	airflowclient "github.com/astronomer/astro-cli/airflow-client"
 	"github.com/astronomer/astro-cli/airflow/mocks"
 	astrocore "github.com/astronomer/astro-cli/astro-client-core"
 	coreMocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
 	"github.com/astronomer/astro-cli/config"
 	testUtil "github.com/astronomer/astro-cli/pkg/testing"
 
@@ -138,7 +140,7 @@ func (s *AirflowSuite) TestDevInitCommandSoftware() {
 }
 
 func (s *AirflowSuite) TestNewAirflowDevRootCmd() {
-	cmd := newDevRootCmd(nil, nil)
+	cmd := newDevRootCmd(nil, nil, nil)
 	s.Nil(cmd.PersistentPreRunE(new(cobra.Command), []string{}))
 }
 
@@ -526,6 +528,84 @@ func (s *AirflowSuite) TestAirflowStart() {
 		mockContainerHandler.AssertExpectations(s.T())
 	})
 
+	s.Run("success with watch", func() {
+		cmd := newAirflowStartCmd(nil)
+		cmd.Flag("watch").Value.Set("true")
+		args := []string{"test-env-file"}
+
+		mockContainerHandler := new(mocks.ContainerHandler)
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			mockContainerHandler.On("Start", "", "airflow_settings.yaml", "", "", false, false, defaultWaitTime, map[string]astrocore.EnvironmentObjectConnection(nil)).Return(nil).Once()
+			mockContainerHandler.On("Watch", "airflow_settings.yaml", "", "").Return(nil).Once()
+			return mockContainerHandler, nil
+		}
+
+		err := airflowStart(cmd, args, nil)
+		s.NoError(err)
+		mockContainerHandler.AssertExpectations(s.T())
+	})
+
+	s.Run("success with kubernetes executor", func() {
+		cmd := newAirflowStartCmd(nil)
+		cmd.Flag("executor").Value.Set(airflow.ExecutorKubernetes)
+		defer func() { executor = "" }()
+		args := []string{"test-env-file"}
+
+		mockContainerHandler := new(mocks.ContainerHandler)
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			mockContainerHandler.On("Start", "", "airflow_settings.yaml", "", "", false, false, defaultWaitTime, map[string]astrocore.EnvironmentObjectConnection(nil)).Return(nil).Once()
+			return mockContainerHandler, nil
+		}
+
+		err := airflowStart(cmd, args, nil)
+		s.NoError(err)
+		mockContainerHandler.AssertExpectations(s.T())
+	})
+
+	s.Run("success with a deployment file", func() {
+		cmd := newAirflowStartCmd(nil)
+		cmd.Flag("from-deployment-file").Value.Set("deployment.yaml")
+		defer func() { fromDeploymentFile = "" }()
+
+		origConfigureFromDeploymentFile := configureFromDeploymentFile
+		defer func() { configureFromDeploymentFile = origConfigureFromDeploymentFile }()
+		configureFromDeploymentFile = func(deploymentFile, airflowHome, envFile, dockerfile string, out io.Writer) (string, error) {
+			s.Equal("deployment.yaml", deploymentFile)
+			s.Equal("test-env-file", envFile)
+			return ".astro/deployment.env", nil
+		}
+
+		mockContainerHandler := new(mocks.ContainerHandler)
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			s.Equal(".astro/deployment.env", envFile)
+			mockContainerHandler.On("Start", "", "airflow_settings.yaml", "", "", false, false, defaultWaitTime, map[string]astrocore.EnvironmentObjectConnection(nil)).Return(nil).Once()
+			return mockContainerHandler, nil
+		}
+
+		err := airflowStart(cmd, []string{"test-env-file"}, nil)
+		s.NoError(err)
+		mockContainerHandler.AssertExpectations(s.T())
+	})
+
+	s.Run("invalid executor", func() {
+		cmd := newAirflowStartCmd(nil)
+		cmd.Flag("executor").Value.Set("invalid")
+		defer func() { executor = "" }()
+
+		err := airflowStart(cmd, []string{}, nil)
+		s.EqualError(err, "invalid executor, the executor can be one of: local, celery or kubernetes")
+	})
+
+	s.Run("watch with a custom image", func() {
+		cmd := newAirflowStartCmd(nil)
+		cmd.Flag("watch").Value.Set("true")
+		cmd.Flag("image-name").Value.Set("custom-image")
+		args := []string{"test-env-file"}
+
+		err := airflowStart(cmd, args, nil)
+		s.ErrorIs(err, errInvalidBothWatchAndCustomImage)
+	})
+
 	s.Run("success with deployment id flag set but environment objects disabled", func() {
 		cmd := newAirflowStartCmd(nil)
 		deploymentID = "test-deployment-id"
@@ -770,7 +850,23 @@ func (s *AirflowSuite) TestAirflowPS() {
 
 		mockContainerHandler := new(mocks.ContainerHandler)
 		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
-			mockContainerHandler.On("PS").Return(nil).Once()
+			mockContainerHandler.On("PS", "table").Return(nil).Once()
+			return mockContainerHandler, nil
+		}
+
+		err := airflowPS(cmd, args)
+		s.NoError(err)
+		mockContainerHandler.AssertExpectations(s.T())
+	})
+
+	s.Run("success with json output", func() {
+		cmd := newAirflowPSCmd()
+		cmd.Flag("output").Value.Set("json")
+		args := []string{}
+
+		mockContainerHandler := new(mocks.ContainerHandler)
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			mockContainerHandler.On("PS", "json").Return(nil).Once()
 			return mockContainerHandler, nil
 		}
 
@@ -785,7 +881,7 @@ func (s *AirflowSuite) TestAirflowPS() {
 
 		mockContainerHandler := new(mocks.ContainerHandler)
 		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
-			mockContainerHandler.On("PS").Return(errMock).Once()
+			mockContainerHandler.On("PS", "table").Return(errMock).Once()
 			return mockContainerHandler, nil
 		}
 
@@ -807,6 +903,50 @@ func (s *AirflowSuite) TestAirflowPS() {
 	})
 }
 
+func (s *AirflowSuite) TestAirflowList() {
+	s.Run("success", func() {
+		cmd := newAirflowListCmd()
+		args := []string{}
+
+		mockContainerHandler := new(mocks.ContainerHandler)
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			mockContainerHandler.On("List").Return(nil).Once()
+			return mockContainerHandler, nil
+		}
+
+		err := airflowList(cmd, args)
+		s.NoError(err)
+		mockContainerHandler.AssertExpectations(s.T())
+	})
+
+	s.Run("failure", func() {
+		cmd := newAirflowListCmd()
+		args := []string{}
+
+		mockContainerHandler := new(mocks.ContainerHandler)
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			mockContainerHandler.On("List").Return(errMock).Once()
+			return mockContainerHandler, nil
+		}
+
+		err := airflowList(cmd, args)
+		s.ErrorIs(err, errMock)
+		mockContainerHandler.AssertExpectations(s.T())
+	})
+
+	s.Run("containerHandlerInit failure", func() {
+		cmd := newAirflowListCmd()
+		args := []string{}
+
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			return nil, errMock
+		}
+
+		err := airflowList(cmd, args)
+		s.ErrorIs(err, errMock)
+	})
+}
+
 func (s *AirflowSuite) TestAirflowLogs() {
 	s.Run("success", func() {
 		cmd := newAirflowLogsCmd()
@@ -1336,7 +1476,7 @@ func (s *AirflowSuite) TestAirflowObjectImport() {
 
 		mockContainerHandler := new(mocks.ContainerHandler)
 		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
-			mockContainerHandler.On("ImportSettings", "airflow_settings.yaml", ".env", connections, variables, pools).Return(nil).Once()
+			mockContainerHandler.On("ImportSettings", "airflow_settings.yaml", ".env", connections, variables, pools, false).Return(nil).Once()
 			return mockContainerHandler, nil
 		}
 
@@ -1351,7 +1491,23 @@ func (s *AirflowSuite) TestAirflowObjectImport() {
 
 		mockContainerHandler := new(mocks.ContainerHandler)
 		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
-			mockContainerHandler.On("ImportSettings", "airflow_settings.yaml", ".env", connections, variables, pools).Return(nil).Once()
+			mockContainerHandler.On("ImportSettings", "airflow_settings.yaml", ".env", connections, variables, pools, false).Return(nil).Once()
+			return mockContainerHandler, nil
+		}
+
+		err := airflowSettingsImport(cmd, args)
+		s.NoError(err)
+		mockContainerHandler.AssertExpectations(s.T())
+	})
+
+	s.Run("with prune", func() {
+		cmd := newObjectImportCmd()
+		cmd.Flag("prune").Value.Set("true")
+		args := []string{}
+
+		mockContainerHandler := new(mocks.ContainerHandler)
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			mockContainerHandler.On("ImportSettings", "airflow_settings.yaml", ".env", false, false, false, true).Return(nil).Once()
 			return mockContainerHandler, nil
 		}
 
@@ -1369,7 +1525,7 @@ func (s *AirflowSuite) TestAirflowObjectImport() {
 
 		mockContainerHandler := new(mocks.ContainerHandler)
 		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
-			mockContainerHandler.On("ImportSettings", "airflow_settings.yaml", ".env", connections, variables, pools).Return(errMock).Once()
+			mockContainerHandler.On("ImportSettings", "airflow_settings.yaml", ".env", connections, variables, pools, false).Return(errMock).Once()
 			return mockContainerHandler, nil
 		}
 
@@ -1492,3 +1648,184 @@ func (s *AirflowSuite) TestAirflowObjectExport() {
 		s.ErrorIs(err, errMock)
 	})
 }
+
+func (s *AirflowSuite) TestAirflowSettingsEncrypt() {
+	origEncryptSettingsFile := encryptSettingsFile
+	defer func() { encryptSettingsFile = origEncryptSettingsFile }()
+
+	s.Run("success", func() {
+		cmd := newObjectEncryptCmd()
+		cmd.Flag("settings-file").Value.Set("test_settings.yaml")
+
+		encryptSettingsFile = func(file string) (int, error) {
+			s.Equal("test_settings.yaml", file)
+			return 2, nil
+		}
+
+		err := airflowSettingsEncrypt(cmd, []string{})
+		s.NoError(err)
+	})
+
+	s.Run("failure", func() {
+		cmd := newObjectEncryptCmd()
+
+		encryptSettingsFile = func(file string) (int, error) {
+			return 0, errMock
+		}
+
+		err := airflowSettingsEncrypt(cmd, []string{})
+		s.ErrorIs(err, errMock)
+	})
+}
+
+func (s *AirflowSuite) TestAirflowSettingsValidate() {
+	origValidateSettingsFile := validateSettingsFile
+	defer func() { validateSettingsFile = origValidateSettingsFile }()
+
+	s.Run("valid", func() {
+		cmd := newObjectValidateCmd()
+
+		validateSettingsFile = func(file string) error {
+			s.Equal("airflow_settings.yaml", file)
+			return nil
+		}
+
+		err := airflowSettingsValidate(cmd, []string{})
+		s.NoError(err)
+	})
+
+	s.Run("invalid", func() {
+		cmd := newObjectValidateCmd()
+
+		validateSettingsFile = func(file string) error {
+			return errMock
+		}
+
+		err := airflowSettingsValidate(cmd, []string{})
+		s.ErrorIs(err, errMock)
+	})
+}
+
+func (s *AirflowSuite) TestAirflowSettingsPull() {
+	origGetDeployment := getDeployment
+	origPullObjects := pullObjects
+	defer func() {
+		getDeployment = origGetDeployment
+		pullObjects = origPullObjects
+	}()
+
+	s.Run("success", func() {
+		cmd := newObjectPullCmd(nil, nil)
+		deploymentID = "test-deployment-id"
+		redactSecrets = true
+		defer func() { deploymentID, redactSecrets = "", false }()
+
+		getDeployment = func(ws, deploymentID, deploymentName string, disableCreateFlow bool, selectionFilter func(deployment astroplatformcore.Deployment) bool, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient) (astroplatformcore.Deployment, error) {
+			s.Equal("test-deployment-id", deploymentID)
+			return astroplatformcore.Deployment{RuntimeVersion: "12.0.0", WebServerUrl: "test.astronomer.run/d1?orgId=org-id"}, nil
+		}
+		pullObjects = func(airflowURL, settingsFile string, connections, variables, pools, redactSecrets bool, airflowAPIClient airflowclient.Client, out io.Writer) error {
+			s.Equal("test.astronomer.run/d1", airflowURL)
+			s.Equal("airflow_settings.yaml", settingsFile)
+			s.True(redactSecrets)
+			return nil
+		}
+
+		err := airflowSettingsPull(cmd, nil, nil)
+		s.NoError(err)
+	})
+
+	s.Run("airflow 3 deployment", func() {
+		cmd := newObjectPullCmd(nil, nil)
+
+		getDeployment = func(ws, deploymentID, deploymentName string, disableCreateFlow bool, selectionFilter func(deployment astroplatformcore.Deployment) bool, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient) (astroplatformcore.Deployment, error) {
+			return astroplatformcore.Deployment{RuntimeVersion: "3.0-1"}, nil
+		}
+		pullObjects = func(airflowURL, settingsFile string, connections, variables, pools, redactSecrets bool, airflowAPIClient airflowclient.Client, out io.Writer) error {
+			s.Fail("objects should not be pulled")
+			return nil
+		}
+
+		err := airflowSettingsPull(cmd, nil, nil)
+		s.Error(err)
+	})
+
+	s.Run("software context", func() {
+		testUtil.InitTestConfig(testUtil.SoftwarePlatform)
+		cmd := newObjectPullCmd(nil, nil)
+
+		err := airflowSettingsPull(cmd, nil, nil)
+		s.ErrorIs(err, errPullNotOnAstro)
+	})
+}
+
+func (s *AirflowSuite) TestAirflowSnapshot() {
+	s.Run("save", func() {
+		cmd := newSnapshotSaveCmd()
+
+		mockContainerHandler := new(mocks.ContainerHandler)
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			mockContainerHandler.On("SaveSnapshot", "before-upgrade").Return(nil).Once()
+			return mockContainerHandler, nil
+		}
+
+		err := airflowSnapshotSave(cmd, []string{"before-upgrade"})
+		s.NoError(err)
+		mockContainerHandler.AssertExpectations(s.T())
+	})
+
+	s.Run("save without name", func() {
+		cmd := newSnapshotSaveCmd()
+
+		mockContainerHandler := new(mocks.ContainerHandler)
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			mockContainerHandler.On("SaveSnapshot", "").Return(errMock).Once()
+			return mockContainerHandler, nil
+		}
+
+		err := airflowSnapshotSave(cmd, []string{})
+		s.ErrorIs(err, errMock)
+		mockContainerHandler.AssertExpectations(s.T())
+	})
+
+	s.Run("restore", func() {
+		cmd := newSnapshotRestoreCmd()
+
+		mockContainerHandler := new(mocks.ContainerHandler)
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			mockContainerHandler.On("RestoreSnapshot", "before-upgrade").Return(nil).Once()
+			return mockContainerHandler, nil
+		}
+
+		err := airflowSnapshotRestore(cmd, []string{"before-upgrade"})
+		s.NoError(err)
+		mockContainerHandler.AssertExpectations(s.T())
+	})
+
+	s.Run("list", func() {
+		cmd := newSnapshotListCmd()
+
+		mockContainerHandler := new(mocks.ContainerHandler)
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			mockContainerHandler.On("ListSnapshots").Return(nil).Once()
+			return mockContainerHandler, nil
+		}
+
+		err := airflowSnapshotList(cmd, []string{})
+		s.NoError(err)
+		mockContainerHandler.AssertExpectations(s.T())
+	})
+
+	s.Run("containerHandlerInit failure", func() {
+		containerHandlerInit = func(airflowHome, envFile, dockerfile, imageName string) (airflow.ContainerHandler, error) {
+			return nil, errMock
+		}
+
+		err := airflowSnapshotSave(newSnapshotSaveCmd(), []string{})
+		s.ErrorIs(err, errMock)
+		err = airflowSnapshotRestore(newSnapshotRestoreCmd(), []string{"snapshot"})
+		s.ErrorIs(err, errMock)
+		err = airflowSnapshotList(newSnapshotListCmd(), []string{})
+		s.ErrorIs(err, errMock)
+	})
+}
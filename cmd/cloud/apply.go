@@ -0,0 +1,51 @@
+package cloud
+
+import (
+	"io"
+
+	"github.com/astronomer/astro-cli/cloud/deployment/fromfile"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyPath        string
+	applyAutoApprove bool
+	applyDryRun      bool
+
+	ApplyDeploymentFiles = fromfile.Apply
+)
+
+func newApplyCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Create or update the Deployments declared by a directory of deployment files",
+		Long:  "Create or update the Deployments declared by a directory of deployment files. The deployment files are compared to the live Deployments, and the plan of the Deployments to create and the fields to update is printed before it is applied. Deployment files have the format of 'astro deployment inspect' and can be in either JSON or YAML format.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return applyDeployments(cmd, out)
+		},
+		Example: `
+Print the plan of a directory of deployment files, and apply it once confirmed:
+
+  $ astro apply -f deployments/
+
+Print the plan without applying it, for example to review the changes of a pull request:
+
+  $ astro apply -f deployments/ --dry-run
+
+Apply the plan without being prompted, for example from CI/CD:
+
+  $ astro apply -f deployments/ --auto-approve
+`,
+	}
+	cmd.Flags().StringVarP(&applyPath, "file", "f", "", "Directory of the deployment files to apply, or a single deployment file")
+	cmd.Flags().BoolVar(&applyAutoApprove, "auto-approve", false, "Apply the plan without prompting for confirmation")
+	cmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "Print the plan without applying it")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func applyDeployments(cmd *cobra.Command, out io.Writer) error {
+	cmd.SilenceUsage = true
+	return ApplyDeploymentFiles(applyPath, applyAutoApprove, applyDryRun, platformCoreClient, astroCoreClient, out)
+}
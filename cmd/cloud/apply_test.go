@@ -0,0 +1,44 @@
+package cloud
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	"github.com/astronomer/astro-cli/cloud/deployment/fromfile"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+	defer func() { ApplyDeploymentFiles = fromfile.Apply }()
+
+	t.Run("applies the deployment files of a directory", func(t *testing.T) {
+		ApplyDeploymentFiles = func(inputPath string, autoApprove, dryRun bool, astroPlatformCore astroplatformcore.CoreClient, coreClient astrocore.CoreClient, out io.Writer) error {
+			assert.Equal(t, "deployments/", inputPath)
+			assert.True(t, autoApprove)
+			assert.False(t, dryRun)
+			return nil
+		}
+		err := testExecCmd(newApplyCmd(new(bytes.Buffer)), "-f", "deployments/", "--auto-approve")
+		assert.NoError(t, err)
+	})
+
+	t.Run("prints the plan of a dry run", func(t *testing.T) {
+		ApplyDeploymentFiles = func(inputPath string, autoApprove, dryRun bool, astroPlatformCore astroplatformcore.CoreClient, coreClient astrocore.CoreClient, out io.Writer) error {
+			assert.False(t, autoApprove)
+			assert.True(t, dryRun)
+			return nil
+		}
+		err := testExecCmd(newApplyCmd(new(bytes.Buffer)), "--file", "deployments/", "--dry-run")
+		assert.NoError(t, err)
+	})
+
+	t.Run("requires the deployment files", func(t *testing.T) {
+		err := testExecCmd(newApplyCmd(new(bytes.Buffer)))
+		assert.ErrorContains(t, err, `required flag(s) "file" not set`)
+	})
+}
@@ -0,0 +1,197 @@
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	cloud "github.com/astronomer/astro-cli/cloud/deploy"
+	"github.com/astronomer/astro-cli/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundlePath string
+	bundleType string
+
+	ListBundles = cloud.ListBundles
+
+	errBundleMountPathNotAbsolute = errors.New("the mount path of a bundle must be an absolute path, for example /usr/local/airflow/sql")
+)
+
+func newBundleCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Manage the bundles of files deployed to Deployments running on Astronomer",
+		Long:  "Manage the bundles of files deployed to Deployments running on Astronomer. A bundle is a directory, such as SQL files, configuration or ML model artifacts, that is mounted on the Airflow components of a Deployment alongside its DAGs.",
+	}
+	cmd.AddCommand(
+		newBundleDeployCmd(),
+		newBundleDeleteCmd(),
+		newBundleListCmd(out),
+	)
+	return cmd
+}
+
+func newBundleDeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy DEPLOYMENT-ID",
+		Short: "Deploy a bundle of files to a Deployment on Astro",
+		Long:  "Deploy a bundle of files to a Deployment on Astro. This command bundles the files of a directory and uploads them to your Deployment, where they are mounted at the mount path. Files matching the patterns of a .astroignore file at the root of the bundle are left out of the bundle.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  deployBundle,
+		Example: `
+Deploy the SQL files of the sql directory to a Deployment, mounted at /usr/local/airflow/sql:
+
+  $ astro bundle deploy <deployment ID> --bundle-path sql --type sql --mount-path /usr/local/airflow/sql
+
+Menu will be presented if you do not specify a deployment ID:
+
+  $ astro bundle deploy --type sql --mount-path /usr/local/airflow/sql
+`,
+	}
+
+	cmd.Flags().StringVarP(&bundlePath, "bundle-path", "p", "", "Path to the directory to deploy as a bundle. Default current directory")
+	cmd.Flags().StringVarP(&bundleType, "type", "t", "", "Type of the bundle, for example sql, config or model")
+	cmd.Flags().StringVarP(&mountPath, "mount-path", "m", "", "Absolute path to mount the bundle at in Airflow, for reference by DAGs")
+	cmd.Flags().StringVar(&workspaceID, "workspace-id", "", "Workspace for your Deployment")
+	cmd.Flags().StringVarP(&deploymentName, "deployment-name", "n", "", "Name of the Deployment to deploy to")
+	cmd.Flags().StringVarP(&deployDescription, "description", "", "", "Description to store on the deploy")
+	cmd.Flags().BoolVarP(&waitForDeploy, "wait", "w", false, "Wait for the Deployment to become healthy before ending the command")
+	_ = cmd.MarkFlagRequired("type")
+	_ = cmd.MarkFlagRequired("mount-path")
+
+	return cmd
+}
+
+func deployBundle(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	if !path.IsAbs(mountPath) {
+		return errBundleMountPathNotAbsolute
+	}
+
+	// if the bundle path is not provided, use the current directory
+	if bundlePath == "" {
+		bundlePath = config.WorkingPath
+	}
+
+	deploymentID, err := resolveBundleDeploymentID(args)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Initiating bundle deploy for deployment ID: " + deploymentID)
+
+	deployBundleInput := &cloud.DeployBundleInput{
+		BundlePath:         bundlePath,
+		MountPath:          mountPath,
+		DeploymentID:       deploymentID,
+		BundleType:         bundleType,
+		Description:        deployDescription,
+		Wait:               waitForDeploy,
+		PlatformCoreClient: platformCoreClient,
+		CoreClient:         astroCoreClient,
+	}
+	return DeployBundle(deployBundleInput)
+}
+
+func newBundleDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete DEPLOYMENT-ID",
+		Short: "Delete a bundle of files from a Deployment on Astro",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  deleteBundle,
+		Example: `
+Delete the bundle mounted at /usr/local/airflow/sql from a Deployment:
+
+  $ astro bundle delete <deployment ID> --type sql --mount-path /usr/local/airflow/sql
+`,
+	}
+
+	cmd.Flags().StringVarP(&bundleType, "type", "t", "", "Type of the bundle to delete")
+	cmd.Flags().StringVarP(&mountPath, "mount-path", "m", "", "Mount path of the bundle to delete from the Deployment")
+	cmd.Flags().StringVar(&workspaceID, "workspace-id", "", "Workspace for your Deployment")
+	cmd.Flags().StringVarP(&deploymentName, "deployment-name", "n", "", "Name of the Deployment to delete the bundle from")
+	cmd.Flags().StringVarP(&deployDescription, "description", "", "", "Description to store on the deploy")
+	cmd.Flags().BoolVarP(&waitForDeploy, "wait", "w", false, "Wait for the Deployment to become healthy before ending the command")
+	_ = cmd.MarkFlagRequired("type")
+	_ = cmd.MarkFlagRequired("mount-path")
+
+	return cmd
+}
+
+func deleteBundle(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	if !path.IsAbs(mountPath) {
+		return errBundleMountPathNotAbsolute
+	}
+
+	deploymentID, err := resolveBundleDeploymentID(args)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Initiating bundle delete deploy for deployment ID: " + deploymentID)
+
+	deleteBundleInput := &cloud.DeleteBundleInput{
+		MountPath:          mountPath,
+		DeploymentID:       deploymentID,
+		WorkspaceID:        workspaceID,
+		BundleType:         bundleType,
+		Description:        deployDescription,
+		Wait:               waitForDeploy,
+		PlatformCoreClient: platformCoreClient,
+		CoreClient:         astroCoreClient,
+	}
+	return DeleteBundle(deleteBundleInput)
+}
+
+func newBundleListCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list DEPLOYMENT-ID",
+		Aliases: []string{"ls"},
+		Short:   "List the bundles deployed to a Deployment on Astro",
+		Long:    "List the bundles deployed to a Deployment on Astro, with the path each bundle is mounted at and its current and desired versions",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listBundles(cmd, args, out)
+		},
+	}
+
+	cmd.Flags().StringVarP(&bundleType, "type", "t", "", "Only list the bundles of this type")
+	cmd.Flags().StringVar(&workspaceID, "workspace-id", "", "Workspace for your Deployment")
+	cmd.Flags().StringVarP(&deploymentName, "deployment-name", "n", "", "Name of the Deployment to list the bundles of")
+
+	return cmd
+}
+
+func listBundles(cmd *cobra.Command, args []string, out io.Writer) error {
+	cmd.SilenceUsage = true
+
+	deploymentID, err := resolveBundleDeploymentID(args)
+	if err != nil {
+		return err
+	}
+
+	listBundlesInput := &cloud.ListBundlesInput{
+		DeploymentID: deploymentID,
+		BundleType:   bundleType,
+		CoreClient:   astroCoreClient,
+	}
+	return ListBundles(listBundlesInput, out)
+}
+
+// resolveBundleDeploymentID returns the ID of the Deployment a bundle command targets, prompting for the Deployment
+// when neither its ID nor its name is given
+func resolveBundleDeploymentID(args []string) (string, error) {
+	// if the workspace ID is not provided, try to find a valid workspace
+	if workspaceID == "" {
+		var err error
+		workspaceID, err = coalesceWorkspace()
+		if err != nil {
+			return "", fmt.Errorf("failed to find a valid workspace: %w", err)
+		}
+	}
+	return resolveDeploymentIDFromDbtArgsFlags(args, workspaceID, deploymentName)
+}
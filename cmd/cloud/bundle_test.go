@@ -0,0 +1,149 @@
+package cloud
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	astrocore_mocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
+	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
+	astroplatformcore_mocks "github.com/astronomer/astro-cli/astro-client-platform-core/mocks"
+	cloud "github.com/astronomer/astro-cli/cloud/deploy"
+	"github.com/astronomer/astro-cli/config"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BundleCmdSuite struct {
+	suite.Suite
+	mockPlatformCoreClient *astroplatformcore_mocks.ClientWithResponsesInterface
+	mockCoreClient         *astrocore_mocks.ClientWithResponsesInterface
+	origPlatformCoreClient astroplatformcore.CoreClient
+	origCoreClient         astrocore.CoreClient
+	origDeployBundle       func(deployInput *cloud.DeployBundleInput) error
+	origDeleteBundle       func(deleteInput *cloud.DeleteBundleInput) error
+	origListBundles        func(listInput *cloud.ListBundlesInput, out io.Writer) error
+}
+
+func (s *BundleCmdSuite) SetupTest() {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+
+	// the package depends on global variables so we need to manage overriding those
+	s.origPlatformCoreClient = platformCoreClient
+	s.origCoreClient = astroCoreClient
+	s.origDeployBundle = DeployBundle
+	s.origDeleteBundle = DeleteBundle
+	s.origListBundles = ListBundles
+	s.mockPlatformCoreClient = new(astroplatformcore_mocks.ClientWithResponsesInterface)
+	s.mockCoreClient = new(astrocore_mocks.ClientWithResponsesInterface)
+	platformCoreClient = s.mockPlatformCoreClient
+	astroCoreClient = s.mockCoreClient
+}
+
+func (s *BundleCmdSuite) TearDownTest() {
+	s.mockPlatformCoreClient.AssertExpectations(s.T())
+	s.mockCoreClient.AssertExpectations(s.T())
+
+	platformCoreClient = s.origPlatformCoreClient
+	astroCoreClient = s.origCoreClient
+	DeployBundle = s.origDeployBundle
+	DeleteBundle = s.origDeleteBundle
+	ListBundles = s.origListBundles
+}
+
+func TestBundleCmd(t *testing.T) {
+	suite.Run(t, new(BundleCmdSuite))
+}
+
+func (s *BundleCmdSuite) TestBundleDeploy() {
+	var deployInput *cloud.DeployBundleInput
+	DeployBundle = func(input *cloud.DeployBundleInput) error {
+		deployInput = input
+		return nil
+	}
+
+	err := testExecCmd(newBundleDeployCmd(), "test-deployment-id", "--type", "sql", "--mount-path", "/usr/local/airflow/sql", "--bundle-path", "sql", "--description", "new queries")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &cloud.DeployBundleInput{
+		BundlePath:         "sql",
+		MountPath:          "/usr/local/airflow/sql",
+		DeploymentID:       "test-deployment-id",
+		BundleType:         "sql",
+		Description:        "new queries",
+		PlatformCoreClient: s.mockPlatformCoreClient,
+		CoreClient:         s.mockCoreClient,
+	}, deployInput)
+}
+
+func (s *BundleCmdSuite) TestBundleDeploy_DefaultBundlePath() {
+	DeployBundle = func(input *cloud.DeployBundleInput) error {
+		if input.BundlePath != config.WorkingPath {
+			return assert.AnError
+		}
+		return nil
+	}
+
+	bundlePath = ""
+	err := testExecCmd(newBundleDeployCmd(), "test-deployment-id", "--type", "config", "--mount-path", "/usr/local/airflow/config")
+	assert.NoError(s.T(), err)
+}
+
+func (s *BundleCmdSuite) TestBundleDeploy_MissingFlags() {
+	err := testExecCmd(newBundleDeployCmd(), "test-deployment-id", "--mount-path", "/usr/local/airflow/sql")
+	assert.ErrorContains(s.T(), err, `required flag(s) "type" not set`)
+
+	err = testExecCmd(newBundleDeployCmd(), "test-deployment-id", "--type", "sql")
+	assert.ErrorContains(s.T(), err, `required flag(s) "mount-path" not set`)
+}
+
+func (s *BundleCmdSuite) TestBundleDeploy_RelativeMountPath() {
+	err := testExecCmd(newBundleDeployCmd(), "test-deployment-id", "--type", "sql", "--mount-path", "sql")
+	assert.ErrorIs(s.T(), err, errBundleMountPathNotAbsolute)
+}
+
+func (s *BundleCmdSuite) TestBundleDelete() {
+	var deleteInput *cloud.DeleteBundleInput
+	DeleteBundle = func(input *cloud.DeleteBundleInput) error {
+		deleteInput = input
+		return nil
+	}
+
+	err := testExecCmd(newBundleDeleteCmd(), "test-deployment-id", "--type", "sql", "--mount-path", "/usr/local/airflow/sql", "--workspace-id", "test-ws-id", "--wait")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &cloud.DeleteBundleInput{
+		MountPath:          "/usr/local/airflow/sql",
+		DeploymentID:       "test-deployment-id",
+		WorkspaceID:        "test-ws-id",
+		BundleType:         "sql",
+		Wait:               true,
+		PlatformCoreClient: s.mockPlatformCoreClient,
+		CoreClient:         s.mockCoreClient,
+	}, deleteInput)
+}
+
+func (s *BundleCmdSuite) TestBundleDelete_RelativeMountPath() {
+	DeleteBundle = func(input *cloud.DeleteBundleInput) error {
+		s.Fail("no bundle should be deleted")
+		return nil
+	}
+
+	err := testExecCmd(newBundleDeleteCmd(), "test-deployment-id", "--type", "sql", "--mount-path", "sql")
+	assert.ErrorIs(s.T(), err, errBundleMountPathNotAbsolute)
+}
+
+func (s *BundleCmdSuite) TestBundleList() {
+	ListBundles = func(input *cloud.ListBundlesInput, out io.Writer) error {
+		if input.DeploymentID != "test-deployment-id" || input.BundleType != "sql" {
+			return assert.AnError
+		}
+		_, err := out.Write([]byte("bundles"))
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	err := testExecCmd(newBundleListCmd(buf), "test-deployment-id", "--type", "sql")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "bundles", buf.String())
+}
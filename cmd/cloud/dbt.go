@@ -14,11 +14,16 @@ import (
 )
 
 var (
-	mountPath      string
-	dbtProjectPath string
-
-	DeployBundle = cloud.DeployBundle
-	DeleteBundle = cloud.DeleteBundle
+	mountPath        string
+	dbtProjectPath   string
+	validateDbt      bool
+	dbtValidateImage string
+	dbtProfilesDir   string
+	astroProjectPath string
+
+	DeployBundle       = cloud.DeployBundle
+	DeleteBundle       = cloud.DeleteBundle
+	ValidateDbtProject = cloud.ValidateDbtProject
 )
 
 const (
@@ -43,7 +48,7 @@ func newDbtDeployCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "deploy DEPLOYMENT-ID",
 		Short: "Deploy your dbt project to a Deployment on Astro",
-		Long:  "Deploy your dbt project to a Deployment on Astro. This command bundles your dbt project files and uploads it to your Deployment.",
+		Long:  "Deploy your dbt project to a Deployment on Astro. This command bundles your dbt project files and uploads it to your Deployment. Files matching the patterns of a .astroignore file at the root of the dbt project are left out of the bundle.",
 		Args:  cobra.MaximumNArgs(1),
 		RunE:  deployDbt,
 		Example: `
@@ -54,6 +59,18 @@ Specify the ID of the Deployment on Astronomer you would like to deploy this dbt
 Menu will be presented if you do not specify a deployment ID:
 
   $ astro dbt deploy
+
+Install the packages of the dbt project, then parse and compile it before deploying it, in the image of the Astro project of the Deployment:
+
+  $ astro dbt deploy <deployment ID> --project-path <dbt project path> --validate
+
+Validate the dbt project in another image with dbt and the dbt adapter of the project installed:
+
+  $ astro dbt deploy <deployment ID> --validate --validate-image <image>
+
+Read the profiles.yml of the dbt project from another directory when validating it:
+
+  $ astro dbt deploy <deployment ID> --project-path <dbt project path> --validate --profiles-dir ~/.dbt
 `,
 	}
 
@@ -63,6 +80,10 @@ Menu will be presented if you do not specify a deployment ID:
 	cmd.Flags().StringVarP(&deploymentName, "deployment-name", "n", "", "Name of the Deployment to deploy to")
 	cmd.Flags().StringVarP(&deployDescription, "description", "", "", "Description to store on the deploy")
 	cmd.Flags().BoolVarP(&waitForDeploy, "wait", "w", false, "Wait for the Deployment to become healthy before ending the command")
+	cmd.Flags().BoolVar(&validateDbt, "validate", false, "Run dbt deps, dbt parse and dbt compile on the dbt project before deploying it, failing the deploy on compilation errors")
+	cmd.Flags().StringVar(&astroProjectPath, "astro-project-path", "", "Path to the Astro project whose image the dbt project is validated in. Default current directory")
+	cmd.Flags().StringVar(&dbtValidateImage, "validate-image", "", "Image to validate the dbt project in instead of the image of the Astro project, with dbt and the dbt adapter of the project installed")
+	cmd.Flags().StringVar(&dbtProfilesDir, "profiles-dir", "", "Directory of the profiles.yml to validate the dbt project with. Default the directory of DBT_PROFILES_DIR, else the dbt project when it has a profiles.yml, else ~/.dbt")
 
 	return cmd
 }
@@ -112,6 +133,24 @@ func deployDbt(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println("Initiating dbt deploy for deployment ID: " + deploymentID)
 
+	// if requested, check the dbt project compiles before uploading it
+	if validateDbt {
+		// if the Astro project path is not provided, use the current directory
+		if astroProjectPath == "" {
+			astroProjectPath = config.WorkingPath
+		}
+		validateInput := &cloud.ValidateDbtProjectInput{
+			DbtProjectPath:   dbtProjectPath,
+			ProfilesDir:      dbtProfilesDir,
+			AstroProjectPath: astroProjectPath,
+			Image:            dbtValidateImage,
+		}
+		err = ValidateDbtProject(validateInput)
+		if err != nil {
+			return err
+		}
+	}
+
 	// if the mount path is not provided, derive it from the dbt project name
 	if mountPath == "" {
 		mountPath = dbtDefaultMountPathPrefix + dbtProjectName
@@ -27,6 +27,7 @@ type DbtSuite struct {
 	origCoreClient         astrocore.CoreClient
 	origDeployBundle       func(deployInput *cloud.DeployBundleInput) error
 	origDeleteBundle       func(deleteInput *cloud.DeleteBundleInput) error
+	origValidateDbtProject func(validateInput *cloud.ValidateDbtProjectInput) error
 }
 
 func (s *DbtSuite) SetupTest() {
@@ -37,6 +38,7 @@ func (s *DbtSuite) SetupTest() {
 	s.origCoreClient = astroCoreClient
 	s.origDeployBundle = DeployBundle
 	s.origDeleteBundle = DeleteBundle
+	s.origValidateDbtProject = ValidateDbtProject
 	s.mockPlatformCoreClient = new(astroplatformcore_mocks.ClientWithResponsesInterface)
 	s.mockCoreClient = new(astrocore_mocks.ClientWithResponsesInterface)
 	platformCoreClient = s.mockPlatformCoreClient
@@ -51,6 +53,7 @@ func (s *DbtSuite) TearDownTest() {
 	astroCoreClient = s.origCoreClient
 	DeployBundle = s.origDeployBundle
 	DeleteBundle = s.origDeleteBundle
+	ValidateDbtProject = s.origValidateDbtProject
 }
 
 func TestDbt(t *testing.T) {
@@ -131,6 +134,66 @@ func (s *DbtSuite) TestDbtDeploy_WithinAstroProject() {
 	assert.Contains(s.T(), err.Error(), "dbt project is within an Astro project")
 }
 
+func (s *DbtSuite) TestDbtDeploy_Validate() {
+	s.createDbtProjectFile("dbt_project.yml")
+	defer os.Remove("dbt_project.yml")
+
+	var validateInput *cloud.ValidateDbtProjectInput
+	ValidateDbtProject = func(input *cloud.ValidateDbtProjectInput) error {
+		validateInput = input
+		return nil
+	}
+	DeployBundle = func(deployInput *cloud.DeployBundleInput) error {
+		return nil
+	}
+
+	err := testExecCmd(newDbtDeployCmd(), "test-deployment-id", "--validate", "--profiles-dir", "profiles")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &cloud.ValidateDbtProjectInput{
+		DbtProjectPath:   config.WorkingPath,
+		ProfilesDir:      "profiles",
+		AstroProjectPath: config.WorkingPath,
+	}, validateInput)
+}
+
+func (s *DbtSuite) TestDbtDeploy_ValidateFailure() {
+	s.createDbtProjectFile("dbt_project.yml")
+	defer os.Remove("dbt_project.yml")
+
+	ValidateDbtProject = func(input *cloud.ValidateDbtProjectInput) error {
+		return assert.AnError
+	}
+	DeployBundle = func(deployInput *cloud.DeployBundleInput) error {
+		s.Fail("the dbt project should not be deployed")
+		return nil
+	}
+
+	err := testExecCmd(newDbtDeployCmd(), "test-deployment-id", "--validate", "--validate-image", "dbt-image")
+	assert.ErrorIs(s.T(), err, assert.AnError)
+}
+
+func (s *DbtSuite) TestDbtDeploy_ValidateInImage() {
+	s.createDbtProjectFile("dbt_project.yml")
+	defer os.Remove("dbt_project.yml")
+
+	var validateInput *cloud.ValidateDbtProjectInput
+	ValidateDbtProject = func(input *cloud.ValidateDbtProjectInput) error {
+		validateInput = input
+		return nil
+	}
+	DeployBundle = func(deployInput *cloud.DeployBundleInput) error {
+		return nil
+	}
+
+	err := testExecCmd(newDbtDeployCmd(), "test-deployment-id", "--validate", "--validate-image", "dbt-image", "--astro-project-path", "astro-project")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &cloud.ValidateDbtProjectInput{
+		DbtProjectPath:   config.WorkingPath,
+		AstroProjectPath: "astro-project",
+		Image:            "dbt-image",
+	}, validateInput)
+}
+
 func (s *DbtSuite) TestDbtDelete_PickDeployment() {
 	s.createDbtProjectFile("dbt_project.yml")
 	defer os.Remove("dbt_project.yml")
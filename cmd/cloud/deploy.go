@@ -2,6 +2,7 @@ package cloud
 
 import (
 	"fmt"
+	"strings"
 
 	cloud "github.com/astronomer/astro-cli/cloud/deploy"
 	"github.com/astronomer/astro-cli/cmd/utils"
@@ -27,6 +28,7 @@ var (
 	imageName         string
 	deploymentName    string
 	deployDescription string
+	deploymentIDs     []string
 	deployExample     = `
 Specify the ID of the Deployment on Astronomer you would like to deploy this project to:
 
@@ -35,12 +37,29 @@ Specify the ID of the Deployment on Astronomer you would like to deploy this pro
 Menu will be presented if you do not specify a deployment ID:
 
   $ astro deploy
+
+Build the image once and deploy it to several Deployments in order, waiting for each one to become healthy before deploying to the next:
+
+  $ astro deploy --deployment-id <dev deployment ID>,<staging deployment ID>,<prod deployment ID> --wait
+
+Attach an SBOM to the pushed image and sign it with the cosign key of the project:
+
+  $ astro deploy <deployment ID> --sbom --sign --sign-key cosign.key
 `
 
-	DeployImage       = cloud.Deploy
-	EnsureProjectDir  = utils.EnsureProjectDir
-	buildSecrets      = []string{}
-	forceUpgradeToAF3 bool
+	DeployImage         = cloud.Deploy
+	DeployToDeployments = cloud.DeployToDeployments
+	EnsureProjectDir    = utils.EnsureProjectDir
+	buildSecrets        = []string{}
+	forceUpgradeToAF3   bool
+	dryRun              bool
+	waitForLock         bool
+	skipLock            bool
+	forceUpload         bool
+	sbom                bool
+	sbomFormat          string
+	signImage           bool
+	signKey             string
 )
 
 const (
@@ -51,7 +70,7 @@ func NewDeployCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "deploy DEPLOYMENT-ID",
 		Short:   "Deploy your project to a Deployment on Astro",
-		Long:    "Deploy your project to a Deployment on Astro. This command bundles your project files into a Docker image and pushes that Docker image to Astronomer. It does not include any metadata associated with your local Airflow environment.",
+		Long:    "Deploy your project to a Deployment on Astro. This command bundles your project files into a Docker image and pushes that Docker image to Astronomer. It does not include any metadata associated with your local Airflow environment. Files matching the patterns of a .astroignore file at the root of the project are left out of the DAGs uploaded by DAG deploys. The commands of the deploy.pre_hooks project config run once the deploy is created, before the image is pushed and the DAGs are uploaded, with the ASTRO_DEPLOYMENT_ID, ASTRO_DEPLOYMENT_NAME, ASTRO_DEPLOY_ID and ASTRO_IMAGE_TAG environment variables describing the deploy, and a failing pre-deploy hook aborts the deploy before it is finalized. ASTRO_DAG_TARBALL_VERSION is empty for them since the DAGs are not uploaded yet. The commands of the deploy.post_hooks project config run after the deploy, with ASTRO_DAG_TARBALL_VERSION set too.",
 		Args:    cobra.MaximumNArgs(1),
 		PreRunE: EnsureProjectDir,
 		RunE:    deploy,
@@ -70,11 +89,20 @@ func NewDeployCmd() *cobra.Command {
 	cmd.Flags().StringVar(&dagsPath, "dags-path", "", "If set deploy dags from this path instead of the dags from working directory")
 	cmd.Flags().StringVarP(&deploymentName, "deployment-name", "n", "", "Name of the deployment to deploy to")
 	cmd.Flags().BoolVar(&parse, "parse", false, "Succeed only if all DAGs in your Astro project parse without errors")
-	cmd.Flags().BoolVarP(&waitForDeploy, "wait", "w", false, "Wait for the Deployment to become healthy before ending the command")
+	cmd.Flags().BoolVarP(&waitForDeploy, "wait", "w", false, "Wait for the Deployment to become healthy before ending the command. With several Deployments, a Deployment is only deployed to once the previous one is healthy")
 	cmd.Flags().MarkHidden("dags-path") //nolint:errcheck
 	cmd.Flags().StringVarP(&deployDescription, "description", "", "", "Add a description for more context on this deploy")
 	cmd.Flags().StringSliceVar(&buildSecrets, "build-secrets", []string{}, "Mimics docker build --secret flag. See https://docs.docker.com/build/building/secrets/ for more information. Example input id=mysecret,src=secrets.txt")
 	cmd.Flags().BoolVar(&forceUpgradeToAF3, "force-upgrade-to-af3", false, "Force allow upgrade from Airflow 2 to Airflow 3")
+	cmd.Flags().StringSliceVar(&deploymentIDs, "deployment-id", []string{}, "IDs of the Deployments to deploy to, in order. The image is built once and deployed to each of them. Defaults to the deploy.targets project config")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print what the deploy would do, like the deploy type, the Astro Runtime version and the DAG files, without deploying")
+	cmd.Flags().BoolVar(&waitForLock, "wait-for-lock", false, "Wait for another deploy in progress to the Deployment to finish instead of failing")
+	cmd.Flags().BoolVar(&skipLock, "skip-lock", false, "Deploy even if another deploy to the Deployment is in progress")
+	cmd.Flags().BoolVar(&forceUpload, "force-upload", false, "Upload the DAGs even if they did not change since the last DAG deploy")
+	cmd.Flags().BoolVar(&sbom, "sbom", false, "Generate an SBOM of the image from its Python and OS packages and attach it to the pushed image. Requires cosign")
+	cmd.Flags().StringVar(&sbomFormat, "sbom-format", cloud.SBOMFormatCycloneDX, "The format of the SBOM generated with --sbom. Possible values are cyclonedx and spdx")
+	cmd.Flags().BoolVar(&signImage, "sign", false, "Sign the digest of the pushed image with a local cosign key. Requires cosign")
+	cmd.Flags().StringVar(&signKey, "sign-key", "cosign.key", "Path to the cosign private key used by --sign, relative to the project directory")
 	return cmd
 }
 
@@ -102,6 +130,14 @@ func deploy(cmd *cobra.Command, args []string) error {
 		deploymentID = args[0]
 	}
 
+	targets, err := deployTargets(deploymentID)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 1 {
+		deploymentID = targets[0]
+	}
+
 	if deploymentID == "" || forcePrompt || workspaceID == "" {
 		var err error
 		workspaceID, err = coalesceWorkspace()
@@ -114,6 +150,14 @@ func deploy(cmd *cobra.Command, args []string) error {
 		return errors.New("cannot use both --dags and --image together. Run 'astro deploy' to update both your image and dags")
 	}
 
+	// Save the deployment targets in config if specified
+	if len(targets) > 1 && saveDeployConfig {
+		err := config.CFG.DeployTargets.SetProjectString(strings.Join(targets, ","))
+		if err != nil {
+			return err
+		}
+	}
+
 	// Save deploymentId in config if specified
 	if deploymentID != "" && saveDeployConfig {
 		err := config.CFG.ProjectDeployment.SetProjectString(deploymentID)
@@ -122,7 +166,8 @@ func deploy(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if git.HasUncommittedChanges("") && !forceDeploy {
+	// a dry run deploys nothing, so uncommitted changes are fine
+	if git.HasUncommittedChanges("") && !forceDeploy && !dryRun {
 		fmt.Println(registryUncommitedChangesMsg)
 		return nil
 	}
@@ -155,7 +200,37 @@ func deploy(cmd *cobra.Command, args []string) error {
 		Description:       deployDescription,
 		BuildSecretString: BuildSecretString,
 		ForceUpgradeToAF3: forceUpgradeToAF3,
+		DryRun:            dryRun,
+		Force:             forceDeploy,
+		ForceUpload:       forceUpload,
+		SkipLock:          skipLock,
+		WaitForLock:       waitForLock,
+		SBOM:              sbom,
+		SBOMFormat:        sbomFormat,
+		Sign:              signImage,
+		SignKey:           signKey,
 	}
 
+	if len(targets) > 1 {
+		return DeployToDeployments(deployInput, targets, platformCoreClient, astroCoreClient)
+	}
 	return DeployImage(deployInput, platformCoreClient, astroCoreClient)
 }
+
+// deployTargets returns the Deployments to deploy to, from the DEPLOYMENT-ID argument, the --deployment-id flag or
+// the deploy.targets config, in that order
+func deployTargets(argDeploymentID string) ([]string, error) {
+	if argDeploymentID != "" {
+		if len(deploymentIDs) > 0 {
+			return nil, errors.New("cannot use both the DEPLOYMENT-ID argument and the --deployment-id flag")
+		}
+		return []string{argDeploymentID}, nil
+	}
+	if len(deploymentIDs) > 0 {
+		return deploymentIDs, nil
+	}
+	if deploymentName != "" || forcePrompt {
+		return nil, nil
+	}
+	return config.CFG.DeployTargets.GetStringSlice(), nil
+}
@@ -1,11 +1,13 @@
 package cloud
 
 import (
+	"errors"
 	"testing"
 
 	astrocore "github.com/astronomer/astro-cli/astro-client-core"
 	astroplatformcore "github.com/astronomer/astro-cli/astro-client-platform-core"
 	cloud "github.com/astronomer/astro-cli/cloud/deploy"
+	"github.com/astronomer/astro-cli/config"
 	testUtil "github.com/astronomer/astro-cli/pkg/testing"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -66,3 +68,83 @@ func TestDeployImage(t *testing.T) {
 	err = execDeployCmd("-f", "test-deployment-id", "--dags", "--parse", "--pytest")
 	assert.NoError(t, err)
 }
+
+func TestDeployDryRun(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+
+	EnsureProjectDir = func(cmd *cobra.Command, args []string) error {
+		return nil
+	}
+
+	var input cloud.InputDeploy
+	DeployImage = func(deployInput cloud.InputDeploy, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient) error {
+		input = deployInput
+		return nil
+	}
+
+	err := execDeployCmd("test-deployment-id", "--dry-run")
+	assert.NoError(t, err)
+	assert.True(t, input.DryRun)
+	assert.Equal(t, "test-deployment-id", input.RuntimeID)
+
+	err = execDeployCmd("test-deployment-id", "-f")
+	assert.NoError(t, err)
+	assert.False(t, input.DryRun)
+}
+
+func TestDeploySBOMAndSign(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+
+	EnsureProjectDir = func(cmd *cobra.Command, args []string) error {
+		return nil
+	}
+
+	var input cloud.InputDeploy
+	DeployImage = func(deployInput cloud.InputDeploy, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient) error {
+		input = deployInput
+		return nil
+	}
+
+	err := execDeployCmd("test-deployment-id", "-f", "--sbom", "--sbom-format", "spdx", "--sign", "--sign-key", "keys/cosign.key")
+	assert.NoError(t, err)
+	assert.True(t, input.SBOM)
+	assert.Equal(t, cloud.SBOMFormatSPDX, input.SBOMFormat)
+	assert.True(t, input.Sign)
+	assert.Equal(t, "keys/cosign.key", input.SignKey)
+
+	err = execDeployCmd("test-deployment-id", "-f")
+	assert.NoError(t, err)
+	assert.False(t, input.SBOM)
+	assert.Equal(t, cloud.SBOMFormatCycloneDX, input.SBOMFormat)
+	assert.False(t, input.Sign)
+}
+
+func TestDeployToMultipleDeployments(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+
+	EnsureProjectDir = func(cmd *cobra.Command, args []string) error {
+		return nil
+	}
+	DeployImage = func(deployInput cloud.InputDeploy, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient) error {
+		return errors.New("single deploy should not be called")
+	}
+
+	var targets []string
+	DeployToDeployments = func(deployInput cloud.InputDeploy, deploymentIDs []string, platformCoreClient astroplatformcore.CoreClient, coreClient astrocore.CoreClient) error {
+		targets = deploymentIDs
+		return nil
+	}
+
+	err := execDeployCmd("--deployment-id", "dev-id,prod-id", "-f")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dev-id", "prod-id"}, targets)
+
+	err = execDeployCmd("test-deployment-id", "--deployment-id", "dev-id,prod-id", "-f")
+	assert.ErrorContains(t, err, "cannot use both the DEPLOYMENT-ID argument and the --deployment-id flag")
+
+	config.CFG.DeployTargets.SetHomeString("staging-id,prod-id")
+	defer config.CFG.DeployTargets.SetHomeString("")
+	err = execDeployCmd("-f")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"staging-id", "prod-id"}, targets)
+}
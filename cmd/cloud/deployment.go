@@ -76,6 +76,8 @@ var (
 	logScheduler              bool
 	logWorkers                bool
 	logTriggerer              bool
+	deployLimit               = 20
+	forceRollback             bool
 
 	deploymentType                = standard
 	deploymentVariableListExample = `
@@ -127,6 +129,8 @@ func newDeploymentRootCmd(out io.Writer) *cobra.Command {
 		newDeploymentTokenRootCmd(out),
 		newDeploymentHibernateCmd(),
 		newDeploymentWakeUpCmd(),
+		newDeploymentDeploysRootCmd(out),
+		newDeploymentRollbackCmd(out),
 	)
 	return cmd
 }
@@ -594,6 +598,66 @@ func newDeploymentWakeUpCmd() *cobra.Command {
 	return cmd
 }
 
+func newDeploymentDeploysRootCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "deploys",
+		Aliases: []string{"deploy"},
+		Short:   "Show the deploys of an Astro Deployment",
+		Long:    "Show the history of the image and DAG deploys of an Astro Deployment and compare two of them.",
+	}
+	cmd.PersistentFlags().StringVarP(&deploymentID, "deployment-id", "d", "", "ID of the Deployment to show the deploys of")
+	cmd.PersistentFlags().StringVarP(&deploymentName, "deployment-name", "n", "", "Name of the Deployment to show the deploys of")
+	cmd.AddCommand(
+		newDeploymentDeploysListCmd(out),
+		newDeploymentDeploysDiffCmd(out),
+	)
+	return cmd
+}
+
+func newDeploymentDeploysListCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the deploys of an Astro Deployment",
+		Long:    "List the most recent deploys of an Astro Deployment with their image tag, DAG tarball version and git commit, newest first.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return deploymentDeploysList(cmd, out)
+		},
+	}
+	cmd.Flags().IntVarP(&deployLimit, "limit", "l", deployLimit, "Number of deploys to show")
+	return cmd
+}
+
+func newDeploymentDeploysDiffCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff DEPLOY-ID DEPLOY-ID",
+		Short: "Compare two deploys of an Astro Deployment",
+		Long:  "Compare the image tag, the DAG tarball version and the git commit of two deploys of an Astro Deployment. The fields that changed are highlighted.",
+		Args:  cobra.ExactArgs(2), //nolint:mnd
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return deploymentDeploysDiff(cmd, args, out)
+		},
+	}
+	return cmd
+}
+
+func newDeploymentRollbackCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback DEPLOY-ID",
+		Short: "Roll back an Astro Deployment to a previous deploy",
+		Long:  "Roll back an Astro Deployment to the image and the DAGs of a previous deploy, without rebuilding them. List the deploys of the Deployment with 'astro deployment deploys list'.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return deploymentRollback(cmd, args, out)
+		},
+	}
+	cmd.Flags().StringVarP(&deploymentID, "deployment-id", "d", "", "ID of the Deployment to roll back")
+	cmd.Flags().StringVarP(&deploymentName, "deployment-name", "n", "", "Name of the Deployment to roll back")
+	cmd.Flags().StringVar(&description, "description", "", "Description of the rollback deploy")
+	cmd.Flags().BoolVarP(&forceRollback, "force", "f", false, "Force rollback. The CLI will not prompt to confirm before rolling back the Deployment.")
+	return cmd
+}
+
 func deploymentList(cmd *cobra.Command, out io.Writer) error {
 	ws, err := coalesceWorkspace()
 	if err != nil {
@@ -798,6 +862,42 @@ func deploymentDelete(cmd *cobra.Command, args []string) error {
 	return deployment.Delete(deploymentID, ws, deploymentName, forceDelete, platformCoreClient)
 }
 
+func deploymentDeploysList(cmd *cobra.Command, out io.Writer) error {
+	ws, err := coalesceWorkspace()
+	if err != nil {
+		return errors.Wrap(err, "failed to find a valid Workspace")
+	}
+
+	// Silence Usage as we have now validated command input
+	cmd.SilenceUsage = true
+
+	return deployment.ListDeploys(deploymentID, ws, deploymentName, deployLimit, platformCoreClient, astroCoreClient, out)
+}
+
+func deploymentDeploysDiff(cmd *cobra.Command, args []string, out io.Writer) error {
+	ws, err := coalesceWorkspace()
+	if err != nil {
+		return errors.Wrap(err, "failed to find a valid Workspace")
+	}
+
+	// Silence Usage as we have now validated command input
+	cmd.SilenceUsage = true
+
+	return deployment.DiffDeploys(deploymentID, ws, deploymentName, args[0], args[1], platformCoreClient, astroCoreClient, out)
+}
+
+func deploymentRollback(cmd *cobra.Command, args []string, out io.Writer) error {
+	ws, err := coalesceWorkspace()
+	if err != nil {
+		return errors.Wrap(err, "failed to find a valid Workspace")
+	}
+
+	// Silence Usage as we have now validated command input
+	cmd.SilenceUsage = true
+
+	return deployment.Rollback(deploymentID, ws, deploymentName, args[0], description, forceRollback, platformCoreClient, astroCoreClient, out)
+}
+
 func deploymentVariableList(cmd *cobra.Command, _ []string, out io.Writer) error {
 	ws, err := coalesceWorkspace()
 	if err != nil {
@@ -423,6 +423,47 @@ func TestDeploymentLogs(t *testing.T) {
 	mockCoreClient.AssertExpectations(t)
 }
 
+func TestDeploymentDeploys(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+
+	mockPlatformCoreClient := new(astroplatformcore_mocks.ClientWithResponsesInterface)
+	mockCoreClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockPlatformCoreClient.On("ListDeploymentsWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&mockListDeploymentsResponse, nil).Times(3)
+	mockPlatformCoreClient.On("GetDeploymentWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&deploymentResponse, nil).Times(3)
+	deploy := astrocore.Deploy{Id: "test-deploy-id", ImageTag: "deploy-2024-01-01", Status: astrocore.DEPLOYED}
+	mockCoreClient.On("ListDeploysWithResponse", mock.Anything, mock.Anything, "test-id-1", mock.Anything).Return(&astrocore.ListDeploysResponse{
+		HTTPResponse: &http.Response{StatusCode: 200},
+		JSON200:      &astrocore.DeploysPaginated{Deploys: []astrocore.Deploy{deploy}},
+	}, nil).Once()
+	mockCoreClient.On("GetDeployWithResponse", mock.Anything, mock.Anything, "test-id-1", "test-deploy-id", mock.Anything).Return(&astrocore.GetDeployResponse{
+		HTTPResponse: &http.Response{StatusCode: 200},
+		JSON200:      &deploy,
+	}, nil).Times(3)
+	mockCoreClient.On("DeployRollbackWithResponse", mock.Anything, mock.Anything, "test-id-1", astrocore.DeployRollbackRequest{DeployId: "test-deploy-id"}).Return(&astrocore.DeployRollbackResponse{
+		HTTPResponse: &http.Response{StatusCode: 200},
+		JSON200:      &astrocore.Deploy{Id: "test-rollback-id"},
+	}, nil).Once()
+	platformCoreClient = mockPlatformCoreClient
+	astroCoreClient = mockCoreClient
+
+	resp, err := execDeploymentCmd("deploys", "list", "--deployment-id", "test-id-1")
+	assert.NoError(t, err)
+	assert.Contains(t, resp, "test-deploy-id")
+
+	resp, err = execDeploymentCmd("deploys", "diff", "test-deploy-id", "test-deploy-id", "--deployment-id", "test-id-1")
+	assert.NoError(t, err)
+	assert.Contains(t, resp, "deploy-2024-01-01")
+
+	resp, err = execDeploymentCmd("rollback", "test-deploy-id", "--deployment-id", "test-id-1", "--force")
+	assert.NoError(t, err)
+	assert.Contains(t, resp, "test-rollback-id")
+
+	_, err = execDeploymentCmd("rollback", "--deployment-id", "test-id-1")
+	assert.Error(t, err)
+	mockPlatformCoreClient.AssertExpectations(t)
+	mockCoreClient.AssertExpectations(t)
+}
+
 func TestDeploymentLogsMultipleComponents(t *testing.T) {
 	testUtil.InitTestConfig(testUtil.LocalPlatform)
 
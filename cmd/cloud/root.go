@@ -29,5 +29,7 @@ func AddCmds(astroPlatformCoreClient astroplatformcore.CoreClient, coreClient as
 		newWorkspaceCmd(out),
 		newOrganizationCmd(out),
 		newDbtCmd(),
+		newBundleCmd(out),
+		newApplyCmd(out),
 	}
 }
@@ -11,6 +11,6 @@ func TestAddCmds(t *testing.T) {
 	buf := new(bytes.Buffer)
 	cmds := AddCmds(nil, nil, nil, nil, buf)
 	for cmdIdx := range cmds {
-		assert.Contains(t, []string{"deployment", "deploy DEPLOYMENT-ID", "workspace", "user", "organization", "dbt"}, cmds[cmdIdx].Use)
+		assert.Contains(t, []string{"deployment", "deploy DEPLOYMENT-ID", "workspace", "user", "organization", "dbt", "bundle", "apply"}, cmds[cmdIdx].Use)
 	}
 }
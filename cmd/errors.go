@@ -8,6 +8,7 @@ var (
 	errInvalidBothAirflowAndRuntimeVersions        = errors.New("you provided both a runtime version and an Airflow version. You have to provide only one of these to initialize your project") //nolint
 	errInvalidBothAirflowAndRuntimeVersionsUpgrade = errors.New("you provided both a runtime version and an Airflow version. You have to provide only one of these to upgrade")                 //nolint
 	errInvalidBothCustomImageandVersion            = errors.New("you provided both a Custom image and a version. You have to provide only one of these to upgrade")                             //nolint
+	errInvalidBothWatchAndCustomImage              = errors.New("you provided both a custom image and the --watch flag. Watching requires the project image to be built by the CLI")            //nolint
 
 	errConfigProjectName               = errors.New("project name is invalid")
 	errConfigProjectNameSpecifiedTwice = errors.New("project name cannot be set with the --name flag and positional argument, please choose one")
@@ -75,7 +75,7 @@ Welcome to the Astro CLI, the modern command line interface for data orchestrati
 		newLoginCommand(astroCoreClient, platformCoreClient, os.Stdout),
 		newLogoutCommand(os.Stdout),
 		newVersionCommand(),
-		newDevRootCmd(platformCoreClient, astroCoreClient),
+		newDevRootCmd(platformCoreClient, astroCoreClient, airflowClient),
 		newContextCmd(os.Stdout),
 		newConfigRootCmd(os.Stdout),
 		newRunCommand(),
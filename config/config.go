@@ -66,6 +66,15 @@ var (
 		PostgresPort:          newCfg("postgres.port", "5432"),
 		PostgresRepository:    newCfg("postgres.repository", "docker.io/postgres"),
 		PostgresTag:           newCfg("postgres.tag", "12.6"),
+		VaultPort:             newCfg("vault.port", "8200"),
+		SettingsEncryptionKey: newCfg("settings.encryption_key", ""),
+		DevExecutor:           newCfg("dev.executor", "local"),
+		KubernetesProvider:    newCfg("dev.kubernetes_provider", "kind"),
+		DevCeleryQueues:       newCfg("dev.celery_queues", "default"),
+		DevSchedulerCount:     newCfg("dev.scheduler_count", "1"),
+		DeployTargets:         newCfg("deploy.targets", ""),
+		DeployPreHooks:        newCfg("deploy.pre_hooks", ""),
+		DeployPostHooks:       newCfg("deploy.post_hooks", ""),
 		ProjectDeployment:     newCfg("project.deployment", ""),
 		ProjectName:           newCfg("project.name", ""),
 		ProjectWorkspace:      newCfg("project.workspace", ""),
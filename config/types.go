@@ -1,5 +1,7 @@
 package config
 
+import "strings"
+
 // cfg defines settings a single configuration setting can have
 type cfg struct {
 	Path    string
@@ -28,6 +30,15 @@ type cfgs struct {
 	PostgresPort          cfg
 	PostgresRepository    cfg
 	PostgresTag           cfg
+	VaultPort             cfg
+	SettingsEncryptionKey cfg
+	DevExecutor           cfg
+	KubernetesProvider    cfg
+	DevCeleryQueues       cfg
+	DevSchedulerCount     cfg
+	DeployTargets         cfg
+	DeployPreHooks        cfg
+	DeployPostHooks       cfg
 	ProjectName           cfg
 	ProjectDeployment     cfg
 	ProjectWorkspace      cfg
@@ -109,6 +120,41 @@ func (c cfg) GetInt() int {
 	return viperHome.GetInt(c.Path)
 }
 
+// GetStringSlice will return the list value of requested config, check working dir and fallback to home.
+// A string value, like the ones set by 'astro config set', is split on commas
+func (c cfg) GetStringSlice() []string {
+	v := viperHome
+	if configExists(viperProject) && viperProject.IsSet(c.Path) {
+		v = viperProject
+	}
+	value, ok := v.Get(c.Path).(string)
+	if !ok {
+		return v.GetStringSlice(c.Path)
+	}
+	values := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// GetProjectStringSlice will return the list value of a project config. A string value is a single item, since
+// the items may contain commas, like shell commands
+func (c cfg) GetProjectStringSlice() []string {
+	if !configExists(viperProject) || !viperProject.IsSet(c.Path) {
+		return []string{}
+	}
+	if value, ok := viperProject.Get(c.Path).(string); ok {
+		if strings.TrimSpace(value) == "" {
+			return []string{}
+		}
+		return []string{value}
+	}
+	return viperProject.GetStringSlice(c.Path)
+}
+
 // GetProjectString will return a project config
 func (c cfg) GetProjectString() string {
 	return viperProject.GetString(c.Path)
@@ -50,3 +50,29 @@ func (s *Suite) TestGetBool() {
 	val = cfg.GetBool()
 	s.Equal(false, val)
 }
+
+func (s *Suite) TestGetStringSlice() {
+	initTestConfig()
+	cfg := newCfg("foo", "")
+	cfg.SetHomeString("a, b,,c")
+	s.Equal([]string{"a", "b", "c"}, cfg.GetStringSlice())
+
+	viperProject.SetConfigFile("test.yaml")
+	defer os.Remove("test.yaml")
+	viperProject.Set("foo", []string{"d", "e"})
+	s.Equal([]string{"d", "e"}, cfg.GetStringSlice())
+}
+
+func (s *Suite) TestGetProjectStringSlice() {
+	initTestConfig()
+	cfg := newCfg("foo", "")
+	cfg.SetHomeString("a")
+	s.Equal([]string{}, cfg.GetProjectStringSlice())
+
+	viperProject.SetConfigFile("test.yaml")
+	defer os.Remove("test.yaml")
+	viperProject.Set("foo", "echo a, b")
+	s.Equal([]string{"echo a, b"}, cfg.GetProjectStringSlice())
+	viperProject.Set("foo", []string{"echo a", "echo b"})
+	s.Equal([]string{"echo a", "echo b"}, cfg.GetProjectStringSlice())
+}
@@ -1,32 +1,290 @@
 package azure
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/astronomer/astro-cli/pkg/logger"
+	"github.com/astronomer/astro-cli/pkg/spinner"
+	"github.com/docker/go-units"
+	"github.com/sirupsen/logrus"
 )
 
-var azureUploader = Upload
+// SHA256MetadataKey is the metadata of an uploaded blob holding the SHA-256 of the file, which the uploaded content
+// is checked against
+const SHA256MetadataKey = "sha256"
+
+const (
+	// uploadBlockSize is the size of the blocks a file is uploaded in
+	uploadBlockSize = 4 * 1024 * 1024
+	// uploadBlockRetries is the number of times the upload of a block is retried
+	uploadBlockRetries = 5
+)
+
+var (
+	errUploadVerificationFailed = errors.New("the uploaded blob does not match the uploaded file")
+
+	azureUploader = Upload
+
+	// Monkey patched to write unit tests
+	newBlockBlobClient = func(sasLink string) (blockBlobClient, error) {
+		return azblob.NewBlockBlobClientWithNoCredential(sasLink, nil)
+	}
+	uploadRetryBackoff = time.Second
+)
 
 type Azure interface {
-	Upload(sasLink string, dagFileReader io.Reader) (string, error)
+	Upload(sasLink string, dagFileReader io.Reader, metadata map[string]string) (string, error)
+}
+
+// blockBlobClient is the part of the Azure block blob client used by the uploads
+type blockBlobClient interface {
+	StageBlock(ctx context.Context, base64BlockID string, body io.ReadSeekCloser, options *azblob.BlockBlobStageBlockOptions) (azblob.BlockBlobStageBlockResponse, error)
+	CommitBlockList(ctx context.Context, base64BlockIDs []string, options *azblob.BlockBlobCommitBlockListOptions) (azblob.BlockBlobCommitBlockListResponse, error)
+	GetBlockList(ctx context.Context, listType azblob.BlockListType, options *azblob.BlockBlobGetBlockListOptions) (azblob.BlockBlobGetBlockListResponse, error)
+	GetProperties(ctx context.Context, options *azblob.BlobGetPropertiesOptions) (azblob.BlobGetPropertiesResponse, error)
 }
 
-func azureUpload(sasLink string, dagFileReader io.Reader) (string, error) {
-	return azureUploader(sasLink, dagFileReader)
+func azureUpload(sasLink string, dagFileReader io.Reader, metadata map[string]string) (string, error) {
+	return azureUploader(sasLink, dagFileReader, metadata)
 }
 
-func Upload(sasLink string, dagFileReader io.Reader) (string, error) {
-	blobClient, err := azblob.NewBlockBlobClientWithNoCredential(sasLink, nil)
+// Upload uploads a file to the blob of a SAS link, with the metadata attached to the blob. The file is uploaded in
+// blocks, each retried on failure and checked by Azure against its MD5. When the upload is retried, the blocks staged to
+// the blob by the failed attempt are not uploaded again if the SAS link allows listing them. The SHA-256 of the uploaded
+// content is checked against the sha256 metadata when set, and the committed blob against the file before returning:
+// its blocks, size, MD5 and metadata, unless the SAS link does not allow reading them.
+func Upload(sasLink string, dagFileReader io.Reader, metadata map[string]string) (string, error) {
+	blobClient, err := newBlockBlobClient(sasLink)
 	if err != nil {
 		return "", err
 	}
-	uploadRes, err := blobClient.UploadStream(context.TODO(), dagFileReader, azblob.UploadStreamOptions{})
+	return uploadBlocks(context.TODO(), blobClient, dagFileReader, readerSize(dagFileReader), metadata)
+}
+
+func uploadBlocks(ctx context.Context, blobClient blockBlobClient, reader io.Reader, size int64, metadata map[string]string) (string, error) {
+	staged := stagedBlocks(ctx, blobClient)
+
+	s := spinner.NewSpinner(uploadProgressMsg(0, size))
+	if !logger.IsLevelEnabled(logrus.DebugLevel) {
+		s.Start()
+		defer s.Stop()
+	}
+
+	fileMD5 := md5.New() //nolint:gosec
+	fileSHA256 := sha256.New()
+	var uploaded int64
+	blockIDs := []string{}
+	blockSizes := map[string]int64{}
+	buf := make([]byte, uploadBlockSize)
+	for {
+		n, err := io.ReadFull(reader, buf)
+		if n > 0 {
+			block := buf[:n]
+			fileMD5.Write(block)
+			fileSHA256.Write(block)
+			sum := md5.Sum(block) //nolint:gosec
+			// the ID of a block identifies its content, so a block staged by a previous attempt can be reused
+			blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d-%x", len(blockIDs), sum)))
+			if stagedSize, ok := staged[blockID]; !ok || stagedSize != int64(n) {
+				stageErr := retryBlock(func() error {
+					_, err := blobClient.StageBlock(ctx, blockID, nopCloser{bytes.NewReader(block)}, &azblob.BlockBlobStageBlockOptions{TransactionalContentMD5: sum[:]})
+					return err
+				})
+				if stageErr != nil {
+					return "", stageErr
+				}
+			}
+			blockIDs = append(blockIDs, blockID)
+			blockSizes[blockID] = int64(n)
+			uploaded += int64(n)
+			s.Lock()
+			s.Suffix = " " + uploadProgressMsg(uploaded, size)
+			s.Unlock()
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// the file may have changed since its SHA-256 was computed
+	if checksum, ok := metadata[SHA256MetadataKey]; ok && checksum != hex.EncodeToString(fileSHA256.Sum(nil)) {
+		return "", errUploadVerificationFailed
+	}
+
+	contentMD5 := fileMD5.Sum(nil)
+	var commitRes azblob.BlockBlobCommitBlockListResponse
+	err := retryBlock(func() error {
+		var err error
+		commitRes, err = blobClient.CommitBlockList(ctx, blockIDs, &azblob.BlockBlobCommitBlockListOptions{
+			Metadata:        metadata,
+			BlobHTTPHeaders: &azblob.BlobHTTPHeaders{BlobContentMD5: contentMD5},
+		})
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
-	versionID := *uploadRes.VersionID
 
-	return versionID, nil
+	err = verifyBlocks(ctx, blobClient, blockIDs, blockSizes)
+	if err == nil {
+		err = verifyBlob(ctx, blobClient, uploaded, contentMD5, metadata)
+	}
+	// a write-only SAS link can't read the blob back, the blocks were already checked against their MD5
+	if isForbidden(err) {
+		logger.Warnf("Skipping the verification of the uploaded blob, the upload URL does not allow reading it: %s", err)
+	} else if err != nil {
+		return "", err
+	}
+	spinner.StopWithCheckmark(s, "Uploaded "+units.HumanSize(float64(uploaded)))
+
+	if commitRes.VersionID == nil {
+		return "", nil
+	}
+	return *commitRes.VersionID, nil
+}
+
+// stagedBlocks returns the sizes of the blocks staged but not committed yet to a blob, keyed by their ID. A blob that
+// does not exist yet has no blocks.
+func stagedBlocks(ctx context.Context, blobClient blockBlobClient) map[string]int64 {
+	staged := map[string]int64{}
+	resp, err := blobClient.GetBlockList(ctx, azblob.BlockListTypeUncommitted, nil)
+	if err != nil {
+		return staged
+	}
+	for _, block := range resp.UncommittedBlocks {
+		if block.Name != nil && block.Size != nil {
+			staged[*block.Name] = *block.Size
+		}
+	}
+	return staged
+}
+
+// verifyBlocks checks the committed blocks of a blob are the blocks of the file, in order
+func verifyBlocks(ctx context.Context, blobClient blockBlobClient, blockIDs []string, blockSizes map[string]int64) error {
+	resp, err := blobClient.GetBlockList(ctx, azblob.BlockListTypeCommitted, nil)
+	if err != nil {
+		return err
+	}
+	if len(resp.CommittedBlocks) != len(blockIDs) {
+		return errUploadVerificationFailed
+	}
+	for i, block := range resp.CommittedBlocks {
+		if block.Name == nil || *block.Name != blockIDs[i] || block.Size == nil || *block.Size != blockSizes[blockIDs[i]] {
+			return errUploadVerificationFailed
+		}
+	}
+	return nil
+}
+
+// verifyBlob checks the properties of the committed blob are the size, the MD5 and the metadata of the file
+func verifyBlob(ctx context.Context, blobClient blockBlobClient, size int64, contentMD5 []byte, metadata map[string]string) error {
+	props, err := blobClient.GetProperties(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if props.ContentLength == nil || *props.ContentLength != size || !bytes.Equal(props.ContentMD5, contentMD5) {
+		return errUploadVerificationFailed
+	}
+	for key, value := range metadata {
+		if blobValue, ok := metadataValue(props.Metadata, key); !ok || blobValue != value {
+			return errUploadVerificationFailed
+		}
+	}
+	return nil
+}
+
+// metadataValue returns the value of a metadata of a blob, whose keys come back in the canonical form of HTTP headers
+func metadataValue(metadata map[string]string, key string) (string, bool) {
+	for blobKey, blobValue := range metadata {
+		if strings.EqualFold(blobKey, key) {
+			return blobValue, true
+		}
+	}
+	return "", false
+}
+
+// BlobChecksum returns the version of the blob of a SAS link and the SHA-256 attached to it by Upload, empty when the
+// blob was not uploaded with one
+func BlobChecksum(sasLink string) (versionID, checksum string, err error) {
+	blobClient, err := newBlockBlobClient(sasLink)
+	if err != nil {
+		return "", "", err
+	}
+	props, err := blobClient.GetProperties(context.TODO(), nil)
+	if err != nil {
+		return "", "", err
+	}
+	if props.VersionID != nil {
+		versionID = *props.VersionID
+	}
+	checksum, _ = metadataValue(props.Metadata, SHA256MetadataKey)
+	return versionID, checksum, nil
+}
+
+// isForbidden tells whether a request failed because the SAS link does not allow it
+func isForbidden(err error) bool {
+	var statusErr interface{ StatusCode() int }
+	return errors.As(err, &statusErr) && statusErr.StatusCode() == http.StatusForbidden
+}
+
+// retryBlock retries an upload request with an exponential backoff
+func retryBlock(request func() error) error {
+	var err error
+	backoff := uploadRetryBackoff
+	for attempt := 1; attempt <= uploadBlockRetries; attempt++ {
+		err = request()
+		if err == nil {
+			return nil
+		}
+		if attempt < uploadBlockRetries {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return err
+}
+
+// readerSize returns the size of a file, or 0 when the reader is not a file
+func readerSize(reader io.Reader) int64 {
+	file, ok := reader.(*os.File)
+	if !ok {
+		return 0
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// uploadProgressMsg describes the progress of an upload, total being 0 when the size of the file is unknown
+func uploadProgressMsg(uploaded, total int64) string {
+	if total <= 0 {
+		return "Uploading " + units.HumanSize(float64(uploaded))
+	}
+	return fmt.Sprintf("Uploading %s/%s (%d%%)", units.HumanSize(float64(uploaded)), units.HumanSize(float64(total)), uploaded*100/total) //nolint:mnd
 }
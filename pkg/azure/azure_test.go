@@ -1,11 +1,19 @@
 package azure
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"io"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -21,20 +29,245 @@ func TestAzure(t *testing.T) {
 
 func (s *Suite) TestUpload() {
 	s.Run("happy path", func() {
-		azureUploader = func(sasLink string, file io.Reader) (string, error) {
+		azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
 			return "version-id", nil
 		}
 
-		resp, err := azureUpload("test-url", io.Reader(strings.NewReader("abcde")))
+		resp, err := azureUpload("test-url", io.Reader(strings.NewReader("abcde")), nil)
 		s.NoError(err)
 		s.Equal("version-id", resp)
 	})
 	s.Run("error path", func() {
-		azureUploader = func(sasLink string, file io.Reader) (string, error) {
+		azureUploader = func(sasLink string, file io.Reader, metadata map[string]string) (string, error) {
 			return "", errMock
 		}
 
-		_, err := azureUpload("test-url", io.Reader(strings.NewReader("abcde")))
+		_, err := azureUpload("test-url", io.Reader(strings.NewReader("abcde")), nil)
 		s.ErrorIs(err, errMock)
 	})
 }
+
+// fakeBlockBlobClient keeps the staged and committed blocks of a blob in memory
+type fakeBlockBlobClient struct {
+	staged        map[string][]byte
+	committed     []string
+	metadata      map[string]string
+	contentMD5    []byte
+	stageCalls    int
+	stageFailures int
+	// stageLimit is the number of blocks staged before all the following stages fail, when positive
+	stageLimit   int
+	dropOnCommit bool
+	// corruptOnCommit changes the MD5 of the blob, like a blob overwritten after the upload
+	corruptOnCommit bool
+	// writeOnly fails the reads of the blob, like a SAS link only allowing writes
+	writeOnly bool
+}
+
+// statusError is a failed request to Azure, like the storage errors of azblob
+type statusError int
+
+func (e statusError) Error() string {
+	return http.StatusText(int(e))
+}
+
+func (e statusError) StatusCode() int {
+	return int(e)
+}
+
+func newFakeBlockBlobClient() *fakeBlockBlobClient {
+	return &fakeBlockBlobClient{staged: map[string][]byte{}}
+}
+
+func (c *fakeBlockBlobClient) StageBlock(ctx context.Context, base64BlockID string, body io.ReadSeekCloser, options *azblob.BlockBlobStageBlockOptions) (azblob.BlockBlobStageBlockResponse, error) {
+	c.stageCalls++
+	if c.stageFailures > 0 || c.stageLimit > 0 && len(c.staged) >= c.stageLimit {
+		c.stageFailures--
+		return azblob.BlockBlobStageBlockResponse{}, errMock
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return azblob.BlockBlobStageBlockResponse{}, err
+	}
+	if sum := md5.Sum(data); !bytes.Equal(sum[:], options.TransactionalContentMD5) { //nolint:gosec
+		return azblob.BlockBlobStageBlockResponse{}, errors.New("md5 mismatch")
+	}
+	c.staged[base64BlockID] = data
+	return azblob.BlockBlobStageBlockResponse{}, nil
+}
+
+func (c *fakeBlockBlobClient) CommitBlockList(ctx context.Context, base64BlockIDs []string, options *azblob.BlockBlobCommitBlockListOptions) (azblob.BlockBlobCommitBlockListResponse, error) {
+	c.committed = base64BlockIDs
+	if c.dropOnCommit {
+		c.committed = base64BlockIDs[1:]
+	}
+	c.metadata = options.Metadata
+	c.contentMD5 = options.BlobHTTPHeaders.BlobContentMD5
+	if c.corruptOnCommit {
+		sum := md5.Sum([]byte("other")) //nolint:gosec
+		c.contentMD5 = sum[:]
+	}
+	versionID := "version-id"
+	resp := azblob.BlockBlobCommitBlockListResponse{}
+	resp.VersionID = &versionID
+	return resp, nil
+}
+
+func (c *fakeBlockBlobClient) GetBlockList(ctx context.Context, listType azblob.BlockListType, options *azblob.BlockBlobGetBlockListOptions) (azblob.BlockBlobGetBlockListResponse, error) {
+	if c.writeOnly {
+		return azblob.BlockBlobGetBlockListResponse{}, statusError(http.StatusForbidden)
+	}
+	blockList := azblob.BlockList{}
+	if listType == azblob.BlockListTypeCommitted {
+		for _, id := range c.committed {
+			name, size := id, int64(len(c.staged[id]))
+			blockList.CommittedBlocks = append(blockList.CommittedBlocks, &azblob.Block{Name: &name, Size: &size})
+		}
+	} else {
+		for id, data := range c.staged {
+			name, size := id, int64(len(data))
+			blockList.UncommittedBlocks = append(blockList.UncommittedBlocks, &azblob.Block{Name: &name, Size: &size})
+		}
+	}
+	resp := azblob.BlockBlobGetBlockListResponse{}
+	resp.BlockList = blockList
+	return resp, nil
+}
+
+func (c *fakeBlockBlobClient) GetProperties(ctx context.Context, options *azblob.BlobGetPropertiesOptions) (azblob.BlobGetPropertiesResponse, error) {
+	if c.writeOnly {
+		return azblob.BlobGetPropertiesResponse{}, statusError(http.StatusForbidden)
+	}
+	var size int64
+	for _, id := range c.committed {
+		size += int64(len(c.staged[id]))
+	}
+	// Azure returns the metadata keys in the canonical form of HTTP headers
+	metadata := map[string]string{}
+	for key, value := range c.metadata {
+		metadata[http.CanonicalHeaderKey(key)] = value
+	}
+	resp := azblob.BlobGetPropertiesResponse{}
+	resp.ContentLength = &size
+	resp.ContentMD5 = c.contentMD5
+	resp.Metadata = metadata
+	if len(c.committed) > 0 {
+		versionID := "version-id"
+		resp.VersionID = &versionID
+	}
+	return resp, nil
+}
+
+func (s *Suite) TestBlobChecksum() {
+	client := newFakeBlockBlobClient()
+	newBlockBlobClient = func(sasLink string) (blockBlobClient, error) {
+		return client, nil
+	}
+	defer func() {
+		newBlockBlobClient = func(sasLink string) (blockBlobClient, error) {
+			return azblob.NewBlockBlobClientWithNoCredential(sasLink, nil)
+		}
+	}()
+
+	versionID, checksum, err := BlobChecksum("test-url")
+	s.NoError(err)
+	s.Empty(versionID)
+	s.Empty(checksum)
+
+	_, err = Upload("test-url", strings.NewReader("abcde"), map[string]string{SHA256MetadataKey: "36bbe50ed96841d10443bcb670d6554f0a34b761be67ec9c4a8ad2c0c44ca42c"})
+	s.NoError(err)
+	versionID, checksum, err = BlobChecksum("test-url")
+	s.NoError(err)
+	s.Equal("version-id", versionID)
+	s.Equal("36bbe50ed96841d10443bcb670d6554f0a34b761be67ec9c4a8ad2c0c44ca42c", checksum)
+}
+
+func (s *Suite) TestUploadBlocks() {
+	uploadRetryBackoff = 0
+	defer func() { uploadRetryBackoff = time.Second }()
+	data := bytes.Repeat([]byte("0123456789"), uploadBlockSize/4)
+	checksum := sha256.Sum256(data)
+	metadata := map[string]string{SHA256MetadataKey: hex.EncodeToString(checksum[:])}
+
+	s.Run("uploads the file in blocks", func() {
+		client := newFakeBlockBlobClient()
+		versionID, err := uploadBlocks(context.Background(), client, bytes.NewReader(data), int64(len(data)), metadata)
+		s.NoError(err)
+		s.Equal("version-id", versionID)
+		s.Len(client.committed, 3)
+		s.Equal(3, client.stageCalls)
+		s.Equal(metadata, client.metadata)
+
+		uploaded := []byte{}
+		for _, id := range client.committed {
+			uploaded = append(uploaded, client.staged[id]...)
+		}
+		s.Equal(data, uploaded)
+	})
+
+	s.Run("retries a failed block", func() {
+		client := newFakeBlockBlobClient()
+		client.stageFailures = 2
+		_, err := uploadBlocks(context.Background(), client, bytes.NewReader(data), int64(len(data)), metadata)
+		s.NoError(err)
+		s.Equal(5, client.stageCalls)
+	})
+
+	s.Run("fails after the retries of a block", func() {
+		client := newFakeBlockBlobClient()
+		client.stageFailures = uploadBlockRetries
+		_, err := uploadBlocks(context.Background(), client, bytes.NewReader(data), int64(len(data)), metadata)
+		s.ErrorIs(err, errMock)
+		s.Empty(client.committed)
+	})
+
+	s.Run("retries an upload without staging the staged blocks again", func() {
+		client := newFakeBlockBlobClient()
+		client.stageLimit = 2
+		_, err := uploadBlocks(context.Background(), client, bytes.NewReader(data), int64(len(data)), metadata)
+		s.ErrorIs(err, errMock)
+		s.Len(client.staged, 2)
+
+		client.stageLimit = 0
+		client.stageCalls = 0
+		_, err = uploadBlocks(context.Background(), client, bytes.NewReader(data), int64(len(data)), metadata)
+		s.NoError(err)
+		s.Equal(1, client.stageCalls)
+		s.Len(client.committed, 3)
+	})
+
+	s.Run("verifies the committed blocks", func() {
+		client := newFakeBlockBlobClient()
+		client.dropOnCommit = true
+		_, err := uploadBlocks(context.Background(), client, bytes.NewReader(data), int64(len(data)), metadata)
+		s.ErrorIs(err, errUploadVerificationFailed)
+	})
+
+	s.Run("verifies the committed blob", func() {
+		client := newFakeBlockBlobClient()
+		client.corruptOnCommit = true
+		_, err := uploadBlocks(context.Background(), client, bytes.NewReader(data), int64(len(data)), metadata)
+		s.ErrorIs(err, errUploadVerificationFailed)
+	})
+
+	s.Run("skips the verification with a write-only upload URL", func() {
+		client := newFakeBlockBlobClient()
+		client.writeOnly = true
+		versionID, err := uploadBlocks(context.Background(), client, bytes.NewReader(data), int64(len(data)), metadata)
+		s.NoError(err)
+		s.Equal("version-id", versionID)
+		s.Len(client.committed, 3)
+	})
+
+	s.Run("fails when the file does not match its SHA-256", func() {
+		client := newFakeBlockBlobClient()
+		_, err := uploadBlocks(context.Background(), client, bytes.NewReader(data), int64(len(data)), map[string]string{SHA256MetadataKey: "other-sha"})
+		s.ErrorIs(err, errUploadVerificationFailed)
+		s.Empty(client.committed)
+	})
+}
+
+func (s *Suite) TestUploadProgressMsg() {
+	s.Equal("Uploading 2MB/4MB (50%)", uploadProgressMsg(2000000, 4000000))
+	s.Equal("Uploading 2MB", uploadProgressMsg(2000000, 0))
+}
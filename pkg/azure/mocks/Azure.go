@@ -13,9 +13,9 @@ type Azure struct {
 	mock.Mock
 }
 
-// Upload provides a mock function with given fields: sasLink, dagFileReader
-func (_m *Azure) Upload(sasLink string, dagFileReader io.Reader) (string, error) {
-	ret := _m.Called(sasLink, dagFileReader)
+// Upload provides a mock function with given fields: sasLink, dagFileReader, metadata
+func (_m *Azure) Upload(sasLink string, dagFileReader io.Reader, metadata map[string]string) (string, error) {
+	ret := _m.Called(sasLink, dagFileReader, metadata)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Upload")
@@ -23,17 +23,17 @@ func (_m *Azure) Upload(sasLink string, dagFileReader io.Reader) (string, error)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, io.Reader) (string, error)); ok {
-		return rf(sasLink, dagFileReader)
+	if rf, ok := ret.Get(0).(func(string, io.Reader, map[string]string) (string, error)); ok {
+		return rf(sasLink, dagFileReader, metadata)
 	}
-	if rf, ok := ret.Get(0).(func(string, io.Reader) string); ok {
-		r0 = rf(sasLink, dagFileReader)
+	if rf, ok := ret.Get(0).(func(string, io.Reader, map[string]string) string); ok {
+		r0 = rf(sasLink, dagFileReader, metadata)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, io.Reader) error); ok {
-		r1 = rf(sasLink, dagFileReader)
+	if rf, ok := ret.Get(1).(func(string, io.Reader, map[string]string) error); ok {
+		r1 = rf(sasLink, dagFileReader, metadata)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -97,26 +97,50 @@ func WriteToFile(path string, r io.Reader) error {
 	return err
 }
 
+// Tar archives the files of the source directory to the target tar file. The archive is reproducible: filepath.Walk
+// adds the files in lexical order, and the times, ownership and modes of the files are normalized.
 func Tar(source, target string, prependBaseDir bool, excludePathPrefixes []string) error {
+	return TarWithIgnore(source, target, prependBaseDir, excludePathPrefixes, nil)
+}
+
+// TarWithIgnore is like Tar, also leaving out the paths of the tar file matched by ignore
+func TarWithIgnore(source, target string, prependBaseDir bool, excludePathPrefixes []string, ignore *IgnoreMatcher) error {
 	tarfile, err := os.Create(target)
 	if err != nil {
 		return err
 	}
 	defer tarfile.Close()
 
-	tarball := tar.NewWriter(tarfile)
-	defer tarball.Close()
+	files, err := TarFiles(source, target, prependBaseDir, excludePathPrefixes, ignore)
+	if err != nil {
+		return err
+	}
+	return WriteTar(tarfile, files)
+}
 
+// TarFile is a file of a directory included in its tarball
+type TarFile struct {
+	// Path is the path of the file on disk
+	Path string
+	// Name is the path of the file in the tarball
+	Name string
+	Info os.FileInfo
+}
+
+// TarFiles returns the files TarWithIgnore writes to the tarball of a directory, in order. A source directory which
+// does not exist has no files.
+func TarFiles(source, target string, prependBaseDir bool, excludePathPrefixes []string, ignore *IgnoreMatcher) ([]TarFile, error) {
 	sourceInfo, err := os.Stat(source)
 	if err != nil {
-		return nil
+		return nil, nil
 	}
 
 	if !sourceInfo.IsDir() {
-		return errors.New("source is not a directory")
+		return nil, errors.New("source is not a directory")
 	}
 
-	return filepath.Walk(source,
+	files := []TarFile{}
+	err = filepath.Walk(source,
 		func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return err
@@ -130,18 +154,6 @@ func Tar(source, target string, prependBaseDir bool, excludePathPrefixes []strin
 				return nil
 			}
 
-			var link string
-			if info.Mode()&os.ModeSymlink == os.ModeSymlink {
-				if link, err = os.Readlink(path); err != nil {
-					return err
-				}
-			}
-
-			header, err := tar.FileInfoHeader(info, link)
-			if err != nil {
-				return err
-			}
-
 			// set the tar file path to be relative to the source directory
 			headerName := strings.TrimPrefix(path, filepath.Clean(source))
 			headerName = strings.TrimPrefix(headerName, string(filepath.Separator))
@@ -160,26 +172,84 @@ func Tar(source, target string, prependBaseDir bool, excludePathPrefixes []strin
 					return nil
 				}
 			}
-
-			header.Name = headerName
-			logger.Debugf("Adding to tarball: %s", header.Name)
-
-			if err := tarball.WriteHeader(header); err != nil {
+			ignored, err := ignore.Matches(headerName)
+			if err != nil {
 				return err
 			}
-
-			if !info.Mode().IsRegular() { // nothing more to do for non-regular
+			if ignored {
+				logger.Debugf("Ignoring tarball path: %s", headerName)
 				return nil
 			}
 
-			file, err := os.Open(path)
-			if err != nil {
+			files = append(files, TarFile{Path: path, Name: headerName, Info: info})
+			return nil
+		})
+	return files, err
+}
+
+// WriteTar writes the tarball of the files returned by TarFiles
+func WriteTar(w io.Writer, files []TarFile) error {
+	tarball := tar.NewWriter(w)
+	for _, file := range files {
+		var link string
+		if file.Info.Mode()&os.ModeSymlink == os.ModeSymlink {
+			var err error
+			if link, err = os.Readlink(file.Path); err != nil {
 				return err
 			}
-			defer file.Close()
-			_, err = io.Copy(tarball, file)
+		}
+
+		header, err := tar.FileInfoHeader(file.Info, link)
+		if err != nil {
 			return err
-		})
+		}
+		header.Name = file.Name
+		normalizeTarHeader(header)
+		logger.Debugf("Adding to tarball: %s", header.Name)
+
+		if err := tarball.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !file.Info.Mode().IsRegular() { // nothing more to do for non-regular
+			continue
+		}
+
+		if err := copyFileTo(tarball, file.Path); err != nil {
+			return err
+		}
+	}
+	return tarball.Close()
+}
+
+func copyFileTo(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// normalizeTarHeader drops the metadata of a tar header that depends on the machine the tarball is built on, so
+// identical directories give identical tarballs
+func normalizeTarHeader(header *tar.Header) {
+	header.ModTime = time.Unix(0, 0)
+	header.AccessTime = time.Time{}
+	header.ChangeTime = time.Time{}
+	header.Uid = 0
+	header.Gid = 0
+	header.Uname = ""
+	header.Gname = ""
+	switch {
+	case header.Typeflag == tar.TypeSymlink:
+		header.Mode = 0o777
+	case header.Mode&0o111 != 0:
+		header.Mode = 0o755
+	default:
+		header.Mode = 0o644
+	}
 }
 
 // this functions reads a whole file into memory and returns a slice of its lines.
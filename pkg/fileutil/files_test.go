@@ -13,6 +13,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
@@ -260,6 +261,51 @@ func (s *Suite) TestTar() {
 	}
 }
 
+func (s *Suite) TestTarReproducible() {
+	sourceDirPath := s.T().TempDir()
+	s.Require().NoError(os.MkdirAll(filepath.Join(sourceDirPath, "subdir"), os.ModePerm))
+	s.Require().NoError(os.WriteFile(filepath.Join(sourceDirPath, "b.py"), []byte("b"), 0o600))
+	s.Require().NoError(os.WriteFile(filepath.Join(sourceDirPath, "a.sh"), []byte("a"), 0o700))
+	s.Require().NoError(os.WriteFile(filepath.Join(sourceDirPath, "subdir", "c.py"), []byte("c"), 0o666))
+
+	firstTarPath := filepath.Join(s.T().TempDir(), "first.tar")
+	s.Require().NoError(Tar(sourceDirPath, firstTarPath, true, nil))
+
+	// touching the files must not change the tarball
+	modTime := time.Now().Add(time.Hour)
+	for _, name := range []string{"b.py", "a.sh", filepath.Join("subdir", "c.py")} {
+		s.Require().NoError(os.Chtimes(filepath.Join(sourceDirPath, name), modTime, modTime))
+	}
+	secondTarPath := filepath.Join(s.T().TempDir(), "second.tar")
+	s.Require().NoError(Tar(sourceDirPath, secondTarPath, true, nil))
+
+	first, err := os.ReadFile(firstTarPath)
+	s.Require().NoError(err)
+	second, err := os.ReadFile(secondTarPath)
+	s.Require().NoError(err)
+	s.Equal(first, second)
+
+	baseDir := filepath.Base(sourceDirPath)
+	modes := map[string]int64{}
+	tarReader := tar.NewReader(bytes.NewReader(first))
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		s.Require().NoError(err)
+		s.Equal(time.Unix(0, 0), header.ModTime)
+		s.Equal(0, header.Uid)
+		s.Equal("", header.Uname)
+		modes[header.Name] = header.Mode
+	}
+	s.Equal(map[string]int64{
+		baseDir + "/a.sh":        0o755,
+		baseDir + "/b.py":        0o644,
+		baseDir + "/subdir/c.py": 0o644,
+	}, modes)
+}
+
 func (s *Suite) TestContains() {
 	type args struct {
 		elems []string
@@ -875,3 +921,36 @@ func (s *Suite) TestUploadFile() {
 		s.NotContains(string(reqBody), "description")
 	})
 }
+
+func (s *Suite) TestIgnoreMatcher() {
+	ignoreFilePath := filepath.Join(s.T().TempDir(), AstroIgnoreFileName)
+	s.NoError(os.WriteFile(ignoreFilePath, []byte("# comment\n\n*.ipynb\n/data\ndags/tests/\n!dags/tests/keep.py\ncache/\n"), 0o600))
+
+	ignore, err := NewIgnoreMatcher(ignoreFilePath)
+	s.NoError(err)
+	for path, expected := range map[string]bool{
+		"notebook.ipynb":        true,
+		"dags/nested/x.ipynb":   true,
+		"data/raw.csv":          true,
+		"dags/data/raw.csv":     false,
+		"dags/tests/test_a.py":  true,
+		"dags/tests/keep.py":    false,
+		"dags/my_dag.py":        false,
+		"tests/not_anchored.py": false,
+		"cache/x.pyc":           true,
+		"dags/cache/x.pyc":      true,
+		"dags/cache":            false,
+		"cache":                 false,
+	} {
+		matches, err := ignore.Matches(path)
+		s.NoError(err)
+		s.Equal(expected, matches, path)
+	}
+
+	ignore, err = NewIgnoreMatcher(filepath.Join(s.T().TempDir(), AstroIgnoreFileName))
+	s.NoError(err)
+	s.Nil(ignore)
+	matches, err := ignore.Matches("dags/my_dag.py")
+	s.NoError(err)
+	s.False(matches)
+}
@@ -0,0 +1,115 @@
+package fileutil
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/moby/patternmatcher"
+	"github.com/pkg/errors"
+)
+
+// AstroIgnoreFileName is the name of the file listing the paths to leave out of the DAG and bundle tarballs
+const AstroIgnoreFileName = ".astroignore"
+
+// IgnoreMatcher matches paths against the patterns of an ignore file, like .gitignore does: a pattern without a
+// slash matches in any directory, a leading slash anchors a pattern to the directory of the ignore file, a trailing
+// slash matches a directory and everything under it, and a leading ! re-includes the paths a previous pattern excluded.
+// A nil IgnoreMatcher matches nothing.
+type IgnoreMatcher struct {
+	patterns []ignorePattern
+}
+
+// ignorePattern is a pattern of an ignore file
+type ignorePattern struct {
+	pm        *patternmatcher.PatternMatcher
+	exclusion bool
+	// dirOnly patterns end with a slash and only match directories
+	dirOnly bool
+}
+
+// NewIgnoreMatcher reads the patterns of an ignore file. It returns a nil IgnoreMatcher when the file does not exist.
+func NewIgnoreMatcher(ignoreFilePath string) (*IgnoreMatcher, error) {
+	f, err := os.Open(ignoreFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	matcher := &IgnoreMatcher{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		pattern, exclusion, dirOnly := toIgnorePattern(scanner.Text())
+		if pattern == "" {
+			continue
+		}
+		pm, err := patternmatcher.New([]string{pattern})
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern in %s", ignoreFilePath)
+		}
+		matcher.patterns = append(matcher.patterns, ignorePattern{pm: pm, exclusion: exclusion, dirOnly: dirOnly})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return matcher, nil
+}
+
+// toIgnorePattern converts a line of an ignore file to a patternmatcher pattern, returning an empty string for blank
+// lines and comments, and whether the pattern re-includes paths or only matches directories
+func toIgnorePattern(line string) (pattern string, exclusion, dirOnly bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", false, false
+	}
+	exclusion = strings.HasPrefix(line, "!")
+	line = strings.TrimPrefix(line, "!")
+	dirOnly = strings.HasSuffix(line, "/")
+
+	// like gitignore, a pattern is relative to the ignore file when it has a slash before its end
+	anchored := strings.Contains(strings.TrimSuffix(line, "/"), "/")
+	line = strings.Trim(line, "/")
+	if line == "" {
+		return "", false, false
+	}
+	if !anchored {
+		line = "**/" + line
+	}
+	return line, exclusion, dirOnly
+}
+
+// Matches returns whether the slash separated path of a file, relative to the directory of the ignore file, is
+// ignored. The last pattern matching the file or one of its parent directories decides.
+func (m *IgnoreMatcher) Matches(file string) (bool, error) {
+	if m == nil {
+		return false, nil
+	}
+	parent := path.Dir(file)
+	matched := false
+	for _, p := range m.patterns {
+		// like patternmatcher, only the patterns which could change the result are evaluated
+		if p.exclusion != matched {
+			continue
+		}
+		target := file
+		// a file is only ignored by a directory pattern through one of its parent directories
+		if p.dirOnly {
+			if parent == "." {
+				continue
+			}
+			target = parent
+		}
+		match, err := p.pm.MatchesOrParentMatches(target)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			matched = !p.exclusion
+		}
+	}
+	return matched, nil
+}
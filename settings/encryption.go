@@ -0,0 +1,261 @@
+package settings
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/astronomer/astro-cli/config"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// encrypted values use a sops style envelope, e.g. ENC[AES256_GCM,data:...,iv:...,tag:...,type:str]
+	encryptedValuePrefix = "ENC[AES256_GCM,"
+	encryptedValueSuffix = "]"
+	encryptionKeySize    = 32
+	encryptionTagSize    = 16
+
+	encryptionKeyGeneratedMsg = "Generated a new settings encryption key in your home config. Share it with your team with 'astro config get settings.encryption_key -g'\n"
+)
+
+var (
+	errNoEncryptionKey       = errors.New("the settings file contains encrypted values but no encryption key is set, set it with 'astro config set settings.encryption_key <key> -g'")
+	errInvalidEncryptionKey  = errors.New("invalid settings encryption key, the key must be 32 bytes encoded as base64")
+	errInvalidEncryptedValue = errors.New("invalid encrypted value in the settings file")
+	errDecryptValue          = errors.New("unable to decrypt a value of the settings file, make sure settings.encryption_key is the key the file was encrypted with")
+	errSaveEncryptionKey     = errors.New("unable to save the settings encryption key, make sure your home config exists")
+)
+
+// encryptedFields are the fields of the settings file holding secrets
+var encryptedFields = map[string]bool{
+	"conn_password":  true,
+	"conn_uri":       true,
+	"variable_value": true,
+}
+
+// IsEncrypted checks whether a settings value is encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedValuePrefix) && strings.HasSuffix(value, encryptedValueSuffix)
+}
+
+// EncryptSettingsFile encrypts the connection passwords, connection URIs and variable values of a settings
+// file in place and returns how many values were encrypted. Values which are already encrypted are left as is.
+func EncryptSettingsFile(settingsFile string) (int, error) {
+	path := filepath.Join(WorkingPath, settingsFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, errors.Wrap(err, "error reading settings file")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, errors.Wrap(err, "error reading settings file")
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return 0, errors.Wrap(err, "unable to decode file")
+	}
+
+	var values []*yaml.Node
+	collectEncryptableValues(&doc, &values)
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	key, err := encryptionKey(true)
+	if err != nil {
+		return 0, err
+	}
+	for _, value := range values {
+		encrypted, err := encryptValue(key, value.Value)
+		if err != nil {
+			return 0, err
+		}
+		value.Value = encrypted
+		value.Tag = "!!str"
+		value.Style = yaml.DoubleQuotedStyle
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2) //nolint:mnd
+	if err := encoder.Encode(&doc); err != nil {
+		return 0, errors.Wrap(err, "error encoding settings file")
+	}
+	if err := os.WriteFile(path, buf.Bytes(), info.Mode()); err != nil {
+		return 0, errors.Wrap(err, "error writing settings file")
+	}
+	return len(values), nil
+}
+
+// collectEncryptableValues finds the plaintext values of the fields holding secrets
+func collectEncryptableValues(node *yaml.Node, values *[]*yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if encryptedFields[key.Value] && value.Kind == yaml.ScalarNode && value.Tag != "!!null" && value.Value != "" && !IsEncrypted(value.Value) {
+				*values = append(*values, value)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		collectEncryptableValues(child, values)
+	}
+}
+
+// decryptSettings decrypts the encrypted values of the loaded settings file
+func decryptSettings() error {
+	var key []byte
+	decrypt := func(value *string) error {
+		if !IsEncrypted(*value) {
+			return nil
+		}
+		if key == nil {
+			var err error
+			key, err = encryptionKey(false)
+			if err != nil {
+				return err
+			}
+		}
+		decrypted, err := decryptValue(key, *value)
+		if err != nil {
+			return err
+		}
+		*value = decrypted
+		return nil
+	}
+
+	for i := range settings.Airflow.Connections {
+		if err := decrypt(&settings.Airflow.Connections[i].ConnPassword); err != nil {
+			return err
+		}
+		if err := decrypt(&settings.Airflow.Connections[i].ConnURI); err != nil {
+			return err
+		}
+	}
+	for i := range settings.Airflow.Variables {
+		if err := decrypt(&settings.Airflow.Variables[i].VariableValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keepEncrypted returns the value to write to the settings file in place of a previous value of the file. When the
+// previous value is encrypted the new value is encrypted too, and the previous value is kept if it did not change.
+func keepEncrypted(previous, value string) (string, error) {
+	if !IsEncrypted(previous) || value == "" {
+		return value, nil
+	}
+	key, err := encryptionKey(false)
+	if err != nil {
+		return "", err
+	}
+	if decrypted, err := decryptValue(key, previous); err == nil && decrypted == value {
+		return previous, nil
+	}
+	return encryptValue(key, value)
+}
+
+// encryptionKey returns the settings encryption key from the home config, generating one if asked to
+func encryptionKey(generate bool) ([]byte, error) {
+	encodedKey := config.CFG.SettingsEncryptionKey.GetHomeString()
+	if encodedKey == "" {
+		if !generate {
+			return nil, errNoEncryptionKey
+		}
+		key := make([]byte, encryptionKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, errors.Wrap(err, "error generating settings encryption key")
+		}
+		encodedKey = base64.StdEncoding.EncodeToString(key)
+		if err := config.CFG.SettingsEncryptionKey.SetHomeString(encodedKey); err != nil {
+			return nil, errors.Wrap(err, errSaveEncryptionKey.Error())
+		}
+		if config.CFG.SettingsEncryptionKey.GetHomeString() != encodedKey {
+			return nil, errSaveEncryptionKey
+		}
+		fmt.Print(encryptionKeyGeneratedMsg)
+		return key, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != encryptionKeySize {
+		return nil, errInvalidEncryptionKey
+	}
+	return key, nil
+}
+
+func encryptValue(key []byte, value string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return "", errors.Wrap(err, "error encrypting value")
+	}
+	sealed := gcm.Seal(nil, iv, []byte(value), nil)
+	data, tag := sealed[:len(sealed)-encryptionTagSize], sealed[len(sealed)-encryptionTagSize:]
+	return fmt.Sprintf("%sdata:%s,iv:%s,tag:%s,type:str%s", encryptedValuePrefix,
+		base64.StdEncoding.EncodeToString(data),
+		base64.StdEncoding.EncodeToString(iv),
+		base64.StdEncoding.EncodeToString(tag),
+		encryptedValueSuffix), nil
+}
+
+func decryptValue(key []byte, value string) (string, error) {
+	fields := map[string][]byte{}
+	envelope := strings.TrimSuffix(strings.TrimPrefix(value, encryptedValuePrefix), encryptedValueSuffix)
+	for _, field := range strings.Split(envelope, ",") {
+		name, encoded, ok := strings.Cut(field, ":")
+		if !ok {
+			return "", errInvalidEncryptedValue
+		}
+		if name == "type" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", errInvalidEncryptedValue
+		}
+		fields[name] = decoded
+	}
+	data, iv, tag := fields["data"], fields["iv"], fields["tag"]
+	if iv == nil || len(tag) != encryptionTagSize {
+		return "", errInvalidEncryptedValue
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(iv) != gcm.NonceSize() {
+		return "", errInvalidEncryptedValue
+	}
+	plaintext, err := gcm.Open(nil, iv, append(data, tag...), nil)
+	if err != nil {
+		return "", errDecryptValue
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errInvalidEncryptionKey
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "error initializing encryption")
+	}
+	return gcm, nil
+}
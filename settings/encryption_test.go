@@ -0,0 +1,178 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/astronomer/astro-cli/config"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+)
+
+var encryptedValueRegex = regexp.MustCompile(`ENC\[AES256_GCM,[^\]]*\]`)
+
+const testEncryptionKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=" // 0123456789abcdef0123456789abcdef
+
+func (s *Suite) TestEncryptValue() {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	encrypted, err := encryptValue(key, "test-password")
+	s.NoError(err)
+	s.True(IsEncrypted(encrypted))
+	s.NotContains(encrypted, "test-password")
+
+	decrypted, err := decryptValue(key, encrypted)
+	s.NoError(err)
+	s.Equal("test-password", decrypted)
+
+	_, err = decryptValue([]byte("fedcba9876543210fedcba9876543210"), encrypted)
+	s.ErrorIs(err, errDecryptValue)
+
+	_, err = decryptValue(key, "ENC[AES256_GCM,data:invalid]")
+	s.ErrorIs(err, errInvalidEncryptedValue)
+}
+
+func (s *Suite) TestEncryptSettingsFile() {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+	origWorkingPath := WorkingPath
+	defer func() { WorkingPath = origWorkingPath }()
+
+	WorkingPath = s.T().TempDir()
+	settingsFile := "airflow_settings.yaml"
+	content := `# local settings
+airflow:
+  connections:
+    - conn_id: test-conn
+      conn_type: postgres
+      conn_password: test-password
+      conn_uri:
+  variables:
+    - variable_name: test-var
+      variable_value: test-value
+`
+	s.NoError(os.WriteFile(filepath.Join(WorkingPath, settingsFile), []byte(content), 0o644))
+
+	count, err := EncryptSettingsFile(settingsFile)
+	s.NoError(err)
+	s.Equal(2, count)
+	s.NotEmpty(config.CFG.SettingsEncryptionKey.GetHomeString())
+
+	encrypted, err := os.ReadFile(filepath.Join(WorkingPath, settingsFile))
+	s.NoError(err)
+	s.Contains(string(encrypted), "# local settings")
+	s.Contains(string(encrypted), "conn_id: test-conn")
+	s.NotContains(string(encrypted), "test-password")
+	s.NotContains(string(encrypted), "test-value")
+
+	// encrypting again leaves the encrypted values alone
+	count, err = EncryptSettingsFile(settingsFile)
+	s.NoError(err)
+	s.Equal(0, count)
+
+	err = InitSettings(settingsFile)
+	s.NoError(err)
+	s.Equal("test-password", settings.Airflow.Connections[0].ConnPassword)
+	s.Equal("", settings.Airflow.Connections[0].ConnURI)
+	s.Equal("test-value", settings.Airflow.Variables[0].VariableValue)
+}
+
+func (s *Suite) TestInitSettingsWithoutEncryptionKey() {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+	origWorkingPath := WorkingPath
+	defer func() { WorkingPath = origWorkingPath }()
+
+	WorkingPath = s.T().TempDir()
+	s.NoError(config.CFG.SettingsEncryptionKey.SetHomeString(testEncryptionKey))
+	encrypted, err := encryptValue([]byte("0123456789abcdef0123456789abcdef"), "test-value")
+	s.NoError(err)
+	content := "airflow:\n  variables:\n    - variable_name: test-var\n      variable_value: \"" + encrypted + "\"\n"
+	s.NoError(os.WriteFile(filepath.Join(WorkingPath, "airflow_settings.yaml"), []byte(content), 0o644))
+
+	s.NoError(InitSettings("airflow_settings.yaml"))
+	s.Equal("test-value", settings.Airflow.Variables[0].VariableValue)
+
+	s.NoError(config.CFG.SettingsEncryptionKey.SetHomeString(""))
+	err = InitSettings("airflow_settings.yaml")
+	s.ErrorIs(err, errNoEncryptionKey)
+}
+
+func (s *Suite) TestExportKeepsEncryptedValues() {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+	origWorkingPath := WorkingPath
+	defer func() { WorkingPath = origWorkingPath }()
+
+	WorkingPath = s.T().TempDir()
+	settingsFile := "airflow_settings.yaml"
+	content := `airflow:
+  connections:
+    - conn_id: unchanged-conn
+      conn_type: postgres
+      conn_password: test-password
+    - conn_id: changed-conn
+      conn_type: postgres
+      conn_password: old-password
+  variables:
+    - variable_name: unchanged-var
+      variable_value: test-value
+    - variable_name: changed-var
+      variable_value: old-value
+`
+	s.NoError(os.WriteFile(filepath.Join(WorkingPath, settingsFile), []byte(content), 0o644))
+	_, err := EncryptSettingsFile(settingsFile)
+	s.NoError(err)
+	encrypted, err := os.ReadFile(filepath.Join(WorkingPath, settingsFile))
+	s.NoError(err)
+
+	execAirflowCommand = func(id, airflowCommand string) (string, error) {
+		switch airflowCommand {
+		case airflowConnectionList:
+			return `
+- conn_id: unchanged-conn
+  conn_type: postgres
+  password: test-password
+- conn_id: changed-conn
+  conn_type: postgres
+  password: new-password`, nil
+		case airflowVarExport:
+			return "2 variables successfully exported to tmp.var", nil
+		case catVarFile:
+			return `{"unchanged-var": "test-value", "changed-var": "new-value"}`, nil
+		case airflowPoolsList:
+			return `
+- description: Default pool
+  pool: default_pool
+  slots: '128'`, nil
+		default:
+			return "", nil
+		}
+	}
+	err = Export("id", settingsFile, 2, true, true, true)
+	s.NoError(err)
+
+	exported, err := os.ReadFile(filepath.Join(WorkingPath, settingsFile))
+	s.NoError(err)
+	for _, plaintext := range []string{"test-password", "new-password", "test-value", "new-value"} {
+		s.NotContains(string(exported), plaintext)
+	}
+	// only the values which changed are encrypted again
+	kept := 0
+	for _, value := range encryptedValueRegex.FindAllString(string(encrypted), -1) {
+		if strings.Contains(string(exported), value) {
+			kept++
+		}
+	}
+	s.Equal(2, kept)
+
+	s.NoError(InitSettings(settingsFile))
+	passwords := map[string]string{}
+	for _, conn := range settings.Airflow.Connections {
+		passwords[conn.ConnID] = conn.ConnPassword
+	}
+	s.Equal(map[string]string{"unchanged-conn": "test-password", "changed-conn": "new-password"}, passwords)
+	values := map[string]string{}
+	for _, variable := range settings.Airflow.Variables {
+		values[variable.VariableName] = variable.VariableValue
+	}
+	s.Equal(map[string]string{"unchanged-var": "test-value", "changed-var": "new-value"}, values)
+}
@@ -48,17 +48,32 @@ const (
 	catVarFile            = "cat tmp.var"
 	rmVarFile             = "rm tmp.var"
 	catConnFile           = "cat tmp.connections"
-	configReadErrorMsg    = "Error reading Airflow Settings file. Connections, Variables, and Pools were not loaded please check your Settings file syntax: %s\n"
-	noColorString         = "[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))"
+	// redactedSecret is the value the Airflow API masks secrets with
+	redactedSecret     = "***"
+	configReadErrorMsg = "Error reading Airflow Settings file. Connections, Variables, and Pools were not loaded please check your Settings file syntax: %s\n"
+	noColorString      = "[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))"
+)
+
+const (
+	// SecretsBackendLocalFilesystem reads connections and variables from files in the project
+	SecretsBackendLocalFilesystem = "local_filesystem"
+	// SecretsBackendVault reads connections and variables from a HashiCorp Vault dev server
+	SecretsBackendVault = "vault"
 )
 
 var (
 	errNoID = errors.New("container ID is not found, the webserver may not be running")
 	re      = regexp.MustCompile(noColorString)
+
+	errInvalidSecretsBackendType  = errors.New("invalid secrets backend type, the type can be one of: local_filesystem or vault")
+	errMissingSecretsBackendFiles = errors.New("the local_filesystem secrets backend requires a connections_file or a variables_file")
+	errInvalidSecretsBackendFile  = errors.New("secrets backend files must be paths inside the project directory")
+	errSecretsBackendFileForVault = errors.New("connections_file and variables_file are only supported by the local_filesystem secrets backend")
 )
 
-// ConfigSettings is the main builder of the settings package
-func ConfigSettings(id, settingsFile string, envConns map[string]astrocore.EnvironmentObjectConnection, version uint64, connections, variables, pools bool) error {
+// ConfigSettings is the main builder of the settings package. On Airflow 2 and later the objects are synced
+// incrementally, and with prune the objects which are not in the settings file are removed.
+func ConfigSettings(id, settingsFile string, envConns map[string]astrocore.EnvironmentObjectConnection, version uint64, connections, variables, pools, prune bool) error {
 	if id == "" {
 		return errNoID
 	}
@@ -66,6 +81,12 @@ func ConfigSettings(id, settingsFile string, envConns map[string]astrocore.Envir
 	if err != nil {
 		return err
 	}
+	if version >= AirflowVersionTwo {
+		return syncSettings(id, envConns, connections, variables, pools, prune)
+	}
+	if prune {
+		return errPruneAirflowOne
+	}
 	if pools {
 		if err := AddPools(id, version); err != nil {
 			return fmt.Errorf("error adding pools: %w", err)
@@ -86,6 +107,14 @@ func ConfigSettings(id, settingsFile string, envConns map[string]astrocore.Envir
 
 // InitSettings initializes settings file
 func InitSettings(settingsFile string) error {
+	if err := loadSettings(settingsFile); err != nil {
+		return err
+	}
+	return decryptSettings()
+}
+
+// loadSettings reads the settings file without decrypting its encrypted values
+func loadSettings(settingsFile string) error {
 	// Set up viper object for project config
 	viperSettings = viper.New()
 	ConfigFileName := strings.Split(settingsFile, ".")[0]
@@ -110,6 +139,43 @@ func InitSettings(settingsFile string) error {
 	return nil
 }
 
+// GetSecretsBackend returns the local secrets backend declared in the settings file, or nil if none is declared
+func GetSecretsBackend(settingsFile string) (*SecretsBackend, error) {
+	v := viper.New()
+	v.SetConfigType(ConfigFileType)
+	v.SetConfigFile(filepath.Join(WorkingPath, settingsFile))
+	if err := v.ReadInConfig(); err != nil {
+		return nil, errors.Wrap(err, "error reading settings file")
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, errors.Wrap(err, "unable to decode file")
+	}
+
+	backend := cfg.Airflow.SecretsBackend
+	switch backend.Type {
+	case "":
+		return nil, nil
+	case SecretsBackendLocalFilesystem:
+		if backend.ConnectionsFile == "" && backend.VariablesFile == "" {
+			return nil, errMissingSecretsBackendFiles
+		}
+		for _, file := range []string{backend.ConnectionsFile, backend.VariablesFile} {
+			if file != "" && !filepath.IsLocal(file) {
+				return nil, errInvalidSecretsBackendFile
+			}
+		}
+	case SecretsBackendVault:
+		if backend.ConnectionsFile != "" || backend.VariablesFile != "" {
+			return nil, errSecretsBackendFileForVault
+		}
+	default:
+		return nil, errInvalidSecretsBackendType
+	}
+	return &backend, nil
+}
+
 // AddVariables is a function to add Variables from settings.yaml
 func AddVariables(id string, version uint64) error {
 	variables := settings.Airflow.Variables
@@ -483,8 +549,8 @@ func Export(id, settingsFile string, version uint64, connections, variables, poo
 	if id == "" {
 		return errNoID
 	}
-	// init settings file
-	err := InitSettings(settingsFile)
+	// init settings file, encrypted values are kept as is so they are written back encrypted
+	err := loadSettings(settingsFile)
 	if err != nil {
 		return err
 	}
@@ -547,9 +613,15 @@ func ExportConnections(id string) error {
 				fmt.Printf("Issue with parsing port number: %s", err.Error())
 			}
 		}
+		password := connections[i].ConnPassword
 		for j := range settings.Airflow.Connections {
 			if settings.Airflow.Connections[j].ConnID == connections[i].ConnID {
 				fmt.Println("Updating Connection: " + connections[i].ConnID)
+				// a password encrypted in the settings file stays encrypted
+				password, err = keepEncrypted(settings.Airflow.Connections[j].ConnPassword, password)
+				if err != nil {
+					return err
+				}
 				// Remove connection if it already exits
 				settings.Airflow.Connections = append(settings.Airflow.Connections[:j], settings.Airflow.Connections[j+1:]...)
 				break
@@ -563,7 +635,7 @@ func ExportConnections(id string) error {
 			ConnHost:     connections[i].ConnHost,
 			ConnSchema:   connections[i].ConnSchema,
 			ConnLogin:    connections[i].ConnLogin,
-			ConnPassword: connections[i].ConnPassword,
+			ConnPassword: password,
 			ConnPort:     port,
 			ConnExtra:    connections[i].ConnExtra,
 		}
@@ -602,15 +674,6 @@ func ExportVariables(id string) error {
 		}
 		// add the variables to settings object
 		for k, v := range m {
-			for j := range settings.Airflow.Variables {
-				if settings.Airflow.Variables[j].VariableName == k {
-					fmt.Println("Updating Pool: " + k)
-					// Remove variable if it already exists
-					settings.Airflow.Variables = append(settings.Airflow.Variables[:j], settings.Airflow.Variables[j+1:]...)
-					break
-				}
-			}
-
 			var vs string
 			switch vt := v.(type) {
 			case string:
@@ -624,6 +687,20 @@ func ExportVariables(id string) error {
 				}
 				vs = string(b)
 			}
+
+			for j := range settings.Airflow.Variables {
+				if settings.Airflow.Variables[j].VariableName == k {
+					fmt.Println("Updating Pool: " + k)
+					// a value encrypted in the settings file stays encrypted
+					vs, err = keepEncrypted(settings.Airflow.Variables[j].VariableValue, vs)
+					if err != nil {
+						return err
+					}
+					// Remove variable if it already exists
+					settings.Airflow.Variables = append(settings.Airflow.Variables[:j], settings.Airflow.Variables[j+1:]...)
+					break
+				}
+			}
 			newVariables := Variables{{k, vs}}
 			fmt.Println("Exporting Variable: " + k)
 			settings.Airflow.Variables = append(settings.Airflow.Variables, newVariables...)
@@ -696,6 +773,125 @@ func ExportPools(id string) error {
 	return nil
 }
 
+// MergeObjects writes connections, variables and pools to a settings file, replacing the objects of the file with
+// the same ids. The settings file is created if it does not exist. The secrets of the replaced objects are kept when
+// the new objects leave them empty or redacted, and the encrypted secrets of the file stay encrypted.
+func MergeObjects(settingsFile string, objects *Airflow) error {
+	path := filepath.Join(WorkingPath, settingsFile)
+	v := viper.New()
+	v.SetConfigType(ConfigFileType)
+	v.SetConfigFile(path)
+	var cfg Config
+	if _, err := os.Stat(path); err == nil {
+		if err := v.ReadInConfig(); err != nil {
+			return errors.Wrap(err, "error reading settings file")
+		}
+		if err := v.Unmarshal(&cfg); err != nil {
+			return errors.Wrap(err, "unable to decode file")
+		}
+	}
+
+	for i := range objects.Connections {
+		conn := objects.Connections[i]
+		idx := -1
+		for j := range cfg.Airflow.Connections {
+			if cfg.Airflow.Connections[j].ConnID == conn.ConnID {
+				idx = j
+				break
+			}
+		}
+		if idx == -1 {
+			cfg.Airflow.Connections = append(cfg.Airflow.Connections, conn)
+			continue
+		}
+		previous := cfg.Airflow.Connections[idx]
+		password, err := mergeSecret(previous.ConnPassword, conn.ConnPassword)
+		if err != nil {
+			return err
+		}
+		conn.ConnPassword = password
+		conn.ConnExtra = mergeExtra(previous.ConnExtra, conn.ConnExtra)
+		cfg.Airflow.Connections[idx] = conn
+	}
+	for i := range objects.Variables {
+		variable := objects.Variables[i]
+		idx := -1
+		for j := range cfg.Airflow.Variables {
+			if cfg.Airflow.Variables[j].VariableName == variable.VariableName {
+				idx = j
+				break
+			}
+		}
+		if idx == -1 {
+			cfg.Airflow.Variables = append(cfg.Airflow.Variables, variable)
+			continue
+		}
+		value, err := mergeSecret(cfg.Airflow.Variables[idx].VariableValue, variable.VariableValue)
+		if err != nil {
+			return err
+		}
+		variable.VariableValue = value
+		cfg.Airflow.Variables[idx] = variable
+	}
+	for i := range objects.Pools {
+		pool := objects.Pools[i]
+		idx := -1
+		for j := range cfg.Airflow.Pools {
+			if cfg.Airflow.Pools[j].PoolName == pool.PoolName {
+				idx = j
+				break
+			}
+		}
+		if idx == -1 {
+			cfg.Airflow.Pools = append(cfg.Airflow.Pools, pool)
+			continue
+		}
+		cfg.Airflow.Pools[idx] = pool
+	}
+
+	v.Set("airflow", cfg.Airflow)
+	if err := v.WriteConfigAs(path); err != nil {
+		return errors.Wrap(err, "error writing settings file")
+	}
+	return nil
+}
+
+// mergeSecret returns the secret to write to the settings file in place of a previous secret of the file, keeping the
+// previous secret when the new one is empty or redacted
+func mergeSecret(previous, value string) (string, error) {
+	if isRedactedSecret(value) {
+		return previous, nil
+	}
+	return keepEncrypted(previous, value)
+}
+
+// mergeExtra returns the extra to write to the settings file in place of a previous extra of the file, keeping the
+// previous values of the fields the new extra leaves empty or redacted
+func mergeExtra(previous, extra any) any {
+	previousMap, ok := previous.(map[string]any)
+	if !ok {
+		return extra
+	}
+	extraMap, ok := extra.(map[string]any)
+	if !ok {
+		return extra
+	}
+	for key, value := range extraMap {
+		s, isString := value.(string)
+		previousValue, exists := previousMap[key]
+		if isString && isRedactedSecret(s) && exists && previousValue != "" {
+			extraMap[key] = previousValue
+		}
+	}
+	return extraMap
+}
+
+// isRedactedSecret checks whether a secret was left out of the objects of a Deployment, which the Airflow API does
+// either by leaving it empty or by masking it
+func isRedactedSecret(value string) bool {
+	return value == "" || value == redactedSecret
+}
+
 func jsonString(conn *Connection) string {
 	var extraMap map[string]any
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	astrocore "github.com/astronomer/astro-cli/astro-client-core"
@@ -27,13 +28,13 @@ func (s *Suite) TearDownTest() {
 
 func (s *Suite) TestConfigSettings() {
 	// config settings success
-	err := ConfigSettings("container-id", "", nil, 2, false, false, false)
+	err := ConfigSettings("container-id", "", nil, 2, false, false, false, false)
 	s.NoError(err)
 	// config setttings no id error
-	err = ConfigSettings("", "", nil, 2, false, false, false)
+	err = ConfigSettings("", "", nil, 2, false, false, false, false)
 	s.ErrorIs(err, errNoID)
 	// config settings settings file error
-	err = ConfigSettings("container-id", "testfiles/airflow_settings_invalid.yaml", nil, 2, false, false, false)
+	err = ConfigSettings("container-id", "testfiles/airflow_settings_invalid.yaml", nil, 2, false, false, false, false)
 	s.Contains(err.Error(), "unable to decode file")
 }
 
@@ -485,3 +486,58 @@ func (s *Suite) TestWriteAirflowSettingstoYAML() {
 		os.Remove("./variables.yaml")
 	})
 }
+
+func (s *Suite) TestGetSecretsBackend() {
+	origWorkingPath := WorkingPath
+	defer func() { WorkingPath = origWorkingPath }()
+
+	writeSettings := func(content string) {
+		WorkingPath = s.T().TempDir()
+		s.NoError(os.WriteFile(filepath.Join(WorkingPath, "airflow_settings.yaml"), []byte(content), 0o644))
+	}
+
+	s.Run("no secrets backend", func() {
+		writeSettings("airflow:\n  variables:\n    - variable_name: test\n      variable_value: test\n")
+		backend, err := GetSecretsBackend("airflow_settings.yaml")
+		s.NoError(err)
+		s.Nil(backend)
+	})
+
+	s.Run("local filesystem", func() {
+		writeSettings("airflow:\n  secrets_backend:\n    type: local_filesystem\n    connections_file: include/secrets/connections.yaml\n")
+		backend, err := GetSecretsBackend("airflow_settings.yaml")
+		s.NoError(err)
+		s.Equal(&SecretsBackend{Type: SecretsBackendLocalFilesystem, ConnectionsFile: "include/secrets/connections.yaml"}, backend)
+	})
+
+	s.Run("local filesystem without files", func() {
+		writeSettings("airflow:\n  secrets_backend:\n    type: local_filesystem\n")
+		_, err := GetSecretsBackend("airflow_settings.yaml")
+		s.ErrorIs(err, errMissingSecretsBackendFiles)
+	})
+
+	s.Run("local filesystem outside of the project", func() {
+		writeSettings("airflow:\n  secrets_backend:\n    type: local_filesystem\n    variables_file: ../variables.yaml\n")
+		_, err := GetSecretsBackend("airflow_settings.yaml")
+		s.ErrorIs(err, errInvalidSecretsBackendFile)
+	})
+
+	s.Run("vault", func() {
+		writeSettings("airflow:\n  secrets_backend:\n    type: vault\n    token: test-token\n")
+		backend, err := GetSecretsBackend("airflow_settings.yaml")
+		s.NoError(err)
+		s.Equal(&SecretsBackend{Type: SecretsBackendVault, Token: "test-token"}, backend)
+	})
+
+	s.Run("vault with files", func() {
+		writeSettings("airflow:\n  secrets_backend:\n    type: vault\n    connections_file: connections.yaml\n")
+		_, err := GetSecretsBackend("airflow_settings.yaml")
+		s.ErrorIs(err, errSecretsBackendFileForVault)
+	})
+
+	s.Run("invalid type", func() {
+		writeSettings("airflow:\n  secrets_backend:\n    type: aws\n")
+		_, err := GetSecretsBackend("airflow_settings.yaml")
+		s.ErrorIs(err, errInvalidSecretsBackendType)
+	})
+}
@@ -0,0 +1,453 @@
+package settings
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	"github.com/astronomer/astro-cli/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	airflowConnectionsExportJSON = "airflow connections export - --file-format json"
+	airflowVariablesExportJSON   = "airflow variables export -"
+	airflowPoolsListJSON         = "airflow pools list -o json"
+
+	defaultPoolName = "default_pool"
+)
+
+var errPruneAirflowOne = errors.New("pruning Airflow objects requires Airflow 2 or later")
+
+// connectionObject is a connection in the format of the airflow connections export and import commands
+type connectionObject struct {
+	ConnType string `json:"conn_type,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Login    string `json:"login,omitempty"`
+	Password string `json:"password,omitempty"`
+	Schema   string `json:"schema,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Extra    string `json:"extra,omitempty"`
+}
+
+// poolObject is a pool in the format of the airflow pools import command
+type poolObject struct {
+	Slots       int    `json:"slots"`
+	Description string `json:"description"`
+}
+
+// objectChanges are the changes needed to bring the objects of Airflow in line with the settings file
+type objectChanges struct {
+	added   []string
+	updated []string
+	removed []string
+}
+
+func (c *objectChanges) empty() bool {
+	return len(c.added) == 0 && len(c.updated) == 0 && len(c.removed) == 0
+}
+
+func (c *objectChanges) print(objectType string) {
+	for _, name := range c.added {
+		fmt.Printf("Added %s: %s\n", objectType, name)
+	}
+	for _, name := range c.updated {
+		fmt.Printf("Updated %s: %s\n", objectType, name)
+	}
+	for _, name := range c.removed {
+		fmt.Printf("Removed %s: %s\n", objectType, name)
+	}
+}
+
+// syncSettings brings the pools, variables and connections of Airflow in line with the settings file. The current
+// objects are exported once per object type and only the objects which changed are imported, in a single command.
+// With prune, the objects which are not in the settings file are removed from Airflow.
+func syncSettings(id string, envConns map[string]astrocore.EnvironmentObjectConnection, connections, variables, pools, prune bool) error {
+	if pools {
+		if err := SyncPools(id, prune); err != nil {
+			return fmt.Errorf("error syncing pools: %w", err)
+		}
+	}
+	if variables {
+		if err := SyncVariables(id, prune); err != nil {
+			return fmt.Errorf("error syncing variables: %w", err)
+		}
+	}
+	if connections {
+		if err := SyncConnections(id, envConns, prune); err != nil {
+			return fmt.Errorf("error syncing connections: %w", err)
+		}
+	}
+	return nil
+}
+
+// SyncConnections brings the connections of Airflow in line with the settings file
+func SyncConnections(id string, envConns map[string]astrocore.EnvironmentObjectConnection, prune bool) error {
+	out, err := execAirflowCommand(id, airflowConnectionsExportJSON)
+	if err != nil {
+		return fmt.Errorf("error exporting connections: %w", err)
+	}
+	exported := map[string]json.RawMessage{}
+	if err := unmarshalCommandOutput(out, "{", "}", &exported); err != nil {
+		return errors.Wrap(err, "error parsing exported connections")
+	}
+	current := make(map[string]connectionObject, len(exported))
+	for connID, raw := range exported {
+		var conn connectionObject
+		if err := json.Unmarshal(raw, &conn); err != nil {
+			return errors.Wrap(err, "error parsing exported connections")
+		}
+		current[connID] = conn
+	}
+
+	declared := map[string]bool{}
+	changes := objectChanges{}
+	toImport := map[string]interface{}{}
+	var replaces []string
+	for _, conn := range AppendEnvironmentConnections(settings.Airflow.Connections, envConns) {
+		if !objectValidator(0, conn.ConnID) {
+			continue
+		}
+		declared[conn.ConnID] = true
+		if !objectValidator(1, conn.ConnType, conn.ConnURI) {
+			fmt.Printf("Skipping %s: conn_type or conn_uri must be specified.\n", conn.ConnID)
+			continue
+		}
+
+		existing, exists := current[conn.ConnID]
+		desired, uri := newConnectionObject(&conn)
+		compared, comparable := desired, true
+		if uri != "" {
+			compared, comparable = parseConnectionURI(uri)
+		}
+		switch {
+		case !exists:
+			changes.added = append(changes.added, conn.ConnID)
+		// connections defined by a URI Airflow parses differently are always imported
+		case !comparable || !equalConnections(&existing, &compared):
+			changes.updated = append(changes.updated, conn.ConnID)
+		default:
+			logger.Debugf("Connection %s is up to date", conn.ConnID)
+			continue
+		}
+		var object interface{} = desired
+		if uri != "" {
+			object = uri
+		}
+		if !exists {
+			toImport[conn.ConnID] = object
+			continue
+		}
+		// the import command skips existing connections, so updated connections are replaced one by one
+		replace, err := replaceCommand("connections", conn.ConnID, object, exported[conn.ConnID])
+		if err != nil {
+			return err
+		}
+		replaces = append(replaces, replace)
+	}
+	if prune {
+		changes.removed = undeclaredObjects(current, declared)
+	}
+
+	return applyChanges(id, "connections", &changes, changes.removed, toImport, replaces, "Connection")
+}
+
+// SyncVariables brings the variables of Airflow in line with the settings file
+func SyncVariables(id string, prune bool) error {
+	out, err := execAirflowCommand(id, airflowVariablesExportJSON)
+	if err != nil {
+		return fmt.Errorf("error exporting variables: %w", err)
+	}
+	exported := map[string]interface{}{}
+	if err := unmarshalCommandOutput(out, "{", "}", &exported); err != nil {
+		return errors.Wrap(err, "error parsing exported variables")
+	}
+
+	declared := map[string]bool{}
+	changes := objectChanges{}
+	toImport := map[string]interface{}{}
+	for _, variable := range settings.Airflow.Variables {
+		if !objectValidator(0, variable.VariableName) {
+			if objectValidator(0, variable.VariableValue) {
+				fmt.Print("Skipping Variable Creation: No Variable Name Specified.\n")
+			}
+			continue
+		}
+		declared[variable.VariableName] = true
+		if !objectValidator(0, variable.VariableValue) {
+			continue
+		}
+
+		existing, exists := exported[variable.VariableName]
+		switch {
+		case !exists:
+			changes.added = append(changes.added, variable.VariableName)
+		case variableString(existing) != variable.VariableValue:
+			changes.updated = append(changes.updated, variable.VariableName)
+		default:
+			logger.Debugf("Variable %s is up to date", variable.VariableName)
+			continue
+		}
+		toImport[variable.VariableName] = variable.VariableValue
+	}
+	if prune {
+		changes.removed = undeclaredObjects(exported, declared)
+	}
+
+	// the import command overwrites existing variables
+	return applyChanges(id, "variables", &changes, changes.removed, toImport, nil, "Variable")
+}
+
+// SyncPools brings the pools of Airflow in line with the settings file
+func SyncPools(id string, prune bool) error {
+	out, err := execAirflowCommand(id, airflowPoolsListJSON)
+	if err != nil {
+		return fmt.Errorf("error listing pools: %w", err)
+	}
+	var listed []map[string]interface{}
+	if err := unmarshalCommandOutput(out, "[", "]", &listed); err != nil {
+		return errors.Wrap(err, "error parsing listed pools")
+	}
+	current := map[string]poolObject{}
+	for _, pool := range listed {
+		slots, _ := strconv.Atoi(fmt.Sprint(pool["slots"]))
+		description, _ := pool["description"].(string)
+		current[fmt.Sprint(pool["pool"])] = poolObject{Slots: slots, Description: description}
+	}
+
+	declared := map[string]bool{defaultPoolName: true}
+	changes := objectChanges{}
+	toImport := map[string]interface{}{}
+	for _, pool := range settings.Airflow.Pools {
+		if !objectValidator(0, pool.PoolName) {
+			continue
+		}
+		declared[pool.PoolName] = true
+		if pool.PoolSlot == 0 {
+			fmt.Printf("Skipping %s: Pool Slot must be set.\n", pool.PoolName)
+			continue
+		}
+
+		desired := poolObject{Slots: pool.PoolSlot, Description: pool.PoolDescription}
+		existing, exists := current[pool.PoolName]
+		switch {
+		case !exists:
+			changes.added = append(changes.added, pool.PoolName)
+		case existing != desired:
+			changes.updated = append(changes.updated, pool.PoolName)
+		default:
+			logger.Debugf("Pool %s is up to date", pool.PoolName)
+			continue
+		}
+		toImport[pool.PoolName] = desired
+	}
+	if prune {
+		changes.removed = undeclaredObjects(current, declared)
+	}
+
+	// the import command overwrites existing pools
+	return applyChanges(id, "pools", &changes, changes.removed, toImport, nil, "Pool")
+}
+
+// applyChanges deletes, imports and replaces Airflow objects in a single command run in the container. The command
+// stops at the first failure.
+func applyChanges(id, objectType string, changes *objectChanges, deletes []string, toImport map[string]interface{}, replaces []string, objectName string) error {
+	if changes.empty() {
+		logger.Debugf("%s are up to date", objectType)
+		return nil
+	}
+
+	commands := make([]string, 0, len(deletes)+len(replaces)+1)
+	for _, name := range deletes {
+		commands = append(commands, fmt.Sprintf("airflow %s delete %s", objectType, shellQuote(name)))
+	}
+	if len(toImport) > 0 {
+		content, err := json.Marshal(toImport)
+		if err != nil {
+			return errors.Wrapf(err, "error encoding %s", objectType)
+		}
+		commands = append(commands, importCommand(objectType, content))
+	}
+	commands = append(commands, replaces...)
+
+	out, err := execAirflowCommand(id, strings.Join(commands, " && "))
+	if err != nil {
+		return fmt.Errorf("error importing %s: %w", objectType, err)
+	}
+	logger.Debugf("Syncing %s logs:\n%s", objectType, out)
+	changes.print(objectName)
+	return nil
+}
+
+// importCommand writes the objects to a temporary file in the container and imports them from it
+func importCommand(objectType string, content []byte) string {
+	file := fmt.Sprintf("/tmp/astro_%s.json", objectType)
+	return fmt.Sprintf("( echo %s | base64 -d > %s && airflow %s import %s; status=$?; rm -f %s; exit $status )",
+		base64.StdEncoding.EncodeToString(content), file, objectType, file, file)
+}
+
+// replaceCommand deletes an object and imports its new version. If the import fails, the previous version of the
+// object is imported back so that a failed update does not lose the object, and the command fails.
+func replaceCommand(objectType, name string, object, previous interface{}) (string, error) {
+	content, err := json.Marshal(map[string]interface{}{name: object})
+	if err != nil {
+		return "", errors.Wrapf(err, "error encoding %s", objectType)
+	}
+	previousContent, err := json.Marshal(map[string]interface{}{name: previous})
+	if err != nil {
+		return "", errors.Wrapf(err, "error encoding %s", objectType)
+	}
+	restored := shellQuote(fmt.Sprintf("failed to update %s, restored its previous version", name))
+	return fmt.Sprintf("{ airflow %s delete %s && %s || { %s; echo %s >&2; exit 1; }; }",
+		objectType, shellQuote(name), importCommand(objectType, content), importCommand(objectType, previousContent), restored), nil
+}
+
+// newConnectionObject converts a connection of the settings file into the import format. Connections
+// defined by URI are returned as a URI instead, with the extra encoded in the URI.
+func newConnectionObject(conn *Connection) (connectionObject, string) {
+	obj := connectionObject{
+		ConnType: conn.ConnType,
+		Host:     conn.ConnHost,
+		Login:    conn.ConnLogin,
+		Password: conn.ConnPassword,
+		Schema:   conn.ConnSchema,
+		Port:     conn.ConnPort,
+		Extra:    jsonString(conn),
+	}
+	// like astro dev start always did, the URI is only used if no other field is set
+	if conn.ConnURI == "" || obj.ConnType != "" || obj.Host != "" || obj.Login != "" || obj.Password != "" || obj.Schema != "" || obj.Port != 0 {
+		return obj, ""
+	}
+	uri := conn.ConnURI
+	if obj.Extra != "" {
+		separator := "?"
+		if strings.Contains(uri, "?") {
+			separator = "&"
+		}
+		uri += separator + "__extra__=" + url.QueryEscape(obj.Extra)
+	}
+	return connectionObject{}, uri
+}
+
+// parseConnectionURI converts a connection URI into the fields Airflow exports for it, the way Airflow parses the URI.
+// URIs with a protocol in their host, or which can't be parsed, are not converted.
+func parseConnectionURI(uri string) (connectionObject, bool) {
+	if strings.Count(uri, "://") != 1 {
+		return connectionObject{}, false
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return connectionObject{}, false
+	}
+	obj := connectionObject{
+		ConnType: u.Scheme,
+		Host:     strings.ToLower(u.Hostname()),
+		Schema:   strings.TrimPrefix(u.Path, "/"),
+	}
+	// like Airflow, postgresql is an alias of postgres and dashes are underscores in connection types
+	if obj.ConnType == "postgresql" {
+		obj.ConnType = "postgres"
+	}
+	obj.ConnType = strings.ReplaceAll(obj.ConnType, "-", "_")
+	if u.User != nil {
+		obj.Login = u.User.Username()
+		obj.Password, _ = u.User.Password()
+	}
+	if port := u.Port(); port != "" {
+		obj.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return connectionObject{}, false
+		}
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return connectionObject{}, false
+	}
+	if extra, ok := query["__extra__"]; ok {
+		obj.Extra = extra[len(extra)-1]
+	} else if len(query) > 0 {
+		// the other query parameters are the extra of the connection, the last value of a parameter winning
+		extra := map[string]string{}
+		for key, values := range query {
+			extra[key] = values[len(values)-1]
+		}
+		b, err := json.Marshal(extra)
+		if err != nil {
+			return connectionObject{}, false
+		}
+		obj.Extra = string(b)
+	}
+	return obj, true
+}
+
+func equalConnections(current, desired *connectionObject) bool {
+	a, b := *current, *desired
+	a.Extra, b.Extra = "", ""
+	return a == b && equalJSON(current.Extra, desired.Extra)
+}
+
+// equalJSON compares two JSON documents, treating empty documents as equal
+func equalJSON(a, b string) bool {
+	var aValue, bValue interface{}
+	if a != "" && json.Unmarshal([]byte(a), &aValue) != nil {
+		return a == b
+	}
+	if b != "" && json.Unmarshal([]byte(b), &bValue) != nil {
+		return a == b
+	}
+	if isEmptyJSON(aValue) && isEmptyJSON(bValue) {
+		return true
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+func isEmptyJSON(value interface{}) bool {
+	m, ok := value.(map[string]interface{})
+	return value == nil || (ok && len(m) == 0)
+}
+
+// variableString converts an exported variable back into its string value. The export command decodes
+// values holding JSON, so those are encoded again.
+func variableString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+// undeclaredObjects returns the sorted names of the objects which are not declared in the settings file
+func undeclaredObjects[T any](current map[string]T, declared map[string]bool) []string {
+	var names []string
+	for name := range current {
+		if !declared[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unmarshalCommandOutput parses the JSON printed by an Airflow command, ignoring any warnings printed around it
+func unmarshalCommandOutput(out, start, end string, v interface{}) error {
+	plainOut := re.ReplaceAllString(out, "")
+	startIndex := strings.Index(plainOut, start)
+	endIndex := strings.LastIndex(plainOut, end)
+	if startIndex == -1 || endIndex < startIndex {
+		return fmt.Errorf("unexpected output: %s", strings.TrimSpace(plainOut))
+	}
+	return json.Unmarshal([]byte(plainOut[startIndex:endIndex+1]), v)
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
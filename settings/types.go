@@ -29,11 +29,24 @@ type Variables []struct {
 	VariableValue string `mapstructure:"variable_value" yaml:"variable_value"`
 }
 
+// SecretsBackend contains structure of a local secrets backend
+type SecretsBackend struct {
+	Type            string `mapstructure:"type" yaml:"type"`
+	ConnectionsFile string `mapstructure:"connections_file" yaml:"connections_file"`
+	VariablesFile   string `mapstructure:"variables_file" yaml:"variables_file"`
+	Image           string `mapstructure:"image" yaml:"image"`
+	Token           string `mapstructure:"token" yaml:"token"`
+	MountPoint      string `mapstructure:"mount_point" yaml:"mount_point"`
+	ConnectionsPath string `mapstructure:"connections_path" yaml:"connections_path"`
+	VariablesPath   string `mapstructure:"variables_path" yaml:"variables_path"`
+}
+
 // Airflow contains structure of airflow settings
 type Airflow struct {
-	Connections `mapstructure:"connections"`
-	Pools       `mapstructure:"pools"`
-	Variables   `mapstructure:"variables"`
+	Connections    `mapstructure:"connections"`
+	Pools          `mapstructure:"pools"`
+	Variables      `mapstructure:"variables"`
+	SecretsBackend SecretsBackend `mapstructure:"secrets_backend" yaml:"secrets_backend,omitempty"`
 }
 
 // Config is input data to generate connections, pools, and variables
@@ -0,0 +1,267 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+type fieldType int
+
+const (
+	stringField fieldType = iota
+	intField
+	// extraField is either a mapping or a JSON string
+	extraField
+)
+
+type objectSchema struct {
+	name   string
+	idKey  string
+	fields map[string]fieldType
+}
+
+var (
+	connectionSchema = objectSchema{
+		name:  "connection",
+		idKey: "conn_id",
+		fields: map[string]fieldType{
+			"conn_id":       stringField,
+			"conn_type":     stringField,
+			"conn_host":     stringField,
+			"conn_schema":   stringField,
+			"conn_login":    stringField,
+			"conn_password": stringField,
+			"conn_port":     intField,
+			"conn_uri":      stringField,
+			"conn_extra":    extraField,
+		},
+	}
+	poolSchema = objectSchema{
+		name:  "pool",
+		idKey: "pool_name",
+		fields: map[string]fieldType{
+			"pool_name":        stringField,
+			"pool_slot":        intField,
+			"pool_description": stringField,
+		},
+	}
+	variableSchema = objectSchema{
+		name:  "variable",
+		idKey: "variable_name",
+		fields: map[string]fieldType{
+			"variable_name":  stringField,
+			"variable_value": stringField,
+		},
+	}
+	secretsBackendSchema = objectSchema{
+		name: "secrets_backend",
+		fields: map[string]fieldType{
+			"type":             stringField,
+			"connections_file": stringField,
+			"variables_file":   stringField,
+			"image":            stringField,
+			"token":            stringField,
+			"mount_point":      stringField,
+			"connections_path": stringField,
+			"variables_path":   stringField,
+		},
+	}
+	objectListSchemas = map[string]objectSchema{
+		"connections": connectionSchema,
+		"pools":       poolSchema,
+		"variables":   variableSchema,
+	}
+)
+
+// ValidationIssue is a problem found on a line of a settings file
+type ValidationIssue struct {
+	Line    int
+	Message string
+}
+
+// ValidationError lists the problems found in a settings file
+type ValidationError struct {
+	File   string
+	Issues []ValidationIssue
+}
+
+func (e *ValidationError) Error() string {
+	lines := []string{fmt.Sprintf("%s is invalid:", e.File)}
+	for _, issue := range e.Issues {
+		lines = append(lines, fmt.Sprintf("  %s:%d: %s", e.File, issue.Line, issue.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ValidateSettingsFile checks a settings file for unknown keys, values of the wrong type, duplicate
+// connections or pools and invalid connection URIs. The problems found are returned as a *ValidationError.
+func ValidateSettingsFile(settingsFile string) error {
+	content, err := os.ReadFile(filepath.Join(WorkingPath, settingsFile))
+	if err != nil {
+		return errors.Wrap(err, "error reading settings file")
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return errors.Wrapf(err, "error parsing %s", settingsFile)
+	}
+
+	v := &validator{}
+	if len(doc.Content) > 0 {
+		v.validateRoot(doc.Content[0])
+	}
+	if len(v.issues) > 0 {
+		sort.SliceStable(v.issues, func(i, j int) bool { return v.issues[i].Line < v.issues[j].Line })
+		return &ValidationError{File: settingsFile, Issues: v.issues}
+	}
+	return nil
+}
+
+type validator struct {
+	issues []ValidationIssue
+}
+
+func (v *validator) addIssue(node *yaml.Node, format string, args ...interface{}) {
+	v.issues = append(v.issues, ValidationIssue{Line: node.Line, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validateRoot(root *yaml.Node) {
+	if isNull(root) {
+		return
+	}
+	if root.Kind != yaml.MappingNode {
+		v.addIssue(root, "the settings file must be a mapping with an airflow key")
+		return
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != "airflow" {
+			v.addIssue(key, "unknown key %q", key.Value)
+			continue
+		}
+		v.validateAirflow(value)
+	}
+}
+
+func (v *validator) validateAirflow(node *yaml.Node) {
+	if isNull(node) {
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		v.addIssue(node, "airflow must be a mapping")
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value == "secrets_backend" {
+			if !isNull(value) {
+				v.validateObject(value, secretsBackendSchema)
+			}
+			continue
+		}
+		schema, ok := objectListSchemas[key.Value]
+		if !ok {
+			v.addIssue(key, "unknown key %q in airflow", key.Value)
+			continue
+		}
+		v.validateObjectList(key.Value, value, schema)
+	}
+}
+
+func (v *validator) validateObjectList(name string, node *yaml.Node, schema objectSchema) {
+	if isNull(node) {
+		return
+	}
+	if node.Kind != yaml.SequenceNode {
+		v.addIssue(node, "%s must be a list", name)
+		return
+	}
+	ids := map[string]int{}
+	for _, item := range node.Content {
+		if isNull(item) {
+			continue
+		}
+		v.validateObject(item, schema)
+
+		id := mappingValue(item, schema.idKey)
+		if id == nil || isNull(id) || id.Kind != yaml.ScalarNode || id.Value == "" {
+			continue
+		}
+		if line, ok := ids[id.Value]; ok {
+			v.addIssue(id, "duplicate %s %q, first defined on line %d", schema.idKey, id.Value, line)
+			continue
+		}
+		ids[id.Value] = id.Line
+	}
+}
+
+func (v *validator) validateObject(node *yaml.Node, schema objectSchema) {
+	if node.Kind != yaml.MappingNode {
+		v.addIssue(node, "each %s must be a mapping", schema.name)
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		fieldType, ok := schema.fields[key.Value]
+		if !ok {
+			v.addIssue(key, "unknown key %q in %s", key.Value, schema.name)
+			continue
+		}
+		if isNull(value) {
+			continue
+		}
+		switch fieldType {
+		case stringField:
+			if value.Kind != yaml.ScalarNode {
+				v.addIssue(value, "%s must be a string", key.Value)
+			}
+		case intField:
+			if value.Kind != yaml.ScalarNode {
+				v.addIssue(value, "%s must be an integer", key.Value)
+			} else if _, err := strconv.Atoi(value.Value); err != nil {
+				v.addIssue(value, "%s must be an integer, got %q", key.Value, value.Value)
+			}
+		case extraField:
+			if value.Kind != yaml.ScalarNode && value.Kind != yaml.MappingNode {
+				v.addIssue(value, "%s must be a mapping or a JSON string", key.Value)
+			}
+		}
+		if key.Value == "conn_uri" && value.Kind == yaml.ScalarNode && !IsEncrypted(value.Value) {
+			if err := validateConnectionURI(value.Value); err != nil {
+				v.addIssue(value, "invalid conn_uri: %s", err.Error())
+			}
+		}
+	}
+}
+
+func validateConnectionURI(uri string) error {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return errors.Unwrap(err)
+	}
+	if parsed.Scheme == "" {
+		return errors.New("missing connection type, e.g. postgres://")
+	}
+	return nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
@@ -0,0 +1,66 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func (s *Suite) TestValidateSettingsFile() {
+	origWorkingPath := WorkingPath
+	defer func() { WorkingPath = origWorkingPath }()
+
+	s.Run("valid file", func() {
+		WorkingPath = "./testfiles/"
+		err := ValidateSettingsFile("airflow_settings.yaml")
+		s.NoError(err)
+	})
+
+	s.Run("empty objects", func() {
+		WorkingPath = s.T().TempDir()
+		content := "airflow:\n  connections:\n    - conn_id:\n      conn_type:\n      conn_port:\n  pools:\n    - pool_name:\n      pool_slot:\n  variables:\n"
+		s.NoError(os.WriteFile(filepath.Join(WorkingPath, "airflow_settings.yaml"), []byte(content), 0o644))
+
+		err := ValidateSettingsFile("airflow_settings.yaml")
+		s.NoError(err)
+	})
+
+	s.Run("invalid file", func() {
+		WorkingPath = s.T().TempDir()
+		content := `airflow:
+  connections:
+    - conn_id: test-conn
+      conn_hots: localhost
+      conn_port: 'testing'
+    - conn_id: test-conn
+      conn_uri: '//localhost:5432/db'
+  pools:
+    - pool_name: test-pool
+      pool_slot: [1]
+  variables: test
+  dags: []
+`
+		s.NoError(os.WriteFile(filepath.Join(WorkingPath, "airflow_settings.yaml"), []byte(content), 0o644))
+
+		err := ValidateSettingsFile("airflow_settings.yaml")
+		var validationErr *ValidationError
+		s.ErrorAs(err, &validationErr)
+		s.Equal([]ValidationIssue{
+			{Line: 4, Message: `unknown key "conn_hots" in connection`},
+			{Line: 5, Message: `conn_port must be an integer, got "testing"`},
+			{Line: 6, Message: `duplicate conn_id "test-conn", first defined on line 3`},
+			{Line: 7, Message: `invalid conn_uri: missing connection type, e.g. postgres://`},
+			{Line: 10, Message: "pool_slot must be an integer"},
+			{Line: 11, Message: "variables must be a list"},
+			{Line: 12, Message: `unknown key "dags" in airflow`},
+		}, validationErr.Issues)
+		s.Contains(err.Error(), "airflow_settings.yaml:4: unknown key \"conn_hots\" in connection")
+	})
+
+	s.Run("yaml syntax error", func() {
+		WorkingPath = s.T().TempDir()
+		s.NoError(os.WriteFile(filepath.Join(WorkingPath, "airflow_settings.yaml"), []byte("airflow:\n  connections: [\n"), 0o644))
+
+		err := ValidateSettingsFile("airflow_settings.yaml")
+		s.ErrorContains(err, "line")
+	})
+}